@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/tlser"
+)
+
+// Exit codes of the check command.
+const (
+	checkOK       = 0
+	checkExpiring = 1
+	checkExpired  = 2
+	checkError    = 3
+	checkUsage    = 4
+)
+
+// Exit codes of the plugin mode, following the monitoring-plugins guidelines.
+const (
+	pluginOK       = 0
+	pluginWarning  = 1
+	pluginCritical = 2
+	pluginUnknown  = 3
+)
+
+var pluginStates = map[int]string{
+	pluginOK:       "OK",
+	pluginWarning:  "WARNING",
+	pluginCritical: "CRITICAL",
+	pluginUnknown:  "UNKNOWN",
+}
+
+// pluginErrorCodes maps the errors of the probe to the exit code of the plugin mode.
+// The certificate is CRITICAL when the server presents a wrong one, but UNKNOWN when it couldn't
+// be fetched at all (DNS, timeout, refused connection), the guidelines keep that for failures of the check itself.
+// Errors missing here are UNKNOWN.
+var pluginErrorCodes = map[tlser.CertStatus]int{
+	tlser.StatusCannotConnect:    pluginUnknown,
+	tlser.StatusHostnameMismatch: pluginCritical,
+	tlser.StatusIssuerNotFound:   pluginCritical,
+}
+
+type checkResult struct {
+	Domain string `json:"domain"`
+	// Status is "ok", the expiration status the worker notifies ("expires soon", "expires today", "expired"),
+	// or "error" when the certificate couldn't be checked.
+	Status string `json:"status"`
+	// Error is the error the service stores for the domain, e.g. "CannotConnect", and Detail why it happened.
+	Error     string       `json:"error"`
+	Detail    string       `json:"detail"`
+	Issuer    string       `json:"issuer"`
+	ExpiresAt *time.Time   `json:"expires_at"`
+	HoursLeft *int         `json:"hours_left"`
+	Chain     []checkChain `json:"chain"`
+}
+
+type checkChain struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	DNSNames  []string  `json:"dns_names"`
+}
+
+// Check the certificate of a domain once, like the worker does
+func main() {
+	os.Exit(runCheck(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runCheck runs the probe of the worker once against a domain, without a database or Redis,
+// and returns the exit code for the status.
+func runCheck(args []string, stdout io.Writer, stderr io.Writer) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "text", "output format: text or json")
+	timeout := fs.Duration("timeout", 5*time.Second, "connection timeout")
+	plugin := fs.Bool("plugin", false, "Nagios/Icinga plugin mode")
+	warning := fs.Int("w", 30, "plugin mode: WARNING when fewer days are left")
+	critical := fs.Int("c", 7, "plugin mode: CRITICAL when fewer days are left")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: domainator-check [-o text|json] [-timeout 5s] example.com[:port]")
+		fmt.Fprintln(stderr, "       domainator-check -plugin [-w 30] [-c 7] [-timeout 5s] example.com[:port]")
+		fmt.Fprintln(stderr, "\nFlags can go before or after the domain.")
+		fmt.Fprintln(stderr, "\nExit codes: 0 ok, 1 expires soon or today, 2 expired, 3 error, 4 wrong usage.")
+		fmt.Fprintln(stderr, "In plugin mode: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN.")
+		fmt.Fprintln(stderr, "  OK        at least -w days left")
+		fmt.Fprintln(stderr, "  WARNING   fewer than -w days left")
+		fmt.Fprintln(stderr, "  CRITICAL  fewer than -c days left, expired, hostname mismatch or untrusted issuer")
+		fmt.Fprintln(stderr, "  UNKNOWN   can't connect (DNS, timeout, refused) or wrong arguments")
+		fs.PrintDefaults()
+	}
+
+	domains, err := parseArgs(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return checkOK
+	}
+
+	usageCode := checkUsage
+	if *plugin {
+		usageCode = pluginUnknown
+	}
+	if err != nil {
+		return usageCode
+	}
+	if len(domains) != 1 {
+		fmt.Fprintf(stderr, "expected one domain, got %d: %s\n", len(domains), strings.Join(domains, " "))
+		fs.Usage()
+		return usageCode
+	}
+	if *output != "text" && *output != "json" {
+		fs.Usage()
+		return usageCode
+	}
+	if *plugin && (*warning < 0 || *critical < 0) {
+		fmt.Fprintln(stdout, "UNKNOWN - the -w and -c thresholds can't be negative")
+		return pluginUnknown
+	}
+	if *plugin && *warning < *critical {
+		fmt.Fprintln(stdout, "UNKNOWN - the -w threshold must be greater or equal to the -c one")
+		return pluginUnknown
+	}
+
+	domain := strings.TrimSpace(domains[0])
+	data := tlser.New(*timeout).GetCertData(context.Background(), domain)
+	result := newCheckResult(domain, data)
+
+	if *plugin {
+		code, line := pluginOutput(result, *warning, *critical)
+		fmt.Fprintln(stdout, line)
+		return code
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(result)
+	} else {
+		writeCheckText(stdout, result)
+	}
+
+	switch result.Status {
+	case "ok":
+		return checkOK
+	case "expired":
+		return checkExpired
+	case "error":
+		return checkError
+	default:
+		return checkExpiring
+	}
+}
+
+// parseArgs parses the flags wherever they are, the flag package alone stops at the first positional
+// argument, and returns the positional ones. Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// newCheckResult turns the data of the probe into the status the service would store and notify.
+func newCheckResult(domain string, data tlser.CertData) checkResult {
+	result := checkResult{Domain: domain, Chain: []checkChain{}}
+	for _, c := range data.Chain {
+		result.Chain = append(result.Chain, checkChain(c))
+	}
+	if data.Err != nil {
+		result.Detail = data.Err.Error()
+	}
+
+	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
+		result.Status = "error"
+		result.Error = string(data.Status)
+		return result
+	}
+
+	hours := certs.HoursToExpiration(data.Expiry)
+	result.Issuer = data.Issuer
+	result.ExpiresAt = &data.Expiry
+	result.HoursLeft = &hours
+	result.Status = certs.ExpirationStatus(hours)
+	if result.Status == "" {
+		result.Status = "ok"
+	}
+
+	return result
+}
+
+func writeCheckText(w io.Writer, r checkResult) {
+	fmt.Fprintf(w, "%s: %s\n", r.Domain, strings.ToUpper(r.Status))
+	if r.Error != "" {
+		fmt.Fprintf(w, "Error:    %s\n", r.Error)
+	}
+	if r.Detail != "" {
+		fmt.Fprintf(w, "Detail:   %s\n", r.Detail)
+	}
+	if r.ExpiresAt != nil {
+		fmt.Fprintf(w, "Issuer:   %s\n", r.Issuer)
+		fmt.Fprintf(w, "Expires:  %s (%s)\n", r.ExpiresAt.UTC().Format(time.RFC3339), daysLeft(*r.HoursLeft))
+	}
+	if len(r.Chain) > 0 {
+		fmt.Fprintln(w, "Chain:")
+		for i, c := range r.Chain {
+			fmt.Fprintf(w, "  %d. %s\n", i, c.Subject)
+			fmt.Fprintf(w, "     issued by %s\n", c.Issuer)
+			fmt.Fprintf(w, "     valid %s to %s\n", c.NotBefore.UTC().Format(time.DateOnly), c.NotAfter.UTC().Format(time.DateOnly))
+			if len(c.DNSNames) > 0 {
+				fmt.Fprintf(w, "     names %s\n", strings.Join(c.DNSNames, ", "))
+			}
+		}
+	}
+}
+
+func daysLeft(hours int) string {
+	if hours <= 0 {
+		return "expired"
+	}
+	days := hours / 24
+	if days == 0 {
+		return fmt.Sprintf("%d hours left", hours)
+	}
+	return fmt.Sprintf("%d days left", days)
+}
+
+// pluginOutput returns the exit code and the line of output of the plugin mode, with the days left
+// as perfdata. Errors of the probe are mapped with pluginErrorCodes.
+func pluginOutput(r checkResult, warning int, critical int) (int, string) {
+	if r.Status == "error" {
+		code, ok := pluginErrorCodes[tlser.CertStatus(r.Error)]
+		if !ok {
+			code = pluginUnknown
+		}
+		msg := fmt.Sprintf("%s - %s: %s", pluginStates[code], r.Domain, r.Error)
+		if r.Detail != "" {
+			msg += " (" + r.Detail + ")"
+		}
+		return code, msg
+	}
+
+	days := int(math.Floor(float64(*r.HoursLeft) / 24))
+	code := pluginOK
+	switch {
+	case r.Status == "expired" || days < critical:
+		code = pluginCritical
+	case days < warning:
+		code = pluginWarning
+	}
+
+	var summary string
+	if r.Status == "expired" {
+		summary = fmt.Sprintf("certificate of %s expired on %s", r.Domain, r.ExpiresAt.UTC().Format(time.DateOnly))
+	} else {
+		summary = fmt.Sprintf("certificate of %s expires in %d days (%s), issued by %s", r.Domain, days, r.ExpiresAt.UTC().Format(time.DateOnly), r.Issuer)
+	}
+
+	perfdata := fmt.Sprintf("days_left=%d;%d;%d;;", days, warning, critical)
+	return code, fmt.Sprintf("%s - %s | %s", pluginStates[code], summary, perfdata)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/tlser"
+	"github.com/germandv/domainator/internal/tlsertest"
+)
+
+func TestNewCheckResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		data   tlser.CertData
+		status string
+	}{
+		{"ok", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(30 * 24 * time.Hour), Issuer: "R3"}, "ok"},
+		{"expires_soon", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(48 * time.Hour), Issuer: "R3"}, "expires soon"},
+		{"expires_today", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(5 * time.Hour), Issuer: "R3"}, "expires today"},
+		{"expired", tlser.CertData{Status: tlser.StatusExpired, Expiry: time.Now().Add(-24 * time.Hour)}, "expired"},
+		{"error", tlser.CertData{Status: tlser.StatusCannotConnect, Err: errors.New("refused")}, "error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newCheckResult("example.com", tc.data)
+			if r.Status != tc.status {
+				t.Errorf("Expected status %q, got %q", tc.status, r.Status)
+			}
+		})
+	}
+}
+
+func TestPluginOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     tlser.CertData
+		code     int
+		state    string
+		perfdata string
+	}{
+		{"ok", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(45*24*time.Hour + time.Hour), Issuer: "R3"}, pluginOK, "OK", "days_left=45;30;7;;"},
+		{"warning", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(20*24*time.Hour + time.Hour), Issuer: "R3"}, pluginWarning, "WARNING", "days_left=20;30;7;;"},
+		{"critical", tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(3*24*time.Hour + time.Hour), Issuer: "R3"}, pluginCritical, "CRITICAL", "days_left=3;30;7;;"},
+		{"expired", tlser.CertData{Status: tlser.StatusExpired, Expiry: time.Now().Add(-36 * time.Hour)}, pluginCritical, "CRITICAL", "days_left=-2;30;7;;"},
+		{"cannot_connect", tlser.CertData{Status: tlser.StatusCannotConnect, Err: errors.New("i/o timeout")}, pluginUnknown, "UNKNOWN", ""},
+		{"hostname_mismatch", tlser.CertData{Status: tlser.StatusHostnameMismatch}, pluginCritical, "CRITICAL", ""},
+		{"issuer_not_found", tlser.CertData{Status: tlser.StatusIssuerNotFound}, pluginCritical, "CRITICAL", ""},
+		{"unexpected_error", tlser.CertData{Status: "Unexpected"}, pluginUnknown, "UNKNOWN", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, line := pluginOutput(newCheckResult("example.com", tc.data), 30, 7)
+			if code != tc.code {
+				t.Errorf("Expected exit code %d, got %d", tc.code, code)
+			}
+			if !strings.HasPrefix(line, tc.state+" - ") {
+				t.Errorf("Expected the line to start with %s, got %q", tc.state, line)
+			}
+			if tc.perfdata != "" && !strings.HasSuffix(line, " | "+tc.perfdata) {
+				t.Errorf("Expected perfdata %q, got %q", tc.perfdata, line)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		output     string
+	}{
+		{"flags_first", []string{"-o", "json", "example.com"}, []string{"example.com"}, "json"},
+		{"flags_last", []string{"example.com", "-o", "json"}, []string{"example.com"}, "json"},
+		{"flags_around", []string{"-o", "json", "example.com", "extra.com"}, []string{"example.com", "extra.com"}, "json"},
+		{"terminator", []string{"--", "example.com", "-o", "json"}, []string{"example.com", "-o", "json"}, "text"},
+		{"none", []string{"-o", "json"}, []string{}, "json"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("check", flag.ContinueOnError)
+			output := fs.String("o", "text", "")
+			positional, err := parseArgs(fs, tc.args)
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if strings.Join(positional, " ") != strings.Join(tc.positional, " ") {
+				t.Errorf("Expected positional arguments %q, got %q", tc.positional, positional)
+			}
+			if *output != tc.output {
+				t.Errorf("Expected output %q, got %q", tc.output, *output)
+			}
+		})
+	}
+}
+
+func TestRunCheckUsage(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr strings.Builder
+	code := runCheck([]string{"example.com", "-o", "xml"}, &stdout, &stderr)
+	if code != checkUsage {
+		t.Errorf("Expected exit code %d, got %d", checkUsage, code)
+	}
+
+	stderr.Reset()
+	code = runCheck([]string{"example.com", "extra.com"}, &stdout, &stderr)
+	if code != checkUsage {
+		t.Errorf("Expected exit code %d, got %d", checkUsage, code)
+	}
+	if !strings.Contains(stderr.String(), "expected one domain, got 2") {
+		t.Errorf("Expected a message about the domains, got %q", stderr.String())
+	}
+}
+
+func TestRunCheckThresholds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		line string
+	}{
+		{"negative_warning", []string{"-plugin", "-w", "-1", "-c", "0", "example.com"}, "can't be negative"},
+		{"negative_critical", []string{"-plugin", "-w", "30", "-c", "-1", "example.com"}, "can't be negative"},
+		{"warning_below_critical", []string{"-plugin", "-w", "5", "-c", "7", "example.com"}, "greater or equal"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr strings.Builder
+			code := runCheck(tc.args, &stdout, &stderr)
+			if code != pluginUnknown {
+				t.Errorf("Expected exit code %d, got %d", pluginUnknown, code)
+			}
+			if !strings.HasPrefix(stdout.String(), "UNKNOWN - ") || !strings.Contains(stdout.String(), tc.line) {
+				t.Errorf("Expected an UNKNOWN line about %q, got %q", tc.line, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunCheckPluginTLS(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	expired, _ := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	untrusted, _ := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+	mismatch, _ := tlsertest.NewServer(t, []string{"example.com"}, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+
+	tests := []struct {
+		name   string
+		domain string
+		error  string
+	}{
+		{"expired", expired, "expired on"},
+		{"untrusted", untrusted, string(tlser.StatusIssuerNotFound)},
+		{"hostname_mismatch", mismatch, string(tlser.StatusHostnameMismatch)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr strings.Builder
+			code := runCheck([]string{"-plugin", tc.domain}, &stdout, &stderr)
+			if code != pluginCritical {
+				t.Errorf("Expected exit code %d, got %d: %s", pluginCritical, code, stdout.String())
+			}
+			if !strings.HasPrefix(stdout.String(), "CRITICAL - ") || !strings.Contains(stdout.String(), tc.error) {
+				t.Errorf("Expected a CRITICAL line about %q, got %q", tc.error, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunCheckTLS(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	expired, _ := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	untrusted, _ := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+
+	tests := []struct {
+		name   string
+		domain string
+		code   int
+		status string
+		error  string
+	}{
+		{"expired", expired, checkExpired, "expired", ""},
+		{"untrusted", untrusted, checkError, "error", string(tlser.StatusIssuerNotFound)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr strings.Builder
+			code := runCheck([]string{tc.domain, "-o", "json"}, &stdout, &stderr)
+			if code != tc.code {
+				t.Errorf("Expected exit code %d, got %d: %s", tc.code, code, stdout.String())
+			}
+
+			var result checkResult
+			err := json.Unmarshal([]byte(stdout.String()), &result)
+			if err != nil {
+				t.Fatalf("Expected JSON output, got %q: %s", stdout.String(), err)
+			}
+			if result.Status != tc.status || result.Error != tc.error {
+				t.Errorf("Expected status %q and error %q, got %q and %q", tc.status, tc.error, result.Status, result.Error)
+			}
+			if len(result.Chain) != 1 {
+				t.Errorf("Expected the chain the server sent, got %v", result.Chain)
+			}
+		})
+	}
+}
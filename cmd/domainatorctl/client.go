@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Domain and Settings mirror the schemas of the same name in the OpenAPI document of the API.
+type Domain struct {
+	ID           string     `json:"id"`
+	Domain       string     `json:"domain"`
+	Status       string     `json:"status"`
+	Issuer       string     `json:"issuer"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	Error        string     `json:"error"`
+	Checking     bool       `json:"checking"`
+	Tags         []string   `json:"tags"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CheckedAt    *time.Time `json:"checked_at"`
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+}
+
+type Channel struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type Settings struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Timezone string    `json:"timezone"`
+	Channels []Channel `json:"channels"`
+}
+
+// APIError is an error response of the API.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.Status, http.StatusText(e.Status))
+}
+
+var errNotFound = errors.New("not found")
+
+// Client talks to the JSON API of a Domainator server.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		baseURL: cfg.URL + "/api/v1",
+		token:   cfg.Token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) ListDomains() ([]Domain, error) {
+	resp := struct {
+		Domains []Domain `json:"domains"`
+	}{}
+	err := c.do(http.MethodGet, "/domains", nil, &resp)
+	return resp.Domains, err
+}
+
+func (c *Client) AddDomain(domain string, tags []string) (Domain, error) {
+	req := struct {
+		Domain string   `json:"domain"`
+		Tags   []string `json:"tags,omitempty"`
+	}{Domain: domain, Tags: tags}
+
+	d := Domain{}
+	err := c.do(http.MethodPost, "/domains", req, &d)
+	return d, err
+}
+
+func (c *Client) RefreshDomain(id string) (Domain, error) {
+	d := Domain{}
+	err := c.do(http.MethodPost, "/domains/"+url.PathEscape(id)+"/refresh", nil, &d)
+	return d, err
+}
+
+func (c *Client) DeleteDomain(id string) error {
+	return c.do(http.MethodDelete, "/domains/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *Client) GetSettings() (Settings, error) {
+	s := Settings{}
+	err := c.do(http.MethodGet, "/settings", nil, &s)
+	return s, err
+}
+
+func (c *Client) TestChannel(id string) error {
+	return c.do(http.MethodPost, "/channels/"+url.PathEscape(id)+"/test", nil, nil)
+}
+
+// FindDomain returns the domain with the given ID or name.
+func (c *Client) FindDomain(idOrName string) (Domain, error) {
+	domains, err := c.ListDomains()
+	if err != nil {
+		return Domain{}, err
+	}
+
+	for _, d := range domains {
+		if d.ID == idOrName || strings.EqualFold(d.Domain, idOrName) {
+			return d, nil
+		}
+	}
+
+	return Domain{}, fmt.Errorf("domain %q %w", idOrName, errNotFound)
+}
+
+// FindChannel returns the channel with the given ID or name.
+func (c *Client) FindChannel(idOrName string) (Channel, error) {
+	s, err := c.GetSettings()
+	if err != nil {
+		return Channel{}, err
+	}
+
+	for _, ch := range s.Channels {
+		if ch.ID == idOrName || strings.EqualFold(ch.Name, idOrName) {
+			return ch, nil
+		}
+	}
+
+	return Channel{}, fmt.Errorf("channel %q %w", idOrName, errNotFound)
+}
+
+// do sends the request with body as JSON and decodes the response into out, when not nil.
+func (c *Client) do(method string, path string, body any, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "domainatorctl")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		apiErr := struct {
+			Error string `json:"error"`
+		}{}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = "request failed"
+		}
+		return APIError{Status: resp.StatusCode, Message: apiErr.Error}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config is where the API is and the personal access token to use it,
+// DOMAINATOR_URL and DOMAINATOR_TOKEN take precedence over the file.
+type Config struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+var errNotLoggedIn = errors.New("no API token, run `domainatorctl login` or set DOMAINATOR_TOKEN")
+
+// defaultConfigPath is domainator/config.json in the user's config directory, e.g. ~/.config on Linux.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "domainator.json"
+	}
+	return filepath.Join(dir, "domainator", "config.json")
+}
+
+func loadConfig(path string) (Config, error) {
+	cfg := Config{}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(b, &cfg)
+		if err != nil {
+			return cfg, fmt.Errorf("parsing config %s: %w", path, err)
+		}
+	}
+
+	if url := os.Getenv("DOMAINATOR_URL"); url != "" {
+		cfg.URL = url
+	}
+	if token := os.Getenv("DOMAINATOR_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+	cfg.URL = strings.TrimSuffix(strings.TrimSpace(cfg.URL), "/")
+	cfg.Token = strings.TrimSpace(cfg.Token)
+
+	if cfg.Token == "" {
+		return cfg, errNotLoggedIn
+	}
+	if cfg.URL == "" {
+		return cfg, errors.New("no server URL, run `domainatorctl login` or set DOMAINATOR_URL")
+	}
+
+	return cfg, nil
+}
+
+// saveConfig writes the config only readable by the user, since it has the token.
+func saveConfig(path string, cfg Config) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, append(b, '\n'), 0o600)
+	if err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	// WriteFile keeps the permissions of an existing file.
+	return os.Chmod(path, 0o600)
+}
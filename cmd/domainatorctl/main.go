@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const usage = `domainatorctl manages your Domainator domains through the API.
+
+Usage:
+  domainatorctl login -url https://domainator.example.com [-token dmn_...]
+  domainatorctl domains list
+  domainatorctl domains add example.com [-tags prod,web]
+  domainatorctl domains rm <id|domain>
+  domainatorctl domains refresh <id|domain>
+  domainatorctl channels list
+  domainatorctl channels test <id|name>
+
+Flags, before or after the command:
+  -o table|json|yaml  output format (default table)
+  -config path        config file (default %s)
+
+DOMAINATOR_URL and DOMAINATOR_TOKEN override the config file.
+`
+
+// options are the flags every command accepts.
+type options struct {
+	format     string
+	configPath string
+}
+
+type command struct {
+	opts   options
+	stdout io.Writer
+	stdin  io.Reader
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("see domainatorctl -h")
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := options{format: formatTable, configPath: defaultConfigPath()}
+	fs := newFlagSet("domainatorctl", &opts)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cmd := &command{opts: opts, stdout: stdout, stdin: stdin}
+	switch rest[0] {
+	case "login":
+		return cmd.login(rest[1:])
+	case "domains":
+		return cmd.dispatch(rest[1:], map[string]func([]string) error{
+			"list":    cmd.listDomains,
+			"add":     cmd.addDomain,
+			"rm":      cmd.removeDomain,
+			"refresh": cmd.refreshDomain,
+		})
+	case "channels":
+		return cmd.dispatch(rest[1:], map[string]func([]string) error{
+			"list": cmd.listChannels,
+			"test": cmd.testChannel,
+		})
+	default:
+		return fmt.Errorf("unknown command %q: %w", rest[0], errUsage)
+	}
+}
+
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&opts.format, "o", opts.format, "output format: table, json or yaml")
+	fs.StringVar(&opts.configPath, "config", opts.configPath, "config file")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), usage, defaultConfigPath())
+	}
+	return fs
+}
+
+func (c *command) dispatch(args []string, verbs map[string]func([]string) error) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: %w", errUsage)
+	}
+
+	verb, found := verbs[args[0]]
+	if !found {
+		return fmt.Errorf("unknown subcommand %q: %w", args[0], errUsage)
+	}
+
+	return verb(args[1:])
+}
+
+// parse parses the flags of a subcommand and checks it got the number of arguments it needs.
+func (c *command) parse(fs *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = parseFormat(c.opts.format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flags can also follow the arguments, e.g. `domains add example.com -tags prod`.
+	positional := []string{}
+	rest := fs.Args()
+	for len(rest) > 0 {
+		positional = append(positional, rest[0])
+		err = fs.Parse(rest[1:])
+		if err != nil {
+			return nil, err
+		}
+		rest = fs.Args()
+	}
+
+	if len(positional) != nArgs {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d: %w", fs.Name(), nArgs, len(positional), errUsage)
+	}
+
+	return positional, nil
+}
+
+func (c *command) client() (*Client, error) {
+	cfg, err := loadConfig(c.opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(cfg), nil
+}
+
+func (c *command) login(args []string) error {
+	fs := newFlagSet("login", &c.opts)
+	url := fs.String("url", "", "URL of the Domainator server")
+	token := fs.String("token", "", "personal access token, read from stdin when empty")
+	_, err := c.parse(fs, args, 0)
+	if err != nil {
+		return err
+	}
+
+	if *url == "" {
+		return fmt.Errorf("missing -url: %w", errUsage)
+	}
+
+	if *token == "" {
+		fmt.Fprint(c.stdout, "Token: ")
+		line, err := bufio.NewReader(c.stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		*token = line
+	}
+
+	cfg := Config{URL: strings.TrimSuffix(strings.TrimSpace(*url), "/"), Token: strings.TrimSpace(*token)}
+	if cfg.Token == "" {
+		return errNotLoggedIn
+	}
+
+	err = saveConfig(c.opts.configPath, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stdout, "Saved to", c.opts.configPath)
+	return nil
+}
+
+func (c *command) listDomains(args []string) error {
+	_, err := c.parse(newFlagSet("domains list", &c.opts), args, 0)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	domains, err := client.ListDomains()
+	if err != nil {
+		return err
+	}
+
+	return printOutput(c.stdout, c.opts.format, domains, domainsTable(domains))
+}
+
+func (c *command) addDomain(args []string) error {
+	fs := newFlagSet("domains add", &c.opts)
+	tags := fs.String("tags", "", "comma separated tags")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var tagList []string
+	for _, t := range strings.Split(*tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tagList = append(tagList, t)
+		}
+	}
+
+	d, err := client.AddDomain(positional[0], tagList)
+	if err != nil {
+		return err
+	}
+
+	return printOutput(c.stdout, c.opts.format, d, domainsTable([]Domain{d}))
+}
+
+func (c *command) removeDomain(args []string) error {
+	positional, err := c.parse(newFlagSet("domains rm", &c.opts), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	id, name, err := c.resolveDomain(client, positional[0])
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteDomain(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stdout, "Deleted", name)
+	return nil
+}
+
+func (c *command) refreshDomain(args []string) error {
+	positional, err := c.parse(newFlagSet("domains refresh", &c.opts), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	id, _, err := c.resolveDomain(client, positional[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := client.RefreshDomain(id)
+	if err != nil {
+		return err
+	}
+
+	return printOutput(c.stdout, c.opts.format, d, domainsTable([]Domain{d}))
+}
+
+func (c *command) listChannels(args []string) error {
+	_, err := c.parse(newFlagSet("channels list", &c.opts), args, 0)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	settings, err := client.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	t := table{headers: []string{"ID", "KIND", "NAME", "ENABLED"}}
+	for _, ch := range settings.Channels {
+		t.rows = append(t.rows, []string{ch.ID, ch.Kind, ch.Name, fmt.Sprint(ch.Enabled)})
+	}
+
+	return printOutput(c.stdout, c.opts.format, settings.Channels, t)
+}
+
+func (c *command) testChannel(args []string) error {
+	positional, err := c.parse(newFlagSet("channels test", &c.opts), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	// IDs are used as they are, so the token only needs the channels:test scope.
+	id, name := positional[0], positional[0]
+	if !isID(id) {
+		ch, err := client.FindChannel(positional[0])
+		if err != nil {
+			return err
+		}
+		id, name = ch.ID, ch.Name
+	}
+
+	err = client.TestChannel(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stdout, "Test message sent through", name)
+	return nil
+}
+
+// resolveDomain returns the ID and name of a domain given either of them.
+func (c *command) resolveDomain(client *Client, idOrName string) (string, string, error) {
+	if isID(idOrName) {
+		return idOrName, idOrName, nil
+	}
+
+	d, err := client.FindDomain(idOrName)
+	if err != nil {
+		return "", "", err
+	}
+
+	return d.ID, d.Domain, nil
+}
+
+func isID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
+
+func domainsTable(domains []Domain) table {
+	t := table{headers: []string{"ID", "DOMAIN", "STATUS", "EXPIRES", "ISSUER", "TAGS"}}
+	for _, d := range domains {
+		expires := "-"
+		if d.ExpiresAt != nil {
+			expires = d.ExpiresAt.Local().Format(time.DateOnly)
+		}
+		status := d.Status
+		if d.Checking {
+			status += " (checking)"
+		}
+		t.rows = append(t.rows, []string{d.ID, d.Domain, status, expires, d.Issuer, strings.Join(d.Tags, ",")})
+	}
+	return t
+}
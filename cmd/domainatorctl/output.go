@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// table is how a value is shown as a table.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func parseFormat(format string) (string, error) {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, use table, json or yaml", format)
+	}
+}
+
+// printOutput writes v in the format, as the table t for the table format.
+func printOutput(w io.Writer, format string, v any, t table) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		_, err := io.WriteString(w, toYAML(v))
+		return err
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// toYAML encodes v as YAML, using the json tags of its fields as keys in the order they are declared.
+// It covers what the API returns: structs, slices, strings, numbers, booleans and times.
+func toYAML(v any) string {
+	rv := reflect.ValueOf(v)
+	if s, ok := yamlScalar(rv); ok {
+		return s + "\n"
+	}
+	return strings.Join(yamlLines(rv), "\n") + "\n"
+}
+
+func yamlLines(v reflect.Value) []string {
+	v = reflect.Indirect(v)
+	lines := []string{}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			fv := v.Field(i)
+			if s, ok := yamlScalar(fv); ok {
+				lines = append(lines, name+": "+s)
+				continue
+			}
+			lines = append(lines, name+":")
+			for _, l := range yamlLines(fv) {
+				lines = append(lines, "  "+l)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if s, ok := yamlScalar(item); ok {
+				lines = append(lines, "- "+s)
+				continue
+			}
+			for j, l := range yamlLines(item) {
+				if j == 0 {
+					lines = append(lines, "- "+l)
+				} else {
+					lines = append(lines, "  "+l)
+				}
+			}
+		}
+	}
+
+	return lines
+}
+
+// yamlScalar returns v as a scalar, or false when it's a struct or a non-empty slice.
+func yamlScalar(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null", true
+		}
+		return yamlScalar(v.Elem())
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339), true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return yamlString(v.String()), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return "[]", true
+		}
+	}
+
+	return "", false
+}
+
+// yamlString quotes s when it would be read as something else than that string.
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") || strings.ContainsAny(s[:1], "-?") {
+		return strconv.Quote(s)
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+
+	_, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
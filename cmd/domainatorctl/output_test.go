@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestToYAML(t *testing.T) {
+	t.Parallel()
+
+	expires := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	domains := []Domain{
+		{
+			ID:        "018ec52b-dd69-7df4-b8e7-edcdc9a3a891",
+			Domain:    "example.com",
+			Status:    "ok",
+			Issuer:    "Let's Encrypt",
+			ExpiresAt: &expires,
+			Tags:      []string{"prod", "123"},
+			CreatedAt: expires,
+		},
+	}
+
+	want := `- id: 018ec52b-dd69-7df4-b8e7-edcdc9a3a891
+  domain: example.com
+  status: ok
+  issuer: "Let's Encrypt"
+  expires_at: 2025-01-02T03:04:05Z
+  error: ""
+  checking: false
+  tags:
+    - prod
+    - "123"
+  created_at: 2025-01-02T03:04:05Z
+  checked_at: null
+  snoozed_until: null
+`
+
+	got := toYAML(domains)
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"example.com", "example.com"},
+		{"", `""`},
+		{"true", `"true"`},
+		{"No", `"No"`},
+		{"42", `"42"`},
+		{"- item", `"- item"`},
+		{"key: value", `"key: value"`},
+		{" padded", `" padded"`},
+		{"multi\nline", `"multi\nline"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+
+			got := yamlString(tc.value)
+			if got != tc.want {
+				t.Errorf("Expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	err := printOutput(buf, formatTable, nil, table{
+		headers: []string{"ID", "DOMAIN"},
+		rows:    [][]string{{"1", "example.com"}, {"22", "example.org"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "ID  DOMAIN\n1   example.com\n22  example.org\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
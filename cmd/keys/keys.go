@@ -1,13 +1,27 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
 	"github.com/germandv/domainator/internal/keys"
 )
 
-// Generate key-pair
+// Generate key-pair, or VAPID keys for Web Push with -vapid
 func main() {
+	vapid := flag.Bool("vapid", false, "generate VAPID keys for Web Push")
+	flag.Parse()
+
+	if *vapid {
+		priv, publ, err := keys.NewVAPID()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("VAPID_PRIVATE_KEY=%s\n", priv)
+		fmt.Printf("# public key, derived from the private one: %s\n", publ)
+		return
+	}
+
 	priv, publ, err := keys.NewPair()
 	if err != nil {
 		panic(err)
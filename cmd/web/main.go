@@ -8,20 +8,33 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/germandv/domainator/internal/apitokens"
 	"github.com/germandv/domainator/internal/cache"
 	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
 	"github.com/germandv/domainator/internal/common"
 	"github.com/germandv/domainator/internal/db"
+	"github.com/germandv/domainator/internal/escalation"
 	"github.com/germandv/domainator/internal/githubauth"
 	"github.com/germandv/domainator/internal/handlers"
 	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/openapi"
+	"github.com/germandv/domainator/internal/outbox"
+	"github.com/germandv/domainator/internal/push"
+	"github.com/germandv/domainator/internal/signer"
+	"github.com/germandv/domainator/internal/slackapp"
 	"github.com/germandv/domainator/internal/tlser"
 	"github.com/germandv/domainator/internal/tokenauth"
+	"github.com/germandv/domainator/internal/tracing"
+	"github.com/germandv/domainator/internal/updates"
 	"github.com/germandv/domainator/internal/users"
+	"github.com/germandv/domainator/internal/webpush"
 	"github.com/germandv/domainator/ui"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type AppConfig struct {
@@ -37,8 +50,25 @@ type AppConfig struct {
 	PostgresConnStr string `env:"POSTGRES_CONN_STR"`
 	GithubClientID  string `env:"GITHUB_CLIENT_ID"`
 	GithubSecret    string `env:"GITHUB_SECRET"`
+	SlackClientID   string `env:"SLACK_CLIENT_ID" default:" "`
+	SlackSecret     string `env:"SLACK_SECRET" default:" "`
+	SlackSigningKey string `env:"SLACK_SIGNING_SECRET" default:" "`
 	Host            string `env:"HOST" default:"http://localhost"`
 	CookieSecret    string `env:"COOKIE_SECRET"`
+	LinkSecret      string `env:"LINK_SECRET"`
+	SMTPHost        string `env:"SMTP_HOST" default:"localhost"`
+	SMTPPort        int    `env:"SMTP_PORT" default:"1025"`
+	SMTPUsername    string `env:"SMTP_USERNAME" default:" "`
+	SMTPPassword    string `env:"SMTP_PASSWORD" default:" "`
+	SMTPFrom        string `env:"SMTP_FROM" default:"Domainator <noreply@localhost>"`
+	SMTPTLSMode     string `env:"SMTP_TLS" default:"none"`
+	PagerDutyURL    string `env:"PAGERDUTY_URL" default:"https://events.pagerduty.com"`
+	OpsgenieURL     string `env:"OPSGENIE_URL" default:"https://api.opsgenie.com"`
+	VAPIDPrivKey    string `env:"VAPID_PRIVATE_KEY" default:" "`
+	VAPIDSubject    string `env:"VAPID_SUBJECT" default:"mailto:admin@localhost"`
+	MetricsToken    string `env:"METRICS_TOKEN" default:" "`
+	OTLPEndpoint    string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" default:" "`
+	ProbeWorkers    int    `env:"PROBE_WORKERS" default:"4"`
 }
 
 func main() {
@@ -52,6 +82,11 @@ func main() {
 		panic(err)
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "domainator-web", strings.TrimSpace(config.OTLPEndpoint))
+	if err != nil {
+		panic(err)
+	}
+
 	db, err := db.InitWithConnStr(config.PostgresConnStr)
 	if err != nil {
 		panic(err)
@@ -62,8 +97,41 @@ func main() {
 	certsRepo := certs.NewRepo(db)
 	cacheClient := cache.New(config.RedisHost, config.RedisPort, config.RedisPassword)
 	tlsClient := tlser.New(5 * time.Second)
-	certsService := certs.NewService(tlsClient, certsRepo, 10)
-	slacker := notifier.NewSlacker()
+	broker := updates.NewBroker(cacheClient)
+	certsService := certs.NewService(tlsClient, certsRepo, broker, 10)
+	channelsRepo := channels.NewRepo(db)
+	channelsService := channels.NewService(channelsRepo, 10)
+	linkSigner := signer.New([]byte(config.LinkSecret))
+	appURL := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	emailer, err := notifier.NewEmailer(notifier.SMTPConfig{
+		Host:     config.SMTPHost,
+		Port:     config.SMTPPort,
+		Username: strings.TrimSpace(config.SMTPUsername),
+		Password: strings.TrimSpace(config.SMTPPassword),
+		From:     config.SMTPFrom,
+		TLSMode:  config.SMTPTLSMode,
+	}, appURL, linkSigner)
+	if err != nil {
+		panic(err)
+	}
+
+	vapid, err := webpush.NewVAPID(config.VAPIDPrivKey, config.VAPIDSubject)
+	if err != nil {
+		panic(err)
+	}
+	tokensService := apitokens.NewService(apitokens.NewRepo(db), 10)
+	pushRepo := push.NewRepo(db)
+	pushService := push.NewService(pushRepo, 10)
+
+	notifiers := channels.Notifiers{
+		AppURL:   appURL,
+		Emailer:  emailer,
+		Incident: notifier.IncidentConfig{PagerDutyURL: config.PagerDutyURL, OpsgenieURL: config.OpsgenieURL},
+		Threads:  channelsRepo,
+		WebPush:  notifier.NewWebPushNotifier(vapid, push.NewStore(pushRepo), appURL),
+	}
+	outboxService := outbox.NewService(outbox.NewRepo(db), channelsService, usersService, notifiers)
+	escalationService := escalation.NewService(escalation.NewRepo(db), outboxService, linkSigner, appURL)
 
 	authService, err := tokenauth.New(config.AuthPrivKey, config.AuthPublKey)
 	if err != nil {
@@ -71,39 +139,123 @@ func main() {
 	}
 	authn := handlers.AuthMdwBuilder(authService, false)
 	authz := handlers.AuthMdwBuilder(authService, true)
+	apiAuthz := func(scope apitokens.Scope) func(next http.Handler) http.Handler {
+		return handlers.APIAuthMdwBuilder(tokensService, scope)
+	}
 
 	githubCfg := githubauth.NewGithubConfig(
 		config.GithubClientID,
 		config.GithubSecret,
-		fmt.Sprintf("%s:%d/github/callback", config.Host, config.Port),
+		appURL+"/github/callback",
 	)
+	slackCfg := slackapp.NewSlackConfig(config.SlackClientID, config.SlackSecret, config.SlackSigningKey, appURL+"/slack/callback")
+
+	registry := prometheus.NewRegistry()
+	metricsMiddleware := handlers.MetricsMdwBuilder(registry)
+	tracingMiddleware := handlers.TracingMdwBuilder()
 
 	mux := http.NewServeMux()
 	mux.Handle("GET /static/*", http.StripPrefix("/static/", ui.CreateFileServer()))
 	mux.HandleFunc("GET /healthcheck", handlers.GetHealthcheck(cacheClient, db))
+	metricsToken := strings.TrimSpace(config.MetricsToken)
+	if metricsToken != "" {
+		mux.HandleFunc("GET /metrics", handlers.GetMetrics(logger, registry, certsService, metricsToken))
+	} else {
+		logger.Info("METRICS_TOKEN isn't set, /metrics is disabled")
+	}
 	mux.Handle("GET /", authn(handlers.GetLanding()))
-	mux.Handle("GET /dashboard", authn(handlers.GetDashboard(certsService)))
+	mux.Handle("GET /dashboard", authn(handlers.GetDashboard(certsService, usersService)))
+	mux.Handle("GET /dashboard/events", authz(handlers.DashboardEvents(logger, broker, certsService, usersService)))
 	mux.Handle("GET /github/login", authn(handlers.GithubLogin(logger, githubCfg, []byte(config.CookieSecret))))
 	mux.HandleFunc("GET /github/callback", handlers.GithubCallback(logger, githubCfg, authService, usersService, []byte(config.CookieSecret)))
 	mux.HandleFunc("POST /logout", handlers.Logout())
-	mux.Handle("POST /domain", authz(handlers.RegisterDomain(logger, certsService)))
-	mux.Handle("PUT /domain/{id}", authz(handlers.UpdateDomain(logger, certsService)))
+	mux.Handle("POST /domain", authz(handlers.RegisterDomain(logger, certsService, usersService)))
+	mux.Handle("PUT /domain/{id}", authz(handlers.UpdateDomain(logger, certsService, usersService)))
 	mux.Handle("DELETE /domain/{id}", authz(handlers.DeleteDomain(logger, certsService)))
-	mux.Handle("GET /settings", authz(handlers.GetSettings(usersService)))
-	mux.Handle("POST /settings/webhook", authz(handlers.SetWebhookURL(usersService)))
-	mux.Handle("PATCH /webhook/test", authz(handlers.SendTestMessage(logger, usersService, slacker)))
+	mux.Handle("PATCH /domain/{id}/tags", authz(handlers.SetDomainTags(logger, certsService, usersService)))
+	mux.Handle("GET /settings", authz(handlers.GetSettings(usersService, channelsService, tokensService, slackCfg, vapid, appURL)))
+	mux.Handle("PUT /settings/timezone", authz(handlers.SetTimezone(usersService)))
+	mux.Handle("PUT /settings/quiet-hours", authz(handlers.SetQuietHours(usersService)))
+	mux.Handle("PUT /settings/digest", authz(handlers.SetDigest(usersService, channelsService)))
+	mux.Handle("POST /settings/feed-token", authz(handlers.RotateFeedToken(usersService, appURL)))
+	mux.Handle("POST /settings/tokens", authz(handlers.CreateToken(logger, tokensService, usersService)))
+	mux.Handle("DELETE /settings/tokens/{id}", authz(handlers.RevokeToken(logger, tokensService)))
+	mux.Handle("GET /settings/deliveries", authz(handlers.GetDeliveries(outboxService)))
+	mux.Handle("POST /outbox/{id}/retry", authz(handlers.RetryMessage(logger, outboxService)))
+	mux.Handle("POST /channel", authz(handlers.CreateChannel(logger, channelsService, emailer)))
+	mux.Handle("POST /channel/{id}/confirmation", authz(handlers.ResendChannelConfirmation(logger, channelsService, emailer)))
+	mux.Handle("PUT /channel/{id}", authz(handlers.SetChannelEnabled(channelsService)))
+	mux.Handle("DELETE /channel/{id}", authz(handlers.DeleteChannel(logger, channelsService)))
+	mux.Handle("PATCH /channel/{id}/test", authz(handlers.SendChannelTest(logger, channelsService, notifiers)))
+	mux.Handle("POST /channel/{id}/secret", authz(handlers.RotateChannelSecret(channelsService)))
+	mux.Handle("GET /channel/{id}/slack-channels", authz(handlers.ListSlackChannels(logger, channelsService, slackCfg)))
+	mux.Handle("GET /slack/install", authz(handlers.SlackInstall(logger, slackCfg, []byte(config.CookieSecret))))
+	mux.Handle("GET /slack/callback", authz(handlers.SlackCallback(logger, slackCfg, channelsService, []byte(config.CookieSecret))))
+	mux.Handle("POST /push/subscription", authz(handlers.PushSubscribe(logger, pushService, channelsService)))
+	mux.Handle("DELETE /push/subscription", authz(handlers.PushUnsubscribe(logger, pushService)))
+	mux.Handle("POST /channel/{id}/rule", authz(handlers.AddRule(channelsService)))
+	mux.Handle("DELETE /channel/{id}/rule/{ruleID}", authz(handlers.DeleteRule(channelsService)))
+	mux.Handle("POST /channel/{id}/template", authz(handlers.SetTemplate(channelsService)))
+	mux.Handle("DELETE /channel/{id}/template/{event}", authz(handlers.DeleteTemplate(channelsService)))
+	mux.Handle("POST /template/preview", authz(handlers.PreviewTemplate(appURL)))
+	mux.Handle("GET /incidents", authz(handlers.GetIncidents(escalationService, channelsService, usersService)))
+	mux.Handle("POST /policy", authz(handlers.CreatePolicy(escalationService, channelsService)))
+	mux.Handle("DELETE /policy/{id}", authz(handlers.DeletePolicy(escalationService)))
+	mux.Handle("POST /policy/{id}/step", authz(handlers.AddPolicyStep(escalationService, channelsService)))
+	mux.Handle("DELETE /policy/{id}/step/{stepID}", authz(handlers.DeletePolicyStep(escalationService, channelsService)))
+	mux.HandleFunc("GET /api/v1/openapi.json", handlers.GetOpenAPI())
+	mux.Handle("GET /api/v1/domains", apiAuthz(apitokens.ScopeDomainsRead)(handlers.APIGetDomains(logger, certsService)))
+	mux.Handle("POST /api/v1/domains", apiAuthz(apitokens.ScopeDomainsWrite)(handlers.APICreateDomain(logger, certsService)))
+	mux.Handle("POST /api/v1/domains/{id}/refresh", apiAuthz(apitokens.ScopeDomainsWrite)(handlers.APIRefreshDomain(logger, certsService)))
+	mux.Handle("DELETE /api/v1/domains/{id}", apiAuthz(apitokens.ScopeDomainsWrite)(handlers.APIDeleteDomain(logger, certsService)))
+	mux.Handle("POST /api/v1/channels/{id}/test", apiAuthz(apitokens.ScopeChannelsTest)(handlers.APITestChannel(logger, channelsService, notifiers)))
+	mux.Handle("GET /api/v1/settings", apiAuthz(apitokens.ScopeSettingsRead)(handlers.APIGetSettings(logger, usersService, channelsService)))
+	mux.HandleFunc("GET /feeds/{token}/calendar.ics", handlers.GetCalendarFeed(logger, usersService, certsService, appURL))
+	mux.HandleFunc("GET /feeds/{token}/events.atom", handlers.GetEventsFeed(logger, usersService, certsService, appURL))
+	mux.HandleFunc("GET /incidents/ack", handlers.GetIncidentAck(linkSigner, escalationService))
+	mux.HandleFunc("POST /incidents/ack", handlers.AcknowledgeIncident(logger, linkSigner, escalationService))
+	mux.HandleFunc("POST /incidents/snooze", handlers.SnoozeIncident(logger, linkSigner, escalationService))
+	mux.Handle("GET /slack/link", authz(handlers.GetSlackLink(linkSigner)))
+	mux.Handle("POST /slack/link", authz(handlers.LinkSlack(logger, linkSigner, channelsService)))
+	mux.HandleFunc("POST /slack/commands", handlers.SlackCommand(logger, slackCfg, linkSigner, appURL, channelsService, certsService, usersService))
+	mux.HandleFunc("POST /slack/actions", handlers.SlackAction(logger, slackCfg, linkSigner, appURL, channelsService, certsService, usersService))
+	mux.HandleFunc("GET /unsubscribe", handlers.GetUnsubscribe(linkSigner))
+	mux.HandleFunc("POST /unsubscribe", handlers.Unsubscribe(logger, linkSigner, channelsService))
+	mux.HandleFunc("GET /channel/confirm", handlers.GetChannelConfirm(linkSigner))
+	mux.HandleFunc("POST /channel/confirm", handlers.ConfirmChannel(logger, linkSigner, channelsService))
 
 	addr := fmt.Sprintf(":%d", config.Port)
 	commonMiddleware := handlers.CommonMdwBuilder(logger, cacheClient)
+	var handler http.Handler = mux
+	if config.Env == "dev" || config.Env == "test" {
+		apiSpec, err := openapi.Load()
+		if err != nil {
+			panic(err)
+		}
+		handler = handlers.OpenAPIMdwBuilder(logger, apiSpec)(mux)
+	}
 	srv := &http.Server{
 		Addr:         addr,
 		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
-		Handler:      commonMiddleware(mux),
+		Handler:      tracingMiddleware(metricsMiddleware(commonMiddleware(handler))),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
 
+	// Stopping the broker on shutdown closes the event streams, which would keep the server waiting.
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	srv.RegisterOnShutdown(stopBroker)
+	go broker.Run(brokerCtx, logger)
+
+	// The probes are stopped before closing the DB, the ones already claimed are finished.
+	probesCtx, stopProbes := context.WithCancel(context.Background())
+	probesDone := make(chan struct{})
+	go func() {
+		defer close(probesDone)
+		certsService.RunProbes(probesCtx, config.ProbeWorkers, logger)
+	}()
+
 	killSig := make(chan os.Signal, 1)
 	signal.Notify(killSig, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
@@ -124,6 +276,8 @@ func main() {
 	// Gracefully shutdown
 	<-killSig
 	logger.Info("Shutting down server")
+	stopProbes()
+	<-probesDone
 	err = cacheClient.Close()
 	if err != nil {
 		logger.Error("Error closing redis", "err", err)
@@ -136,6 +290,10 @@ func main() {
 		cancel()
 		os.Exit(1)
 	}
+	err = shutdownTracing(ctx)
+	if err != nil {
+		logger.Error("Error flushing traces", "err", err)
+	}
 	cancel()
 	logger.Info("Server shutdown complete")
 }
@@ -5,15 +5,27 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/germandv/domainator/internal/cache"
 	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
 	"github.com/germandv/domainator/internal/common"
 	"github.com/germandv/domainator/internal/db"
+	"github.com/germandv/domainator/internal/digest"
+	"github.com/germandv/domainator/internal/escalation"
 	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/outbox"
+	"github.com/germandv/domainator/internal/push"
+	"github.com/germandv/domainator/internal/signer"
 	"github.com/germandv/domainator/internal/tlser"
+	"github.com/germandv/domainator/internal/tracing"
+	"github.com/germandv/domainator/internal/updates"
 	"github.com/germandv/domainator/internal/users"
+	"github.com/germandv/domainator/internal/webpush"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const CacheKey = "domainator_worker_running"
@@ -27,11 +39,27 @@ type WorkerConfig struct {
 	RedisHost       string `env:"REDIS_HOST" default:"localhost"`
 	RedisPort       int    `env:"REDIS_PORT" default:"6379"`
 	RedisPassword   string `env:"REDIS_PASSWORD" default:" "`
+	AppURL          string `env:"APP_URL" default:"http://localhost:4000"`
+	LinkSecret      string `env:"LINK_SECRET"`
+	SMTPHost        string `env:"SMTP_HOST" default:"localhost"`
+	SMTPPort        int    `env:"SMTP_PORT" default:"1025"`
+	SMTPUsername    string `env:"SMTP_USERNAME" default:" "`
+	SMTPPassword    string `env:"SMTP_PASSWORD" default:" "`
+	SMTPFrom        string `env:"SMTP_FROM" default:"Domainator <noreply@localhost>"`
+	SMTPTLSMode     string `env:"SMTP_TLS" default:"none"`
+	PagerDutyURL    string `env:"PAGERDUTY_URL" default:"https://events.pagerduty.com"`
+	OpsgenieURL     string `env:"OPSGENIE_URL" default:"https://api.opsgenie.com"`
+	VAPIDPrivKey    string `env:"VAPID_PRIVATE_KEY" default:" "`
+	VAPIDSubject    string `env:"VAPID_SUBJECT" default:"mailto:admin@localhost"`
+	PushgatewayURL  string `env:"PUSHGATEWAY_URL" default:" "`
+	OTLPEndpoint    string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" default:" "`
 }
 
 // This worker is meant to be run as a cron job,
 // it will check all the certificates in the database and update their details,
-// sending notifications for those that have expired or will expire soon.
+// queueing notifications for those that have expired or will expire soon,
+// the digests that are due and the escalations of unacknowledged incidents,
+// then delivering the notifications in the outbox.
 func main() {
 	config, err := common.GetConfig[WorkerConfig]()
 	if err != nil {
@@ -43,7 +71,20 @@ func main() {
 		panic(err)
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "domainator-worker", strings.TrimSpace(config.OTLPEndpoint))
+	if err != nil {
+		panic(err)
+	}
+
 	err = exclusiveRun(config, logger, run)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	e := shutdownTracing(ctx)
+	if e != nil {
+		logger.Error("Failed to flush traces", "error", e.Error())
+	}
+
 	if err != nil {
 		panic(err)
 	}
@@ -84,22 +125,73 @@ func exclusiveRun(
 	return err
 }
 
+// run processes everything and, with PUSHGATEWAY_URL set, pushes the stats of the run at the end.
+// The whole run is a trace, the probes and queries are its spans.
 func run(config *WorkerConfig, logger *slog.Logger) error {
-	logger.Info("Starting worker")
+	ctx, span := tracing.Start(context.Background(), "worker.run", trace.SpanKindInternal)
+	stats := newRunStats()
+	err := process(ctx, config, logger, stats)
+	tracing.End(span, err)
+
+	pushURL := strings.TrimSpace(config.PushgatewayURL)
+	if pushURL != "" {
+		pushErr := stats.push(context.Background(), pushURL, err)
+		if pushErr != nil {
+			logger.Error("Failed to push metrics", "error", pushErr.Error())
+		}
+	}
+
+	return err
+}
+
+func process(ctx context.Context, config *WorkerConfig, logger *slog.Logger, stats *runStats) error {
+	logger.InfoContext(ctx, "Starting worker")
 
 	db, err := db.InitWithConnStr(config.PostgresConnStr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %s", err)
 	}
 
+	cacheClient := cache.New(config.RedisHost, config.RedisPort, config.RedisPassword)
+	defer cacheClient.Close()
+
 	certsRepo := certs.NewRepo(db)
-	tlsClient := tlser.New(5 * time.Second)
-	certsService := certs.NewService(tlsClient, certsRepo, 10)
+	tlsClient := countingTLSer{Client: tlser.New(5 * time.Second), stats: stats}
+	certsService := certs.NewService(tlsClient, certsRepo, updates.NewBroker(cacheClient), 10)
 
-	usersRepo := users.NewRepo(db)
-	usersService := users.NewService(usersRepo)
+	channelsRepo := channels.NewRepo(db)
+	channelsService := channels.NewService(channelsRepo, 10)
 
-	slacker := notifier.NewSlacker()
+	linkSigner := signer.New([]byte(config.LinkSecret))
+	emailer, err := notifier.NewEmailer(notifier.SMTPConfig{
+		Host:     config.SMTPHost,
+		Port:     config.SMTPPort,
+		Username: strings.TrimSpace(config.SMTPUsername),
+		Password: strings.TrimSpace(config.SMTPPassword),
+		From:     config.SMTPFrom,
+		TLSMode:  config.SMTPTLSMode,
+	}, config.AppURL, linkSigner)
+	if err != nil {
+		return fmt.Errorf("failed to configure emailer: %s", err)
+	}
+
+	vapid, err := webpush.NewVAPID(config.VAPIDPrivKey, config.VAPIDSubject)
+	if err != nil {
+		return fmt.Errorf("failed to configure web push: %s", err)
+	}
+
+	notifiers := channels.Notifiers{
+		AppURL:   config.AppURL,
+		Emailer:  emailer,
+		Incident: notifier.IncidentConfig{PagerDutyURL: config.PagerDutyURL, OpsgenieURL: config.OpsgenieURL},
+		Threads:  channelsRepo,
+		WebPush:  notifier.NewWebPushNotifier(vapid, push.NewStore(push.NewRepo(db)), config.AppURL),
+	}
+
+	usersService := users.NewService(users.NewRepo(db))
+	outboxService := outbox.NewService(outbox.NewRepo(db), channelsService, usersService, notifiers)
+	digestService := digest.NewService(usersService, certsService, channelsService, outboxService, config.AppURL)
+	escalationService := escalation.NewService(escalation.NewRepo(db), outboxService, linkSigner, config.AppURL)
 
 	doneCh := make(chan struct{})
 	errCh := make(chan error)
@@ -107,7 +199,7 @@ func run(config *WorkerConfig, logger *slog.Logger) error {
 
 	go func() {
 		err = certsService.ProcessBatch(
-			context.Background(),
+			ctx,
 			config.BatchSize,
 			notificationCh,
 			logger,
@@ -122,33 +214,87 @@ func run(config *WorkerConfig, logger *slog.Logger) error {
 	for {
 		select {
 		case err := <-errCh:
+			stats.failures["batch"]++
 			return fmt.Errorf("failed to process batch: %s", err)
 		case <-doneCh:
 			logger.Info("Batch processed successfully")
-			return nil
+
+			sent, err := digestService.SendDue(ctx, digest.SendDueReq{Now: time.Now().UTC()}, logger)
+			if err != nil {
+				stats.failures["digests"]++
+				logger.Error("Failed to send digests", "error", err.Error())
+			} else {
+				stats.digests = sent
+				logger.Info("Digests queued", "count", sent)
+			}
+
+			escalated, err := escalationService.Escalate(ctx, escalation.EscalateReq{Now: time.Now().UTC()}, logger)
+			if err != nil {
+				stats.failures["escalations"]++
+				logger.Error("Failed to escalate incidents", "error", err.Error())
+			} else {
+				stats.escalations = escalated
+				logger.Info("Incidents escalated", "count", escalated)
+			}
+
+			return dispatch(outboxService, config.BatchSize, logger, stats)
 		case n := <-notificationCh:
-			logger.Debug("Sending notification", "domain", n.Domain, "status", n.Status, "hours", n.Hours)
+			stats.notifications++
+			logger.Debug("Queueing notification", "domain", n.Domain, "status", n.Status, "hours", n.Hours)
 			userID, err := common.ParseID(n.UserID)
 			if err != nil {
 				logger.Error("Failed to parse user ID", "id", n.UserID, "error", err.Error())
 				continue
 			}
 
-			user, err := usersService.GetByID(context.Background(), users.GetByIDReq{UserID: userID})
+			// Channels notified by an escalation policy get the notification with its acknowledge link instead.
+			escalated, err := escalationService.Trigger(ctx, escalation.TriggerReq{UserID: userID, Notification: n})
+			if err != nil {
+				logger.Error("Failed to trigger escalation policies", "id", n.UserID, "domain", n.Domain, "error", err.Error())
+			}
+
+			routed, err := channelsService.Route(ctx, channels.RouteReq{UserID: userID, Notification: n})
 			if err != nil {
-				logger.Error("Failed to fetch user data", "id", n.UserID, "error", err.Error())
+				logger.Error("Failed to fetch notification channels", "id", n.UserID, "error", err.Error())
 				continue
 			}
 
-			if user.WebhookURL.String() == "" {
-				logger.Debug("User has not provided a webhook, skipping notification", "id", n.UserID)
+			if len(routed) == 0 {
+				logger.Debug("No notification channel matches, skipping notification", "id", n.UserID, "domain", n.Domain)
 				continue
 			}
 
-			err = slacker.Notify(user.WebhookURL.String(), n)
-			if err != nil {
-				logger.Error("Failed to send notification", "id", n.UserID, "webhook_url", user.WebhookURL.String(), "error", err.Error())
+			for _, c := range routed {
+				if slices.Contains(escalated, c.ID) {
+					continue
+				}
+				err = outboxService.Enqueue(ctx, outbox.EnqueueReq{UserID: userID, ChannelID: c.ID, Notification: n})
+				if err != nil {
+					stats.failures["notifications"]++
+					logger.Error("Failed to queue notification", "id", n.UserID, "channel", c.ID.String(), "kind", c.Kind, "error", err.Error())
+				}
 			}
 		}
 	}
 }
+
+// dispatch delivers the notifications in the outbox that are due, batch by batch,
+// including those queued by previous runs that are due for a retry.
+func dispatch(outboxService outbox.Service, size int, logger *slog.Logger, stats *runStats) error {
+	total := 0
+	for {
+		n, err := outboxService.Dispatch(context.Background(), outbox.DispatchReq{Size: size}, logger)
+		if err != nil {
+			stats.failures["dispatch"]++
+			return fmt.Errorf("failed to dispatch notifications: %s", err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		stats.dispatched = total
+	}
+
+	logger.Info("Notifications dispatched", "count", total)
+	return nil
+}
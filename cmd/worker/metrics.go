@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/germandv/domainator/internal/tlser"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// pushJob is the job the metrics of the worker are grouped under in the Pushgateway.
+const pushJob = "domainator_worker"
+
+// runStats counts what a run of the worker did, to push it to a Pushgateway at the end.
+type runStats struct {
+	start         time.Time
+	certs         atomic.Int64
+	probeFailures atomic.Int64
+	notifications int
+	digests       int
+	escalations   int
+	dispatched    int
+	failures      map[string]int
+}
+
+func newRunStats() *runStats {
+	return &runStats{start: time.Now(), failures: map[string]int{}}
+}
+
+// countingTLSer counts the certs checked and the checks that failed.
+type countingTLSer struct {
+	tlser.Client
+	stats *runStats
+}
+
+func (c countingTLSer) GetCertData(ctx context.Context, domain string) tlser.CertData {
+	data := c.Client.GetCertData(ctx, domain)
+	c.stats.certs.Add(1)
+	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
+		c.stats.probeFailures.Add(1)
+	}
+	return data
+}
+
+// push sends the stats of the run, which ended with err, to the Pushgateway.
+func (s *runStats) push(ctx context.Context, pushURL string, err error) error {
+	newGauge := func(name string, help string) prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	}
+	duration := newGauge("domainator_worker_last_run_duration_seconds", "Duration of the last run of the worker.")
+	timestamp := newGauge("domainator_worker_last_run_timestamp_seconds", "End of the last run of the worker as a Unix timestamp.")
+	success := newGauge("domainator_worker_last_run_success", "Whether the last run of the worker finished without errors (1) or not (0).")
+	processed := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "domainator_worker_processed",
+		Help: "What the last run of the worker processed, by kind.",
+	}, []string{"kind"})
+	failures := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "domainator_worker_failures",
+		Help: "Failures in the last run of the worker, by kind.",
+	}, []string{"kind"})
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(duration, timestamp, success, processed, failures)
+
+	duration.Set(time.Since(s.start).Seconds())
+	timestamp.SetToCurrentTime()
+	if err == nil {
+		success.Set(1)
+	}
+
+	processed.WithLabelValues("certs").Set(float64(s.certs.Load()))
+	processed.WithLabelValues("notifications").Set(float64(s.notifications))
+	processed.WithLabelValues("digests").Set(float64(s.digests))
+	processed.WithLabelValues("escalations").Set(float64(s.escalations))
+	processed.WithLabelValues("dispatched").Set(float64(s.dispatched))
+
+	s.failures["probes"] += int(s.probeFailures.Load())
+	for _, kind := range []string{"probes", "batch", "notifications", "digests", "escalations", "dispatch"} {
+		failures.WithLabelValues(kind).Set(float64(s.failures[kind]))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return push.New(pushURL, pushJob).Gatherer(registry).Client(http.DefaultClient).PushContext(ctx)
+}
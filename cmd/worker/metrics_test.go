@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPush(t *testing.T) {
+	t.Parallel()
+
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	stats := newRunStats()
+	stats.certs.Add(3)
+	stats.probeFailures.Add(1)
+	stats.notifications = 2
+
+	err := stats.push(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("Expected method PUT, got %s", method)
+	}
+	if path != "/metrics/job/"+pushJob {
+		t.Errorf("Expected path /metrics/job/%s, got %s", pushJob, path)
+	}
+	// The body is in the protobuf format, where the names and label values are plain strings.
+	for _, s := range []string{"domainator_worker_last_run_success", "domainator_worker_processed", "notifications", "probes"} {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected %q in the pushed metrics", s)
+		}
+	}
+}
+
+func TestPushError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	err := newRunStats().push(context.Background(), server.URL, errors.New("run failed"))
+	if err == nil {
+		t.Errorf("Expected an error when the pushgateway rejects the metrics")
+	}
+}
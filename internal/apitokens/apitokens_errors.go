@@ -0,0 +1,12 @@
+package apitokens
+
+import "errors"
+
+var (
+	ErrInvalidName  = errors.New("name is required and must be at most 64 characters")
+	ErrInvalidScope = errors.New("invalid scope, use domains:read, domains:write or settings:read")
+	ErrNoScopes     = errors.New("at least one scope is required")
+	ErrTooMany      = errors.New("too many access tokens")
+	ErrNotFound     = errors.New("access token not found")
+	ErrInvalidToken = errors.New("invalid access token")
+)
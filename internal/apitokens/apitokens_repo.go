@@ -0,0 +1,106 @@
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const QueryTimeout = 5 * time.Second
+
+type Repo interface {
+	Save(ctx context.Context, token repoToken) error
+	Count(ctx context.Context, userID common.ID) (int, error)
+	GetAll(ctx context.Context, userID common.ID) ([]repoToken, error)
+	GetByHash(ctx context.Context, hash string) (repoToken, error)
+	SetLastUsed(ctx context.Context, id common.ID, usedAt time.Time) error
+	Delete(ctx context.Context, userID common.ID, id common.ID) error
+}
+
+type TokensRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *TokensRepo {
+	return &TokensRepo{db}
+}
+
+const tokenColumns = `id, user_id, name, prefix, token_hash, scopes, last_used_at, created_at`
+
+func (r *TokensRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *TokensRepo) Save(ctx context.Context, token repoToken) error {
+	q := `
+    insert into api_tokens (id, user_id, name, prefix, token_hash, scopes, created_at)
+    values ($1, $2, $3, $4, $5, $6, $7)`
+	return r.update(ctx, q, token.ID, token.UserID, token.Name, token.Prefix, token.Hash, token.Scopes, token.CreatedAt)
+}
+
+func (r *TokensRepo) Count(ctx context.Context, userID common.ID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	count := 0
+	q := `select count(*) from api_tokens where user_id = $1`
+	err := r.db.QueryRow(ctx, q, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *TokensRepo) GetAll(ctx context.Context, userID common.ID) ([]repoToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select ` + tokenColumns + ` from api_tokens where user_id = $1 order by created_at`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoToken])
+}
+
+func (r *TokensRepo) GetByHash(ctx context.Context, hash string) (repoToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select ` + tokenColumns + ` from api_tokens where token_hash = $1`
+
+	rows, _ := r.db.Query(ctx, q, hash)
+	token, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[repoToken])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoToken{}, ErrNotFound
+		}
+		return repoToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *TokensRepo) SetLastUsed(ctx context.Context, id common.ID, usedAt time.Time) error {
+	q := `update api_tokens set last_used_at = $2 where id = $1`
+	return r.update(ctx, q, id, usedAt)
+}
+
+func (r *TokensRepo) Delete(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `delete from api_tokens where user_id = $1 and id = $2`
+	return r.update(ctx, q, userID, id)
+}
@@ -0,0 +1,16 @@
+package apitokens
+
+import "time"
+
+// repoToken represents a Token in the Repository layer,
+// only the hash of the secret is stored.
+type repoToken struct {
+	ID         string     `db:"id"`
+	UserID     string     `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	Hash       string     `db:"token_hash"`
+	Scopes     []string   `db:"scopes"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
@@ -0,0 +1,128 @@
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+const (
+	// TokenPrefix starts every token, so they are easy to recognise, e.g. by secret scanners.
+	TokenPrefix   = "dmn_"
+	secretLength  = 40
+	prefixLength  = len(TokenPrefix) + 4
+	maxNameLength = 64
+	// lastUsedPrecision avoids writing to the database on every request made with a token.
+	lastUsedPrecision = time.Minute
+)
+
+type Service interface {
+	Create(ctx context.Context, req CreateReq) (Token, string, error)
+	GetAll(ctx context.Context, req GetAllReq) ([]Token, error)
+	Revoke(ctx context.Context, req RevokeReq) error
+	Authenticate(ctx context.Context, req AuthenticateReq) (Token, error)
+}
+
+type TokensService struct {
+	repo             Repo
+	maxTokensPerUser int
+}
+
+func NewService(repo Repo, maxTokensPerUser int) *TokensService {
+	return &TokensService{
+		repo:             repo,
+		maxTokensPerUser: maxTokensPerUser,
+	}
+}
+
+// Create saves a new token and returns it along with its secret, which is not stored and cannot be shown again.
+func (s *TokensService) Create(ctx context.Context, req CreateReq) (Token, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return Token{}, "", ErrInvalidName
+	}
+
+	if len(req.Scopes) == 0 {
+		return Token{}, "", ErrNoScopes
+	}
+
+	count, err := s.repo.Count(ctx, req.UserID)
+	if err != nil {
+		return Token{}, "", err
+	}
+
+	if count >= s.maxTokensPerUser {
+		return Token{}, "", fmt.Errorf("cannot have more than %d access tokens: %w", s.maxTokensPerUser, ErrTooMany)
+	}
+
+	secret := TokenPrefix + common.GenerateRandomString(secretLength)
+	token := New(req.UserID, name, secret[:prefixLength], req.Scopes)
+	err = s.repo.Save(ctx, serviceToRepoAdapter(token, hashToken(secret)))
+	if err != nil {
+		return Token{}, "", err
+	}
+
+	return token, secret, nil
+}
+
+func (s *TokensService) GetAll(ctx context.Context, req GetAllReq) ([]Token, error) {
+	tokens, err := s.repo.GetAll(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Token, len(tokens))
+	for i, t := range tokens {
+		result[i], err = repoToServiceAdapter(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Revoke deletes the token, requests made with it are rejected from then on.
+func (s *TokensService) Revoke(ctx context.Context, req RevokeReq) error {
+	return s.repo.Delete(ctx, req.UserID, req.ID)
+}
+
+// Authenticate returns the token the secret belongs to, ErrInvalidToken if there is none,
+// and ErrInvalidScope if the token doesn't allow the scope.
+func (s *TokensService) Authenticate(ctx context.Context, req AuthenticateReq) (Token, error) {
+	if !strings.HasPrefix(req.Token, TokenPrefix) || len(req.Token) != len(TokenPrefix)+secretLength {
+		return Token{}, ErrInvalidToken
+	}
+
+	t, err := s.repo.GetByHash(ctx, hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Token{}, ErrInvalidToken
+		}
+		return Token{}, err
+	}
+
+	token, err := repoToServiceAdapter(t)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if !token.Allows(req.Scope) {
+		return Token{}, ErrInvalidScope
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(token.LastUsedAt) > lastUsedPrecision {
+		err = s.repo.SetLastUsed(ctx, token.ID, now)
+		if err != nil {
+			return Token{}, err
+		}
+		token.LastUsedAt = now
+	}
+
+	return token, nil
+}
@@ -0,0 +1,120 @@
+package apitokens
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+type CreateReq struct {
+	UserID common.ID
+	Name   string
+	Scopes []Scope
+}
+
+type GetAllReq struct {
+	UserID common.ID
+}
+
+type RevokeReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type AuthenticateReq struct {
+	// Token is the secret sent by the client.
+	Token string
+	Scope Scope
+}
+
+// Token is a personal access token, it acts on behalf of its user within its scopes.
+type Token struct {
+	ID     common.ID
+	UserID common.ID
+	Name   string
+	// Prefix is the start of the secret, to tell tokens apart without showing them.
+	Prefix     string
+	Scopes     []Scope
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// Allows reports whether the token can be used for something that needs the scope.
+func (t Token) Allows(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s.grants(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func New(userID common.ID, name string, prefix string, scopes []Scope) Token {
+	return Token{
+		ID:        common.NewID(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// hashToken returns the hash stored for the secret, tokens are random enough not to need a slow hash.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// serviceToRepoAdapter transforms a Token from the Service layer to the Repository layer.
+func serviceToRepoAdapter(t Token, hash string) repoToken {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = s.String()
+	}
+
+	return repoToken{
+		ID:        t.ID.String(),
+		UserID:    t.UserID.String(),
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// repoToServiceAdapter transforms a Token from the Repository layer to the Service layer.
+func repoToServiceAdapter(t repoToken) (Token, error) {
+	id, err := common.ParseID(t.ID)
+	if err != nil {
+		return Token{}, err
+	}
+
+	userID, err := common.ParseID(t.UserID)
+	if err != nil {
+		return Token{}, err
+	}
+
+	scopes, err := ParseScopes(t.Scopes)
+	if err != nil {
+		return Token{}, err
+	}
+
+	lastUsedAt := time.Time{}
+	if t.LastUsedAt != nil {
+		lastUsedAt = *t.LastUsedAt
+	}
+
+	return Token{
+		ID:         id,
+		UserID:     userID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: lastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package apitokens
+
+import (
+	"slices"
+	"strings"
+)
+
+// Scope is what a token is allowed to do with the API.
+type Scope string
+
+const (
+	ScopeDomainsRead  Scope = "domains:read"
+	ScopeDomainsWrite Scope = "domains:write"
+	ScopeSettingsRead Scope = "settings:read"
+	ScopeChannelsTest Scope = "channels:test"
+)
+
+// Scopes lists every scope, in the order they are offered to users.
+var Scopes = []Scope{ScopeDomainsRead, ScopeDomainsWrite, ScopeSettingsRead, ScopeChannelsTest}
+
+func ParseScope(scope string) (Scope, error) {
+	s := Scope(strings.TrimSpace(scope))
+	if !slices.Contains(Scopes, s) {
+		return "", ErrInvalidScope
+	}
+	return s, nil
+}
+
+// ParseScopes parses a list of scopes, removing duplicates.
+func ParseScopes(scopes []string) ([]Scope, error) {
+	parsed := []Scope{}
+	for _, scope := range scopes {
+		s, err := ParseScope(scope)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(parsed, s) {
+			parsed = append(parsed, s)
+		}
+	}
+
+	if len(parsed) == 0 {
+		return nil, ErrNoScopes
+	}
+
+	return parsed, nil
+}
+
+func (s Scope) String() string {
+	return string(s)
+}
+
+// Label is the human friendly description of the scope.
+func (s Scope) Label() string {
+	switch s {
+	case ScopeDomainsRead:
+		return "List domains"
+	case ScopeDomainsWrite:
+		return "Add, refresh and delete domains"
+	case ScopeSettingsRead:
+		return "Read settings"
+	case ScopeChannelsTest:
+		return "Send test messages through channels"
+	default:
+		return string(s)
+	}
+}
+
+// grants reports whether having s allows what needs the scope,
+// writing domains includes reading them.
+func (s Scope) grants(scope Scope) bool {
+	return s == scope || (s == ScopeDomainsWrite && scope == ScopeDomainsRead)
+}
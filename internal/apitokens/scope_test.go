@@ -0,0 +1,65 @@
+package apitokens
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scopes   []string
+		expected int
+		err      error
+	}{
+		{"one scope", []string{"domains:read"}, 1, nil},
+		{"duplicates", []string{"domains:read", " domains:read "}, 1, nil},
+		{"all scopes", []string{"domains:read", "domains:write", "settings:read", "channels:test"}, 4, nil},
+		{"no scopes", []string{}, 0, ErrNoScopes},
+		{"unknown scope", []string{"domains:read", "admin"}, 0, ErrInvalidScope},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			scopes, err := ParseScopes(tc.scopes)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if len(scopes) != tc.expected {
+				t.Errorf("Expected %d scopes, got %d", tc.expected, len(scopes))
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scopes   []Scope
+		scope    Scope
+		expected bool
+	}{
+		{"same scope", []Scope{ScopeDomainsRead}, ScopeDomainsRead, true},
+		{"write includes read", []Scope{ScopeDomainsWrite}, ScopeDomainsRead, true},
+		{"read doesn't include write", []Scope{ScopeDomainsRead}, ScopeDomainsWrite, false},
+		{"other scope", []Scope{ScopeDomainsWrite}, ScopeSettingsRead, false},
+		{"any of the scopes", []Scope{ScopeDomainsRead, ScopeSettingsRead}, ScopeSettingsRead, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := Token{Scopes: tc.scopes}
+			if got := token.Allows(tc.scope); got != tc.expected {
+				t.Errorf("Expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package atom writes Atom feeds (RFC 4287).
+package atom
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const namespace = "http://www.w3.org/2005/Atom"
+
+// Feed is an Atom feed, its ID and the IDs of its entries must never change
+// so feed readers can tell the entries they've already seen.
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  Person   `xml:"author"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type Entry struct {
+	ID       string     `xml:"id"`
+	Title    string     `xml:"title"`
+	Updated  string     `xml:"updated"`
+	Links    []Link     `xml:"link"`
+	Summary  string     `xml:"summary,omitempty"`
+	Category []Category `xml:"category"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+// NewFeed returns a feed with no entries.
+func NewFeed(id string, title string, author string, updated time.Time) Feed {
+	return Feed{
+		XMLNS:   namespace,
+		ID:      id,
+		Title:   title,
+		Updated: Time(updated),
+		Author:  Person{Name: author},
+		Links:   []Link{},
+		Entries: []Entry{},
+	}
+}
+
+// Time formats t as an Atom date, RFC 3339 in UTC.
+func Time(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Write writes the feed as an XML document.
+func (f Feed) Write(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(f)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,52 @@
+package atom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	updated := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	feed := NewFeed("urn:uuid:0190f5a4-7b1e-7c3a-9a5e-2f1d3c4b5a69", "Certificates <& changes>", "Domainator", updated)
+	feed.Links = append(feed.Links, Link{Href: "https://domainator.example.com/dashboard", Rel: "alternate"})
+	feed.Entries = append(feed.Entries, Entry{
+		ID:       "urn:uuid:0190f5a4-7b1e-7c3a-9a5e-2f1d3c4b5a70",
+		Title:    "example.com renewed",
+		Updated:  Time(updated),
+		Summary:  "now expires 2024-08-01",
+		Category: []Category{{Term: "renewed"}},
+	})
+
+	var sb strings.Builder
+	err := feed.Write(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Error("Expected the output to start with the XML header")
+	}
+	if !strings.Contains(out, `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Error("Expected the Atom namespace")
+	}
+	if !strings.Contains(out, "<updated>2024-05-01T08:00:00Z</updated>") {
+		t.Error("Expected dates in UTC")
+	}
+	if !strings.Contains(out, "Certificates &lt;&amp; changes&gt;") {
+		t.Error("Expected the title to be escaped")
+	}
+
+	var parsed Feed
+	err = xml.Unmarshal([]byte(strings.TrimPrefix(out, xml.Header)), &parsed)
+	if err != nil {
+		t.Fatalf("Expected valid XML, got %s", err)
+	}
+	if len(parsed.Entries) != 1 || parsed.Entries[0].ID != feed.Entries[0].ID {
+		t.Errorf("Expected the entry to round trip, got %+v", parsed.Entries)
+	}
+}
@@ -6,26 +6,30 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/germandv/domainator/internal/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
 var ErrNoKey = errors.New("key not found")
 
 type Client interface {
-	Ping() error
+	Ping(ctx context.Context) error
 	Close() error
-	Increment(key string) (int64, error)
-	Expire(key string, duration time.Duration) error
+	Increment(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, duration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Publish(ctx context.Context, channel string, message string) error
+	// Subscribe returns the messages published to the channel, until ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
 }
 
 type RedisClient struct {
 	client *redis.Client
 }
 
-func (rc *RedisClient) Ping() error {
-	resp, err := rc.client.Ping(context.Background()).Result()
+func (rc *RedisClient) Ping(ctx context.Context) error {
+	resp, err := rc.client.Ping(ctx).Result()
 	if err != nil || resp != "PONG" {
 		return err
 	}
@@ -36,12 +40,12 @@ func (rc *RedisClient) Close() error {
 	return rc.client.Close()
 }
 
-func (rc *RedisClient) Increment(key string) (int64, error) {
-	return rc.client.Incr(context.Background(), key).Result()
+func (rc *RedisClient) Increment(ctx context.Context, key string) (int64, error) {
+	return rc.client.Incr(ctx, key).Result()
 }
 
-func (rc *RedisClient) Expire(key string, duration time.Duration) error {
-	return rc.client.Expire(context.Background(), key, duration).Err()
+func (rc *RedisClient) Expire(ctx context.Context, key string, duration time.Duration) error {
+	return rc.client.Expire(ctx, key, duration).Err()
 }
 
 func (rc *RedisClient) Get(ctx context.Context, key string) (string, error) {
@@ -59,12 +63,53 @@ func (rc *RedisClient) Set(ctx context.Context, key string, value string, ttl ti
 	return rc.client.Set(ctx, key, value, ttl).Err()
 }
 
-func New(host string, port int, password string) Client {
-	return &RedisClient{
-		client: redis.NewClient(&redis.Options{
-			Addr:     fmt.Sprintf("%s:%d", host, port),
-			Password: password,
-			DB:       0,
-		}),
+func (rc *RedisClient) Publish(ctx context.Context, channel string, message string) error {
+	return rc.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe waits for Redis to confirm the subscription, the connection is then
+// reestablished by go-redis if it drops, missing what was published meanwhile.
+func (rc *RedisClient) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	sub := rc.client.Subscribe(ctx, channel)
+	_, err := sub.Receive(ctx)
+	if err != nil {
+		_ = sub.Close()
+		return nil, err
 	}
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer sub.Close()
+
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+func New(host string, port int, password string) Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", host, port),
+		Password: password,
+		DB:       0,
+	})
+	client.AddHook(tracing.RedisHook{})
+
+	return &RedisClient{client}
 }
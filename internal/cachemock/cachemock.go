@@ -2,6 +2,7 @@ package cachemock
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/germandv/domainator/internal/cache"
@@ -10,15 +11,19 @@ import (
 type CacheMockClient struct {
 	counts map[string]int
 	hmap   map[string]string
+
+	mu          sync.Mutex
+	subscribers map[string][]chan string
 }
 
 func New() *CacheMockClient {
 	return &CacheMockClient{
-		counts: make(map[string]int),
+		counts:      make(map[string]int),
+		subscribers: make(map[string][]chan string),
 	}
 }
 
-func (c *CacheMockClient) Ping() error {
+func (c *CacheMockClient) Ping(_ context.Context) error {
 	return nil
 }
 
@@ -26,12 +31,12 @@ func (c *CacheMockClient) Close() error {
 	return nil
 }
 
-func (c *CacheMockClient) Increment(key string) (int64, error) {
+func (c *CacheMockClient) Increment(_ context.Context, key string) (int64, error) {
 	c.counts[key]++
 	return int64(c.counts[key]), nil
 }
 
-func (c *CacheMockClient) Expire(_ string, _ time.Duration) error {
+func (c *CacheMockClient) Expire(_ context.Context, _ string, _ time.Duration) error {
 	return nil
 }
 
@@ -47,3 +52,37 @@ func (c *CacheMockClient) Set(_ context.Context, key string, value string, _ tim
 	c.hmap[key] = value
 	return nil
 }
+
+func (c *CacheMockClient) Publish(_ context.Context, channel string, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, ch := range c.subscribers[channel] {
+		ch <- message
+	}
+	return nil
+}
+
+func (c *CacheMockClient) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan string, 10)
+	c.subscribers[channel] = append(c.subscribers[channel], ch)
+
+	go func() {
+		<-ctx.Done()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		subs := c.subscribers[channel]
+		for i, s := range subs {
+			if s == ch {
+				c.subscribers[channel] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
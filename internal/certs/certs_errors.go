@@ -3,8 +3,11 @@ package certs
 import "errors"
 
 var (
-	ErrInvalidDomain   = errors.New("domain is required and must be a valid hostname")
-	ErrDuplicateDomain = errors.New("domain already exists")
-	ErrInvalidIssuer   = errors.New("issuer is required")
-	ErrNotFound        = errors.New("domain not found")
+	ErrInvalidDomain    = errors.New("domain is required and must be a valid hostname")
+	ErrDuplicateDomain  = errors.New("domain already exists")
+	ErrInvalidIssuer    = errors.New("issuer is required")
+	ErrInvalidTag       = errors.New("tags must be up to 32 lowercase letters, numbers, dashes or underscores")
+	ErrNotFound         = errors.New("domain not found")
+	ErrInvalidEventKind = errors.New("invalid event kind")
+	ErrNoProbe          = errors.New("no probe job is due")
 )
@@ -14,15 +14,30 @@ import (
 
 const QueryTimeout = 5 * time.Second
 
+// enqueueProbeQuery queues a job, or asks the queued one to run again if it's running already.
+// Claiming a job clears rerun, so it's only kept when the request comes while the job runs.
+const enqueueProbeQuery = `insert into probe_jobs (id, certificate_id, user_id, kind, attempts, run_at, created_at)
+  values ($1, $2, $3, $4, $5, $6, $7)
+  on conflict (certificate_id) do update set rerun = true`
+
 type Repo interface {
-	Save(ctx context.Context, cert repoCert) error
+	SaveWithProbe(ctx context.Context, cert repoCert, job repoProbeJob) error
 	GetAll(ctx context.Context, userID common.ID) ([]repoCert, error)
 	GetBatch(ctx context.Context, size int, cursor string) ([]repoCert, error)
+	CountByStatus(ctx context.Context) ([]repoStatusCount, error)
 	Get(ctx context.Context, id common.ID) (repoCert, error)
 	Count(ctx context.Context, userID common.ID, limit int) (int, error)
 	Update(ctx context.Context, userID common.ID, id common.ID, expiry time.Time, issuer string, updatedAt time.Time) error
 	UpdateWithError(ctx context.Context, userID common.ID, id common.ID, error string, updatedAt time.Time) error
+	UpdateTags(ctx context.Context, userID common.ID, id common.ID, tags []string) error
+	Snooze(ctx context.Context, userID common.ID, id common.ID, until time.Time) error
 	Delete(ctx context.Context, userID common.ID, id common.ID) error
+	SaveEvents(ctx context.Context, events []repoEvent) error
+	GetEvents(ctx context.Context, userID common.ID, since time.Time, limit int) ([]repoEvent, error)
+	EnqueueProbe(ctx context.Context, job repoProbeJob) error
+	ClaimProbe(ctx context.Context, lease time.Duration) (repoProbeJob, error)
+	RetryProbe(ctx context.Context, id common.ID, runAt time.Time) error
+	FinishProbe(ctx context.Context, id common.ID) error
 }
 
 type CertsRepo struct {
@@ -33,23 +48,28 @@ func NewRepo(db *pgxpool.Pool) *CertsRepo {
 	return &CertsRepo{db}
 }
 
-func (r *CertsRepo) Save(ctx context.Context, cert repoCert) error {
+// SaveWithProbe saves a cert that hasn't been checked yet, without expiry nor issuer,
+// along with the job that checks it.
+func (r *CertsRepo) SaveWithProbe(ctx context.Context, cert repoCert, job repoProbeJob) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 	defer cancel()
 
-	q := `insert into certificates (id, user_id, domain, issuer, expires_at)
-    values ($1, $2, $3, $4, $5)`
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		q := `insert into certificates (id, user_id, domain, issuer, tags)
+      values ($1, $2, $3, $4, $5)`
 
-	_, err := r.db.Exec(ctx, q, cert.ID, cert.UserID, cert.Domain, cert.Issuer, cert.ExpiresAt)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return ErrDuplicateDomain
+		_, err := tx.Exec(ctx, q, cert.ID, cert.UserID, cert.Domain, cert.Issuer, cert.Tags)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return ErrDuplicateDomain
+			}
+			return err
 		}
-		return err
-	}
 
-	return nil
+		_, err = tx.Exec(ctx, enqueueProbeQuery, job.ID, job.CertID, job.UserID, job.Kind, job.Attempts, job.RunAt, job.CreatedAt)
+		return err
+	})
 }
 
 func (r *CertsRepo) GetAll(ctx context.Context, userID common.ID) ([]repoCert, error) {
@@ -58,7 +78,8 @@ func (r *CertsRepo) GetAll(ctx context.Context, userID common.ID) ([]repoCert, e
 
 	q := `
     select
-      id, user_id, domain, issuer, expires_at, created_at, updated_at, coalesce(error, '') as error
+      id, user_id, domain, issuer, coalesce(expires_at, '0001-01-01') as expires_at, created_at, updated_at,
+      coalesce(error, '') as error, tags, snoozed_until, exists (select 1 from probe_jobs where certificate_id = certificates.id) as checking
     from
       certificates
     where
@@ -74,7 +95,8 @@ func (r *CertsRepo) Get(ctx context.Context, id common.ID) (repoCert, error) {
 
 	q := `
     select
-      id, user_id, domain, issuer, expires_at, created_at, updated_at, coalesce(error, '') as error
+      id, user_id, domain, issuer, coalesce(expires_at, '0001-01-01') as expires_at, created_at, updated_at,
+      coalesce(error, '') as error, tags, snoozed_until, exists (select 1 from probe_jobs where certificate_id = certificates.id) as checking
     from
       certificates
     where
@@ -146,13 +168,24 @@ func (r *CertsRepo) UpdateWithError(
 	return r.update(ctx, q, id, userID, error, updatedAt)
 }
 
+func (r *CertsRepo) UpdateTags(ctx context.Context, userID common.ID, id common.ID, tags []string) error {
+	q := `update certificates set tags = $3 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID, tags)
+}
+
+func (r *CertsRepo) Snooze(ctx context.Context, userID common.ID, id common.ID, until time.Time) error {
+	q := `update certificates set snoozed_until = $3 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID, until)
+}
+
 func (r *CertsRepo) Delete(ctx context.Context, userID common.ID, id common.ID) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 	defer cancel()
 
 	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
-		q := `insert into certificates_deleted (id, user_id, domain, issuer, error, expires_at, created_at, updated_at)
-      select * from certificates where id = $1 and user_id = $2`
+		q := `insert into certificates_deleted (id, user_id, domain, issuer, error, expires_at, created_at, updated_at, tags)
+      select id, user_id, domain, issuer, error, expires_at, created_at, updated_at, tags
+      from certificates where id = $1 and user_id = $2`
 		res, err := tx.Exec(ctx, q, id, userID)
 		if err != nil {
 			return err
@@ -174,13 +207,41 @@ func (r *CertsRepo) Delete(ctx context.Context, userID common.ID, id common.ID)
 	})
 }
 
+// CountByStatus counts the certs of every user by status: ok, expiring, expired, error or pending.
+// Like ExpirationStatus, certs are expired within the hour and expiring within 72 hours.
+func (r *CertsRepo) CountByStatus(ctx context.Context) ([]repoStatusCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      status, count(*) as count, min(updated_at) as oldest_check
+    from (
+      select
+        case
+          when coalesce(error, '') <> '' then 'error'
+          when expires_at is null then 'pending'
+          when expires_at < (now() at time zone 'utc') + interval '1 hour' then 'expired'
+          when expires_at < (now() at time zone 'utc') + interval '72 hours' then 'expiring'
+          else 'ok'
+        end as status,
+        updated_at
+      from certificates
+    ) as statuses
+    group by status`
+
+	rows, _ := r.db.Query(ctx, q)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoStatusCount])
+}
+
 func (r *CertsRepo) GetBatch(ctx context.Context, size int, lastID string) ([]repoCert, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 	defer cancel()
 
 	q := `
     select
-      id, user_id, domain, issuer, expires_at, created_at, updated_at, coalesce(error, '') as error
+      id, user_id, domain, issuer, coalesce(expires_at, '0001-01-01') as expires_at, created_at, updated_at,
+      coalesce(error, '') as error, tags, snoozed_until, exists (select 1 from probe_jobs where certificate_id = certificates.id) as checking
     from certificates
     where id < $2
     order by id desc
@@ -189,7 +250,8 @@ func (r *CertsRepo) GetBatch(ctx context.Context, size int, lastID string) ([]re
 	if lastID == "" {
 		q = `
       select
-        id, user_id, domain, issuer, expires_at, created_at, updated_at, coalesce(error, '') as error
+        id, user_id, domain, issuer, coalesce(expires_at, '0001-01-01') as expires_at, created_at, updated_at,
+        coalesce(error, '') as error, tags, snoozed_until, exists (select 1 from probe_jobs where certificate_id = certificates.id) as checking
       from certificates
       order by id desc
       limit $1`
@@ -224,3 +286,125 @@ func (r *CertsRepo) Count(ctx context.Context, userID common.ID, limit int) (int
 
 	return count, nil
 }
+
+func (r *CertsRepo) SaveEvents(ctx context.Context, events []repoEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(
+			`insert into certificate_events (id, user_id, certificate_id, domain, kind, detail, created_at)
+      values ($1, $2, $3, $4, $5, $6, $7)`,
+			e.ID, e.UserID, e.CertID, e.Domain, e.Kind, e.Detail, e.CreatedAt,
+		)
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// GetEvents returns the latest events of the user's certs since the given time, newest first.
+func (r *CertsRepo) GetEvents(ctx context.Context, userID common.ID, since time.Time, limit int) ([]repoEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      id, user_id, certificate_id, domain, kind, detail, created_at
+    from
+      certificate_events
+    where
+      user_id = $1 and created_at > $2
+    order by created_at desc
+    limit $3`
+
+	rows, _ := r.db.Query(ctx, q, userID, since, limit)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoEvent])
+}
+
+// EnqueueProbe queues a job to check a cert, unless one is already queued for it.
+func (r *CertsRepo) EnqueueProbe(ctx context.Context, job repoProbeJob) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx, enqueueProbeQuery, job.ID, job.CertID, job.UserID, job.Kind, job.Attempts, job.RunAt, job.CreatedAt)
+	return err
+}
+
+// ClaimProbe returns the next job that is due, postponing it by lease so other workers skip it
+// while it runs, and counts the attempt. If the worker dies before finishing it, it's run again
+// once the lease expires, the returned attempts tell how many times it was claimed.
+func (r *CertsRepo) ClaimProbe(ctx context.Context, lease time.Duration) (repoProbeJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var job repoProbeJob
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		q := `
+      select
+        id, certificate_id, user_id, kind, attempts, run_at, created_at
+      from
+        probe_jobs
+      where
+        run_at <= (now() at time zone 'utc')
+      order by run_at
+      limit 1
+      for update skip locked`
+
+		rows, _ := tx.Query(ctx, q)
+		var err error
+		job, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[repoProbeJob])
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNoProbe
+			}
+			return err
+		}
+
+		job.Attempts++
+		q = `update probe_jobs set run_at = $2, attempts = $3, rerun = false where id = $1`
+		_, err = tx.Exec(ctx, q, job.ID, time.Now().UTC().Add(lease), job.Attempts)
+		return err
+	})
+	if err != nil {
+		return repoProbeJob{}, err
+	}
+
+	return job, nil
+}
+
+func (r *CertsRepo) RetryProbe(ctx context.Context, id common.ID, runAt time.Time) error {
+	q := `update probe_jobs set run_at = $2 where id = $1`
+	return r.update(ctx, q, id, runAt)
+}
+
+// FinishProbe deletes the job, unless the cert was refreshed while it ran:
+// then it's queued again right away as a refresh, with its attempts reset.
+func (r *CertsRepo) FinishProbe(ctx context.Context, id common.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var rerun bool
+		err := tx.QueryRow(ctx, `select rerun from probe_jobs where id = $1 for update`, id).Scan(&rerun)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		if rerun {
+			q := `update probe_jobs set rerun = false, attempts = 0, kind = $2, run_at = (now() at time zone 'utc') where id = $1`
+			_, err = tx.Exec(ctx, q, id, string(ProbeRefresh))
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `delete from probe_jobs where id = $1`, id)
+		return err
+	})
+}
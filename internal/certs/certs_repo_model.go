@@ -12,4 +12,38 @@ type repoCert struct {
 	Domain    string    `db:"domain"`
 	Issuer    string    `db:"issuer"`
 	Error     string    `db:"error"`
+	Tags      []string  `db:"tags"`
+	// SnoozedUntil is nil unless the notifications about the cert were snoozed.
+	SnoozedUntil *time.Time `db:"snoozed_until"`
+	// Checking is whether a probe job of the cert is queued.
+	Checking bool `db:"checking"`
+}
+
+// repoProbeJob represents a ProbeJob in the Repository layer.
+type repoProbeJob struct {
+	ID        string    `db:"id"`
+	CertID    string    `db:"certificate_id"`
+	UserID    string    `db:"user_id"`
+	Kind      string    `db:"kind"`
+	Attempts  int       `db:"attempts"`
+	RunAt     time.Time `db:"run_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// repoEvent represents an Event in the Repository layer.
+type repoEvent struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	CertID    string    `db:"certificate_id"`
+	Domain    string    `db:"domain"`
+	Kind      string    `db:"kind"`
+	Detail    string    `db:"detail"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// repoStatusCount is how many certs have a status, and the oldest check among them.
+type repoStatusCount struct {
+	Status      string    `db:"status"`
+	Count       int       `db:"count"`
+	OldestCheck time.Time `db:"oldest_check"`
 }
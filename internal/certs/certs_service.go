@@ -10,26 +10,36 @@ import (
 	"github.com/germandv/domainator/internal/common"
 	"github.com/germandv/domainator/internal/notifier"
 	"github.com/germandv/domainator/internal/tlser"
+	"github.com/germandv/domainator/internal/updates"
 )
 
 type Service interface {
 	Save(ctx context.Context, req RegisterReq) (Cert, error)
+	Get(ctx context.Context, req GetReq) (Cert, error)
 	GetAll(ctx context.Context, req GetAllReq) ([]Cert, error)
 	Delete(ctx context.Context, req DeleteReq) error
 	Update(ctx context.Context, req UpdateReq) (Cert, error)
+	SetTags(ctx context.Context, req SetTagsReq) (Cert, error)
+	Snooze(ctx context.Context, req SnoozeReq) (Cert, error)
+	GetEvents(ctx context.Context, req GetEventsReq) ([]Event, error)
+	Stats(ctx context.Context) (Stats, error)
 	ProcessBatch(ctx context.Context, size int, ch chan<- notifier.Notification, logger *slog.Logger) error
+	RunProbes(ctx context.Context, workers int, logger *slog.Logger)
 }
 
 type CertsService struct {
 	repo            Repo
 	tlsClient       tlser.Client
+	publisher       updates.Publisher
 	maxCertsPerUser int
 }
 
-func NewService(tlsClient tlser.Client, repo Repo, maxCertsPerUser int) *CertsService {
+// NewService returns the service, the publisher is told about the certs the batch and the probes update and can be nil.
+func NewService(tlsClient tlser.Client, repo Repo, publisher updates.Publisher, maxCertsPerUser int) *CertsService {
 	return &CertsService{
 		repo:            repo,
 		tlsClient:       tlsClient,
+		publisher:       publisher,
 		maxCertsPerUser: maxCertsPerUser,
 	}
 }
@@ -44,23 +54,29 @@ func (s *CertsService) Save(ctx context.Context, req RegisterReq) (Cert, error)
 		return Cert{}, fmt.Errorf("cannot have more than %d certs", s.maxCertsPerUser)
 	}
 
-	data := s.tlsClient.GetCertData(req.Domain.value)
-	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
-		return Cert{}, fmt.Errorf("TLS error: %s", data.Status)
-	}
-
-	issuer, err := ParseIssuer(data.Issuer)
+	// The cert is checked by the probe workers, so a slow domain doesn't hold the request.
+	cert := New(req.UserID, req.Domain, Issuer{}, time.Time{}, req.Tags)
+	c := serviceToRepoAdapter(cert)
+	err = s.repo.SaveWithProbe(ctx, c, newProbeJob(c, ProbeRegister))
 	if err != nil {
 		return Cert{}, err
 	}
 
-	cert := New(req.UserID, req.Domain, issuer, data.Expiry)
-	err = s.repo.Save(ctx, serviceToRepoAdapter(cert))
+	cert.Checking = true
+	return cert, nil
+}
+
+func (s *CertsService) Get(ctx context.Context, req GetReq) (Cert, error) {
+	cert, err := s.repo.Get(ctx, req.ID)
 	if err != nil {
 		return Cert{}, err
 	}
 
-	return cert, nil
+	if cert.UserID != req.UserID.String() {
+		return Cert{}, ErrNotFound
+	}
+
+	return repoToServiceAdapter(cert)
 }
 
 func (s *CertsService) GetAll(ctx context.Context, req GetAllReq) ([]Cert, error) {
@@ -82,7 +98,17 @@ func (s *CertsService) GetAll(ctx context.Context, req GetAllReq) ([]Cert, error
 }
 
 func (s *CertsService) Delete(ctx context.Context, req DeleteReq) error {
-	return s.repo.Delete(ctx, req.UserID, req.ID)
+	cert, err := s.repo.Get(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SaveEvents(ctx, []repoEvent{newEvent(cert, EventDeleted, "")})
 }
 
 func (s *CertsService) Update(ctx context.Context, req UpdateReq) (Cert, error) {
@@ -91,44 +117,92 @@ func (s *CertsService) Update(ctx context.Context, req UpdateReq) (Cert, error)
 		return Cert{}, err
 	}
 
-	data := s.tlsClient.GetCertData(cert.Domain)
-	now := time.Now().UTC()
-
-	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
-		err := s.repo.UpdateWithError(context.Background(), req.UserID, req.ID, string(data.Status), now)
-		if err != nil {
-			return Cert{}, err
-		}
+	// Don't probe, or record events for, the certs of other users.
+	if cert.UserID != req.UserID.String() {
+		return Cert{}, ErrNotFound
+	}
 
-		cert.UpdatedAt = now
-		cert.Error = string(data.Status)
-		c, err := repoToServiceAdapter(cert)
-		if err != nil {
-			return Cert{}, err
-		}
-		return c, nil
+	err = s.repo.EnqueueProbe(ctx, newProbeJob(cert, ProbeRefresh))
+	if err != nil {
+		return Cert{}, err
 	}
 
-	issuer, err := ParseIssuer(data.Issuer)
+	cert.Checking = true
+	return repoToServiceAdapter(cert)
+}
+
+func (s *CertsService) SetTags(ctx context.Context, req SetTagsReq) (Cert, error) {
+	cert, err := s.repo.Get(ctx, req.ID)
 	if err != nil {
-		return Cert{}, ErrInvalidIssuer
+		return Cert{}, err
 	}
 
-	err = s.repo.Update(ctx, req.UserID, req.ID, data.Expiry, issuer.value, now)
+	tags := tagsToStrings(req.Tags)
+	err = s.repo.UpdateTags(ctx, req.UserID, req.ID, tags)
 	if err != nil {
 		return Cert{}, err
 	}
 
-	cert.UpdatedAt = now
-	cert.Issuer = issuer.value
-	cert.ExpiresAt = data.Expiry
-	cert.Error = ""
+	cert.Tags = tags
+	return repoToServiceAdapter(cert)
+}
+
+// Snooze stops the notifications about the problems of the cert until the given time,
+// it's still checked and the notification that it's healthy again is sent.
+func (s *CertsService) Snooze(ctx context.Context, req SnoozeReq) (Cert, error) {
+	cert, err := s.repo.Get(ctx, req.ID)
+	if err != nil {
+		return Cert{}, err
+	}
 
-	c, err := repoToServiceAdapter(cert)
+	until := req.Until.UTC()
+	err = s.repo.Snooze(ctx, req.UserID, req.ID, until)
 	if err != nil {
 		return Cert{}, err
 	}
-	return c, nil
+
+	cert.SnoozedUntil = &until
+	return repoToServiceAdapter(cert)
+}
+
+// GetEvents returns the latest changes in the certs of the user since the given time.
+func (s *CertsService) GetEvents(ctx context.Context, req GetEventsReq) ([]Event, error) {
+	events, err := s.repo.GetEvents(ctx, req.UserID, req.Since, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	evts := make([]Event, len(events))
+	for i, e := range events {
+		event, err := repoToEventAdapter(e)
+		if err != nil {
+			return nil, err
+		}
+		evts[i] = event
+	}
+
+	return evts, nil
+}
+
+// Stats counts the certs of every user by status, in the database.
+func (s *CertsService) Stats(ctx context.Context) (Stats, error) {
+	counts, err := s.repo.CountByStatus(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{ByStatus: map[string]int{}}
+	for _, c := range counts {
+		stats.ByStatus[c.Status] = c.Count
+		if c.Status == "pending" {
+			continue
+		}
+		if stats.OldestCheck.IsZero() || c.OldestCheck.Before(stats.OldestCheck) {
+			stats.OldestCheck = c.OldestCheck
+		}
+	}
+
+	return stats, nil
 }
 
 func (s *CertsService) ProcessBatch(
@@ -161,7 +235,7 @@ func (s *CertsService) ProcessBatch(
 			lastID = cert.ID
 			go func(cert repoCert) {
 				defer wg.Done()
-				s.updateAndCheckExp(cert, ch, logger)
+				s.updateAndCheckExp(ctx, cert, ch, logger)
 			}(cert)
 		}
 		wg.Wait()
@@ -170,9 +244,11 @@ func (s *CertsService) ProcessBatch(
 	return nil
 }
 
-func (s *CertsService) updateAndCheckExp(cert repoCert, ch chan<- notifier.Notification, logger *slog.Logger) {
-	logger.Debug("checking cert", "id", cert.ID, "domain", cert.Domain)
-	data := s.tlsClient.GetCertData(cert.Domain)
+// updateAndCheckExp stores the result of the probe even when ctx is canceled, ctx is only used to trace it.
+func (s *CertsService) updateAndCheckExp(ctx context.Context, cert repoCert, ch chan<- notifier.Notification, logger *slog.Logger) {
+	logger.DebugContext(ctx, "checking cert", "id", cert.ID, "domain", cert.Domain)
+	data := s.tlsClient.GetCertData(ctx, cert.Domain)
+	ctx = context.WithoutCancel(ctx)
 	now := time.Now().UTC()
 
 	userID, err := common.ParseID(cert.UserID)
@@ -187,18 +263,31 @@ func (s *CertsService) updateAndCheckExp(cert repoCert, ch chan<- notifier.Notif
 		return
 	}
 
+	err = s.repo.SaveEvents(ctx, changes(cert, data))
+	if err != nil {
+		logger.Debug("failed to save events", "id", cert.ID, "error", err.Error())
+	}
+
 	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
-		err := s.repo.UpdateWithError(context.Background(), userID, certID, string(data.Status), now)
+		err := s.repo.UpdateWithError(ctx, userID, certID, string(data.Status), now)
 		if err != nil {
 			logger.Debug("failed to UpdateWithError", "id", cert.ID, "status", string(data.Status), "error", err.Error())
 			return
 		}
+		s.publish(ctx, cert, logger)
+		if isSnoozed(cert, now) {
+			logger.Debug("cert is snoozed", "id", cert.ID, "status", string(data.Status))
+			return
+		}
 		ch <- notifier.Notification{
-			ID:     cert.ID,
-			UserID: cert.UserID,
-			Domain: cert.Domain,
-			Status: string(data.Status),
-			Hours:  0,
+			ID:        cert.ID,
+			UserID:    cert.UserID,
+			Domain:    cert.Domain,
+			Status:    string(data.Status),
+			Hours:     0,
+			Issuer:    cert.Issuer,
+			ExpiresAt: cert.ExpiresAt,
+			Tags:      cert.Tags,
 		}
 		return
 	}
@@ -208,30 +297,78 @@ func (s *CertsService) updateAndCheckExp(cert repoCert, ch chan<- notifier.Notif
 		return
 	}
 
-	err = s.repo.Update(context.Background(), userID, certID, data.Expiry, issuer.value, now)
+	err = s.repo.Update(ctx, userID, certID, data.Expiry, issuer.value, now)
 	if err != nil {
 		logger.Debug("failed to update cert", "id", cert.ID, "error", err.Error())
 		return
 	}
+	s.publish(ctx, cert, logger)
+
+	expHours := HoursToExpiration(data.Expiry)
+	expStatus := ExpirationStatus(expHours)
+	if expStatus == "" && hadProblem(cert) {
+		ch <- notifier.Notification{
+			ID:        cert.ID,
+			UserID:    cert.UserID,
+			Domain:    cert.Domain,
+			Status:    notifier.StatusResolved,
+			Hours:     expHours,
+			Issuer:    issuer.value,
+			ExpiresAt: data.Expiry,
+			Tags:      cert.Tags,
+		}
+		return
+	}
 
-	expHours := hoursToExpiration(data.Expiry)
-	expStatus := expirationStatus(expHours)
-	if expStatus != "" {
+	if expStatus != "" && !isSnoozed(cert, now) {
 		ch <- notifier.Notification{
-			ID:     cert.ID,
-			UserID: cert.UserID,
-			Domain: cert.Domain,
-			Status: expStatus,
-			Hours:  expHours,
+			ID:        cert.ID,
+			UserID:    cert.UserID,
+			Domain:    cert.Domain,
+			Status:    expStatus,
+			Hours:     expHours,
+			Issuer:    issuer.value,
+			ExpiresAt: data.Expiry,
+			Tags:      cert.Tags,
 		}
 	}
 }
 
-func hoursToExpiration(expiry time.Time) int {
+// publish tells the open dashboards of the user that the cert changed,
+// failing only costs them the live update.
+func (s *CertsService) publish(ctx context.Context, cert repoCert, logger *slog.Logger) {
+	if s.publisher == nil {
+		return
+	}
+
+	err := s.publisher.Publish(ctx, updates.Update{UserID: cert.UserID, CertID: cert.ID})
+	if err != nil {
+		logger.WarnContext(ctx, "failed to publish cert update", "id", cert.ID, "error", err.Error())
+	}
+}
+
+// hadProblem reports whether the stored state of the cert was notified as unhealthy,
+// either because of a connection error or because it was about to expire.
+func hadProblem(cert repoCert) bool {
+	if cert.Error != "" {
+		return true
+	}
+	return !cert.ExpiresAt.IsZero() && ExpirationStatus(HoursToExpiration(cert.ExpiresAt)) != ""
+}
+
+// isSnoozed reports whether the problems of the cert shouldn't be notified at the given time.
+func isSnoozed(cert repoCert, now time.Time) bool {
+	return cert.SnoozedUntil != nil && cert.SnoozedUntil.After(now)
+}
+
+// HoursToExpiration is how many whole hours are left until the expiry, negative once it has passed.
+func HoursToExpiration(expiry time.Time) int {
 	return int(expiry.Sub(time.Now().UTC()).Hours())
 }
 
-func expirationStatus(hours int) string {
+// ExpirationStatus is the status notified for a cert that expires in the given hours,
+// empty when there is nothing to notify yet.
+func ExpirationStatus(hours int) string {
 	if hours <= 0 {
 		return "expired"
 	}
@@ -9,6 +9,12 @@ import (
 type RegisterReq struct {
 	Domain Domain
 	UserID common.ID
+	Tags   []Tag
+}
+
+type GetReq struct {
+	ID     common.ID
+	UserID common.ID
 }
 
 type GetAllReq struct {
@@ -20,23 +26,63 @@ type UpdateReq struct {
 	UserID common.ID
 }
 
+type SetTagsReq struct {
+	ID     common.ID
+	UserID common.ID
+	Tags   []Tag
+}
+
 type DeleteReq struct {
 	ID     common.ID
 	UserID common.ID
 }
 
+type GetEventsReq struct {
+	UserID common.ID
+	Since  time.Time
+	Limit  int
+}
+
+type SnoozeReq struct {
+	ID     common.ID
+	UserID common.ID
+	Until  time.Time
+}
+
 type Cert struct {
-	ID        common.ID
-	UserID    common.ID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	ExpiresAt time.Time
-	Domain    Domain
-	Issuer    Issuer
-	Error     string
+	ID           common.ID
+	UserID       common.ID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ExpiresAt    time.Time
+	Domain       Domain
+	Issuer       Issuer
+	Error        string
+	Tags         []Tag
+	SnoozedUntil time.Time
+	// Checking is whether the cert is queued to be checked.
+	Checking bool
+}
+
+// Pending reports whether the cert hasn't been checked since it was registered.
+func (c Cert) Pending() bool {
+	return c.ExpiresAt.IsZero() && c.Error == ""
 }
 
-func New(userID common.ID, domain Domain, issuer Issuer, expiresAt time.Time) Cert {
+// Snoozed reports whether the notifications about the cert are snoozed at the given time.
+func (c Cert) Snoozed(now time.Time) bool {
+	return c.SnoozedUntil.After(now)
+}
+
+// Stats are aggregates of the certs of every user.
+type Stats struct {
+	// ByStatus counts the certs by status: ok, expiring, expired, error or pending.
+	ByStatus map[string]int
+	// OldestCheck is when the cert checked the longest ago was checked, zero if none was.
+	OldestCheck time.Time
+}
+
+func New(userID common.ID, domain Domain, issuer Issuer, expiresAt time.Time, tags []Tag) Cert {
 	return Cert{
 		ID:        common.NewID(),
 		UserID:    userID,
@@ -45,12 +91,13 @@ func New(userID common.ID, domain Domain, issuer Issuer, expiresAt time.Time) Ce
 		Domain:    domain,
 		Issuer:    issuer,
 		Error:     "",
+		Tags:      tags,
 	}
 }
 
 // serviceToRepoAdapter transforms a Cert from the Service layer to the Repository layer.
 func serviceToRepoAdapter(cert Cert) repoCert {
-	return repoCert{
+	c := repoCert{
 		ID:        cert.ID.String(),
 		UserID:    cert.UserID.String(),
 		CreatedAt: cert.CreatedAt,
@@ -59,7 +106,13 @@ func serviceToRepoAdapter(cert Cert) repoCert {
 		Domain:    cert.Domain.String(),
 		Issuer:    cert.Issuer.String(),
 		Error:     cert.Error,
+		Tags:      tagsToStrings(cert.Tags),
 	}
+	if !cert.SnoozedUntil.IsZero() {
+		c.SnoozedUntil = &cert.SnoozedUntil
+	}
+	c.Checking = cert.Checking
+	return c
 }
 
 // repoToServiceAdapter transforms a Cert from the Repository layer to the Service layer.
@@ -79,19 +132,41 @@ func repoToServiceAdapter(cert repoCert) (Cert, error) {
 		return Cert{}, err
 	}
 
-	parsedIssuer, err := ParseIssuer(cert.Issuer)
-	if err != nil {
-		return Cert{}, err
+	// Certs that haven't been checked yet don't have an issuer.
+	parsedIssuer := Issuer{}
+	if cert.Issuer != "" {
+		parsedIssuer, err = ParseIssuer(cert.Issuer)
+		if err != nil {
+			return Cert{}, err
+		}
+	}
+
+	parsedTags := make([]Tag, len(cert.Tags))
+	for i, t := range cert.Tags {
+		parsedTags[i], err = ParseTag(t)
+		if err != nil {
+			return Cert{}, err
+		}
 	}
 
 	return Cert{
-		ID:        parsedID,
-		UserID:    parsedUserID,
-		CreatedAt: cert.CreatedAt,
-		UpdatedAt: cert.UpdatedAt,
-		ExpiresAt: cert.ExpiresAt,
-		Domain:    parsedDomain,
-		Issuer:    parsedIssuer,
-		Error:     cert.Error,
+		ID:           parsedID,
+		UserID:       parsedUserID,
+		CreatedAt:    cert.CreatedAt,
+		UpdatedAt:    cert.UpdatedAt,
+		ExpiresAt:    cert.ExpiresAt,
+		Domain:       parsedDomain,
+		Issuer:       parsedIssuer,
+		Error:        cert.Error,
+		Tags:         parsedTags,
+		SnoozedUntil: snoozedUntil(cert),
+		Checking:     cert.Checking,
 	}, nil
 }
+
+func snoozedUntil(cert repoCert) time.Time {
+	if cert.SnoozedUntil == nil {
+		return time.Time{}
+	}
+	return *cert.SnoozedUntil
+}
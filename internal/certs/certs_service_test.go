@@ -0,0 +1,44 @@
+package certs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeStatsRepo returns the same counts, the methods the stats don't use panic.
+type fakeStatsRepo struct {
+	Repo
+	counts []repoStatusCount
+}
+
+func (r fakeStatsRepo) CountByStatus(_ context.Context) ([]repoStatusCount, error) {
+	return r.counts, nil
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	oldest := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	repo := fakeStatsRepo{counts: []repoStatusCount{
+		{Status: "ok", Count: 4, OldestCheck: oldest.Add(time.Hour)},
+		{Status: "error", Count: 1, OldestCheck: oldest},
+		{Status: "pending", Count: 2, OldestCheck: oldest.Add(-24 * time.Hour)},
+	}}
+	s := NewService(nil, repo, nil, 10)
+
+	stats, err := s.Stats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"ok": 4, "error": 1, "pending": 2}
+	for status, count := range want {
+		if stats.ByStatus[status] != count {
+			t.Errorf("Expected %d %s certs, got %d", count, status, stats.ByStatus[status])
+		}
+	}
+	if !stats.OldestCheck.Equal(oldest) {
+		t.Errorf("Expected the oldest check %s, ignoring pending certs, got %s", oldest, stats.OldestCheck)
+	}
+}
@@ -0,0 +1,125 @@
+package certs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/tlser"
+)
+
+// EventKind is the type of change recorded in the history of a Cert.
+type EventKind string
+
+const (
+	EventRegistered EventKind = "registered"
+	EventRenewed    EventKind = "renewed"
+	EventExpiring   EventKind = "expiring"
+	EventExpired    EventKind = "expired"
+	EventError      EventKind = "error"
+	EventRecovered  EventKind = "recovered"
+	EventDeleted    EventKind = "deleted"
+)
+
+func ParseEventKind(kind string) (EventKind, error) {
+	switch k := EventKind(kind); k {
+	case EventRegistered, EventRenewed, EventExpiring, EventExpired, EventError, EventRecovered, EventDeleted:
+		return k, nil
+	default:
+		return "", ErrInvalidEventKind
+	}
+}
+
+// Event is a change in a Cert, e.g. it was renewed or it became unreachable.
+type Event struct {
+	ID        common.ID
+	UserID    common.ID
+	CertID    common.ID
+	Domain    string
+	Kind      EventKind
+	Detail    string
+	CreatedAt time.Time
+}
+
+func newEvent(cert repoCert, kind EventKind, detail string) repoEvent {
+	return repoEvent{
+		ID:        common.NewID().String(),
+		UserID:    cert.UserID,
+		CertID:    cert.ID,
+		Domain:    cert.Domain,
+		Kind:      string(kind),
+		Detail:    detail,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// changes returns the events caused by probing the cert and getting data,
+// comparing it to the state stored after the previous check.
+func changes(cert repoCert, data tlser.CertData) []repoEvent {
+	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
+		if cert.Error != string(data.Status) {
+			return []repoEvent{newEvent(cert, EventError, string(data.Status))}
+		}
+		return nil
+	}
+
+	events := []repoEvent{}
+	if cert.Error != "" {
+		events = append(events, newEvent(cert, EventRecovered, "reachable again after "+cert.Error))
+	}
+
+	if !cert.ExpiresAt.IsZero() && data.Expiry.After(cert.ExpiresAt) {
+		detail := fmt.Sprintf("now expires %s", data.Expiry.Format(time.DateOnly))
+		return append(events, newEvent(cert, EventRenewed, detail))
+	}
+
+	checkedAt := cert.UpdatedAt
+	if checkedAt.IsZero() {
+		checkedAt = cert.CreatedAt
+	}
+	before := ExpirationStatus(int(cert.ExpiresAt.Sub(checkedAt).Hours()))
+	now := ExpirationStatus(HoursToExpiration(data.Expiry))
+
+	switch {
+	case now == before || now == "":
+	case now == "expired":
+		events = append(events, newEvent(cert, EventExpired, now))
+	default:
+		events = append(events, newEvent(cert, EventExpiring, now))
+	}
+
+	return events
+}
+
+// repoToEventAdapter transforms an Event from the Repository layer to the Service layer.
+func repoToEventAdapter(e repoEvent) (Event, error) {
+	parsedID, err := common.ParseID(e.ID)
+	if err != nil {
+		return Event{}, err
+	}
+
+	parsedUserID, err := common.ParseID(e.UserID)
+	if err != nil {
+		return Event{}, err
+	}
+
+	parsedCertID, err := common.ParseID(e.CertID)
+	if err != nil {
+		return Event{}, err
+	}
+
+	parsedKind, err := ParseEventKind(e.Kind)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:        parsedID,
+		UserID:    parsedUserID,
+		CertID:    parsedCertID,
+		Domain:    e.Domain,
+		Kind:      parsedKind,
+		Detail:    e.Detail,
+		CreatedAt: e.CreatedAt,
+	}, nil
+}
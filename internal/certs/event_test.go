@@ -0,0 +1,61 @@
+package certs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/tlser"
+)
+
+func TestChanges(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	healthy := repoCert{
+		ID:        "018ec52b-dd69-7df4-b8e7-edcdc9a3a891",
+		UserID:    "018ec52b-dd69-7df4-b8e7-edcdc9a3a892",
+		Domain:    "example.com",
+		ExpiresAt: now.Add(30 * 24 * time.Hour),
+		UpdatedAt: now.Add(-24 * time.Hour),
+	}
+	failing := healthy
+	failing.Error = string(tlser.StatusCannotConnect)
+	expiring := healthy
+	expiring.ExpiresAt = now.Add(48 * time.Hour)
+	unchecked := expiring
+	unchecked.UpdatedAt = now.Add(-time.Hour)
+
+	tt := []struct {
+		name     string
+		cert     repoCert
+		data     tlser.CertData
+		expected []EventKind
+	}{
+		{"unchanged", healthy, tlser.CertData{Status: tlser.StatusOK, Expiry: healthy.ExpiresAt}, []EventKind{}},
+		{"new error", healthy, tlser.CertData{Status: tlser.StatusCannotConnect}, []EventKind{EventError}},
+		{"same error", failing, tlser.CertData{Status: tlser.StatusCannotConnect}, []EventKind{}},
+		{"other error", failing, tlser.CertData{Status: tlser.StatusHostnameMismatch}, []EventKind{EventError}},
+		{"recovered", failing, tlser.CertData{Status: tlser.StatusOK, Expiry: healthy.ExpiresAt}, []EventKind{EventRecovered}},
+		{"renewed", expiring, tlser.CertData{Status: tlser.StatusOK, Expiry: now.Add(90 * 24 * time.Hour)}, []EventKind{EventRenewed}},
+		{"recovered and renewed", failing, tlser.CertData{Status: tlser.StatusOK, Expiry: now.Add(90 * 24 * time.Hour)}, []EventKind{EventRecovered, EventRenewed}},
+		{"starts expiring", expiring, tlser.CertData{Status: tlser.StatusOK, Expiry: expiring.ExpiresAt}, []EventKind{EventExpiring}},
+		{"still expiring", unchecked, tlser.CertData{Status: tlser.StatusOK, Expiry: unchecked.ExpiresAt}, []EventKind{}},
+		{"expired", unchecked, tlser.CertData{Status: tlser.StatusExpired, Expiry: now.Add(-time.Hour)}, []EventKind{EventExpired}},
+	}
+
+	for _, tc := range tt {
+		events := changes(tc.cert, tc.data)
+		if len(events) != len(tc.expected) {
+			t.Errorf("%s: expected %d events, got %+v", tc.name, len(tc.expected), events)
+			continue
+		}
+		for i, e := range events {
+			if e.Kind != string(tc.expected[i]) {
+				t.Errorf("%s: expected event %q, got %q", tc.name, tc.expected[i], e.Kind)
+			}
+			if e.Domain != "example.com" || e.CertID != healthy.ID || e.UserID != healthy.UserID {
+				t.Errorf("%s: unexpected event %+v", tc.name, e)
+			}
+		}
+	}
+}
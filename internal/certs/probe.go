@@ -0,0 +1,222 @@
+package certs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/tlser"
+	"github.com/germandv/domainator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProbeKind is why a Cert is checked outside the batch of the worker.
+type ProbeKind string
+
+const (
+	ProbeRegister ProbeKind = "register"
+	ProbeRefresh  ProbeKind = "refresh"
+)
+
+const (
+	// probeLease is how long a claimed job is hidden from other workers, longer than a probe takes.
+	probeLease = time.Minute
+	// probePollInterval is how long an idle worker waits before looking for jobs again.
+	probePollInterval = time.Second
+	// maxProbeAttempts is how many times a job that fails to store its result is run,
+	// counting those whose worker died before finishing it.
+	maxProbeAttempts = 3
+)
+
+// Errors stored for a cert when the probe has no tlser.CertStatus to store.
+const (
+	// statusInvalidIssuer is stored when the server sent a cert without a usable issuer.
+	statusInvalidIssuer = "InvalidIssuer"
+	// statusProbeFailed is stored when a cert that was never checked runs out of attempts.
+	statusProbeFailed = "ProbeFailed"
+)
+
+func newProbeJob(cert repoCert, kind ProbeKind) repoProbeJob {
+	now := time.Now().UTC()
+	return repoProbeJob{
+		ID:        common.NewID().String(),
+		CertID:    cert.ID,
+		UserID:    cert.UserID,
+		Kind:      string(kind),
+		Attempts:  0,
+		RunAt:     now,
+		CreatedAt: now,
+	}
+}
+
+// RunProbes checks the queued certs with a pool of workers until ctx is done.
+// Any number of instances can run it, a job is only claimed by one worker.
+func (s *CertsService) RunProbes(ctx context.Context, workers int, logger *slog.Logger) {
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			s.probeWorker(ctx, logger)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *CertsService) probeWorker(ctx context.Context, logger *slog.Logger) {
+	for {
+		err := s.processProbe(ctx, logger)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrNoProbe) && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "failed to process probe job", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(probePollInterval):
+		}
+	}
+}
+
+// processProbe runs the next job that is due, ErrNoProbe if there is none.
+func (s *CertsService) processProbe(ctx context.Context, logger *slog.Logger) error {
+	job, err := s.repo.ClaimProbe(ctx, probeLease)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "certs.probe_job", trace.SpanKindConsumer,
+		attribute.String("job.kind", job.Kind),
+		attribute.Int("job.attempts", job.Attempts),
+	)
+	var cert repoCert
+	if job.Attempts > maxProbeAttempts {
+		// The previous attempts never finished, e.g. the worker crashed, so it isn't tried again.
+		err = fmt.Errorf("probe job %s abandoned after %d attempts", job.ID, maxProbeAttempts)
+	} else {
+		cert, err = s.runProbe(ctx, job)
+	}
+	tracing.End(span, err)
+
+	// Once claimed, the job is finished even if ctx is canceled, so it isn't run twice.
+	ctx = context.WithoutCancel(ctx)
+	jobID, idErr := common.ParseID(job.ID)
+	if idErr != nil {
+		return idErr
+	}
+
+	if err != nil && job.Attempts < maxProbeAttempts {
+		retryErr := s.repo.RetryProbe(ctx, jobID, time.Now().UTC().Add(probePollInterval<<(job.Attempts-1)))
+		return errors.Join(err, retryErr)
+	}
+	if err != nil {
+		err = errors.Join(err, s.failPending(ctx, job))
+	}
+
+	finishErr := s.repo.FinishProbe(ctx, jobID)
+	if errors.Is(finishErr, ErrNotFound) {
+		// The cert, and its job with it, was deleted meanwhile.
+		finishErr = nil
+	}
+
+	// Published once the job is gone, so the dashboards don't show the cert as still being checked.
+	if finishErr == nil && cert.ID != "" {
+		s.publish(ctx, cert, logger)
+	}
+
+	return errors.Join(err, finishErr)
+}
+
+// failPending stores statusProbeFailed for the cert of a job out of attempts if it was never checked,
+// so it isn't shown as pending forever. Certs checked before keep their last result.
+func (s *CertsService) failPending(ctx context.Context, job repoProbeJob) error {
+	certID, err := common.ParseID(job.CertID)
+	if err != nil {
+		return err
+	}
+	userID, err := common.ParseID(job.UserID)
+	if err != nil {
+		return err
+	}
+
+	cert, err := s.repo.Get(ctx, certID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !cert.ExpiresAt.IsZero() || cert.Error != "" {
+		return nil
+	}
+
+	return s.repo.UpdateWithError(ctx, userID, certID, statusProbeFailed, time.Now().UTC())
+}
+
+// runProbe checks the cert of the job and stores the result, it returns no cert if it was deleted.
+func (s *CertsService) runProbe(ctx context.Context, job repoProbeJob) (repoCert, error) {
+	certID, err := common.ParseID(job.CertID)
+	if err != nil {
+		return repoCert{}, err
+	}
+
+	cert, err := s.repo.Get(ctx, certID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return repoCert{}, nil
+		}
+		return repoCert{}, err
+	}
+
+	data := s.tlsClient.GetCertData(ctx, cert.Domain)
+
+	events := changes(cert, data)
+	if ProbeKind(job.Kind) == ProbeRegister {
+		detail := ""
+		if data.Status == tlser.StatusOK || data.Status == tlser.StatusExpired {
+			detail = fmt.Sprintf("expires %s", data.Expiry.Format(time.DateOnly))
+			events = nil
+		}
+		events = append([]repoEvent{newEvent(cert, EventRegistered, detail)}, events...)
+	}
+
+	err = s.store(ctx, cert, data)
+	if err != nil {
+		return cert, err
+	}
+
+	return cert, s.repo.SaveEvents(ctx, events)
+}
+
+// store saves the result of checking the cert.
+func (s *CertsService) store(ctx context.Context, cert repoCert, data tlser.CertData) error {
+	userID, err := common.ParseID(cert.UserID)
+	if err != nil {
+		return err
+	}
+	certID, err := common.ParseID(cert.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if data.Status != tlser.StatusOK && data.Status != tlser.StatusExpired {
+		return s.repo.UpdateWithError(ctx, userID, certID, string(data.Status), now)
+	}
+
+	issuer, err := ParseIssuer(data.Issuer)
+	if err != nil {
+		// Checking again would get the same cert, so it's stored as the result.
+		return s.repo.UpdateWithError(ctx, userID, certID, statusInvalidIssuer, now)
+	}
+
+	return s.repo.Update(ctx, userID, certID, data.Expiry, issuer.value, now)
+}
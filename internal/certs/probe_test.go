@@ -0,0 +1,123 @@
+package certs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/tlser"
+)
+
+// fakeProbeRepo keeps a single cert and job in memory, the methods the probes don't use panic.
+type fakeProbeRepo struct {
+	Repo
+	cert      repoCert
+	job       repoProbeJob
+	updateErr error
+	// storedError is what UpdateWithError stored, retried and finished what happened to the job.
+	storedError string
+	retried     bool
+	finished    bool
+}
+
+func (r *fakeProbeRepo) ClaimProbe(_ context.Context, _ time.Duration) (repoProbeJob, error) {
+	return r.job, nil
+}
+
+func (r *fakeProbeRepo) Get(_ context.Context, _ common.ID) (repoCert, error) {
+	return r.cert, nil
+}
+
+func (r *fakeProbeRepo) Update(_ context.Context, _ common.ID, _ common.ID, _ time.Time, _ string, _ time.Time) error {
+	return r.updateErr
+}
+
+func (r *fakeProbeRepo) UpdateWithError(_ context.Context, _ common.ID, _ common.ID, err string, _ time.Time) error {
+	r.storedError = err
+	return nil
+}
+
+func (r *fakeProbeRepo) SaveEvents(_ context.Context, _ []repoEvent) error {
+	return nil
+}
+
+func (r *fakeProbeRepo) RetryProbe(_ context.Context, _ common.ID, _ time.Time) error {
+	r.retried = true
+	return nil
+}
+
+func (r *fakeProbeRepo) FinishProbe(_ context.Context, _ common.ID) error {
+	r.finished = true
+	return nil
+}
+
+type fakeTLSer struct {
+	data   tlser.CertData
+	probed *bool
+}
+
+func (f fakeTLSer) GetCertData(_ context.Context, _ string) tlser.CertData {
+	*f.probed = true
+	return f.data
+}
+
+func TestProcessProbe(t *testing.T) {
+	t.Parallel()
+
+	valid := tlser.CertData{Status: tlser.StatusOK, Expiry: time.Now().Add(90 * 24 * time.Hour), Issuer: "R3"}
+	checked := time.Now().Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		data        tlser.CertData
+		attempts    int
+		expiresAt   time.Time
+		updateErr   error
+		wantErr     bool
+		probed      bool
+		storedError string
+		retried     bool
+		finished    bool
+	}{
+		{"ok", valid, 1, time.Time{}, nil, false, true, "", false, true},
+		{"invalid_issuer", tlser.CertData{Status: tlser.StatusOK, Expiry: valid.Expiry}, 1, time.Time{}, nil, false, true, statusInvalidIssuer, false, true},
+		{"failed_attempt", valid, 1, time.Time{}, errors.New("db down"), true, true, "", true, false},
+		{"last_attempt_pending", valid, maxProbeAttempts, time.Time{}, errors.New("db down"), true, true, statusProbeFailed, false, true},
+		{"last_attempt_checked", valid, maxProbeAttempts, checked, errors.New("db down"), true, true, "", false, true},
+		{"abandoned", valid, maxProbeAttempts + 1, time.Time{}, nil, true, false, statusProbeFailed, false, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cert := repoCert{ID: common.NewID().String(), UserID: common.NewID().String(), Domain: "example.com", ExpiresAt: tc.expiresAt}
+			job := newProbeJob(cert, ProbeRefresh)
+			job.Attempts = tc.attempts
+			repo := &fakeProbeRepo{cert: cert, job: job, updateErr: tc.updateErr}
+			probed := false
+			s := NewService(fakeTLSer{tc.data, &probed}, repo, nil, 10)
+
+			err := s.processProbe(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %t, got %v", tc.wantErr, err)
+			}
+			if probed != tc.probed {
+				t.Errorf("Expected probed %t, got %t", tc.probed, probed)
+			}
+			if repo.storedError != tc.storedError {
+				t.Errorf("Expected stored error %q, got %q", tc.storedError, repo.storedError)
+			}
+			if repo.retried != tc.retried {
+				t.Errorf("Expected retried %t, got %t", tc.retried, repo.retried)
+			}
+			if repo.finished != tc.finished {
+				t.Errorf("Expected finished %t, got %t", tc.finished, repo.finished)
+			}
+		})
+	}
+}
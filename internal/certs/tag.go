@@ -0,0 +1,64 @@
+package certs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const maxTagsPerCert = 10
+
+var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_\-]{0,31}$`)
+
+type Tag struct {
+	value string
+}
+
+func ParseTag(tag string) (Tag, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !tagRegex.MatchString(tag) {
+		return Tag{}, fmt.Errorf("error parsing tag %s: %w", tag, ErrInvalidTag)
+	}
+	return Tag{value: tag}, nil
+}
+
+// ParseTags parses a comma separated list of tags, ignoring empty items and duplicates.
+func ParseTags(tags string) ([]Tag, error) {
+	parsed := []Tag{}
+	seen := map[string]bool{}
+
+	for _, t := range strings.Split(tags, ",") {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+
+		tag, err := ParseTag(t)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[tag.value] {
+			continue
+		}
+		seen[tag.value] = true
+		parsed = append(parsed, tag)
+	}
+
+	if len(parsed) > maxTagsPerCert {
+		return nil, fmt.Errorf("cannot have more than %d tags: %w", maxTagsPerCert, ErrInvalidTag)
+	}
+
+	return parsed, nil
+}
+
+func (t Tag) String() string {
+	return t.value
+}
+
+func tagsToStrings(tags []Tag) []string {
+	strs := make([]string, len(tags))
+	for i, t := range tags {
+		strs[i] = t.value
+	}
+	return strs
+}
@@ -0,0 +1,64 @@
+package certs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  Tag
+		err   error
+	}{
+		{"prod", Tag{value: "prod"}, nil},
+		{"  Prod  ", Tag{value: "prod"}, nil},
+		{"team-a_1", Tag{value: "team-a_1"}, nil},
+		{"", Tag{}, ErrInvalidTag},
+		{"-prod", Tag{}, ErrInvalidTag},
+		{"with space", Tag{}, ErrInvalidTag},
+		{"averyveryveryveryveryverylongtagname", Tag{}, ErrInvalidTag},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseTag(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("expected error %q but got %q", tc.err, err)
+		}
+		if got.value != tc.want.value {
+			t.Errorf("expected %q but got %q", tc.want.value, got.value)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  []string
+		err   error
+	}{
+		{"", []string{}, nil},
+		{"prod, eu", []string{"prod", "eu"}, nil},
+		{"prod,,PROD, eu ,", []string{"prod", "eu"}, nil},
+		{"prod, bad tag", nil, ErrInvalidTag},
+		{"a,b,c,d,e,f,g,h,i,j,k", nil, ErrInvalidTag},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseTags(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("expected error %q but got %q", tc.err, err)
+		}
+		strs := tagsToStrings(got)
+		if len(strs) != len(tc.want) {
+			t.Fatalf("expected %v but got %v", tc.want, strs)
+		}
+		for i := range strs {
+			if strs[i] != tc.want[i] {
+				t.Errorf("expected %v but got %v", tc.want, strs)
+			}
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package channels
+
+import "errors"
+
+var (
+	ErrInvalidKind       = errors.New("invalid channel kind")
+	ErrInvalidName       = errors.New("name is required and must be at most 64 characters")
+	ErrInvalidTarget     = errors.New("invalid target for the channel kind")
+	ErrInvalidCredential = errors.New("invalid token for the channel kind")
+	ErrInvalidPattern    = errors.New("invalid domain pattern, use a glob like *.example.com")
+	ErrNotFound          = errors.New("channel not found")
+	ErrRuleNotFound      = errors.New("rule not found")
+	ErrTooMany           = errors.New("too many channels")
+	ErrTemplate          = errors.New("failed to render template, default message sent")
+	ErrSlackUserNotFound = errors.New("the Slack user is not linked to a Domainator account")
+	ErrUnconfirmed       = errors.New("the email address has to be confirmed first, follow the link sent to it")
+)
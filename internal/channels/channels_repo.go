@@ -0,0 +1,401 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const QueryTimeout = 5 * time.Second
+
+type Repo interface {
+	Save(ctx context.Context, channel repoChannel) error
+	GetAll(ctx context.Context, userID common.ID) ([]repoChannel, error)
+	Get(ctx context.Context, userID common.ID, id common.ID) (repoChannel, error)
+	Count(ctx context.Context, userID common.ID) (int, error)
+	SetEnabled(ctx context.Context, userID common.ID, id common.ID, enabled bool) error
+	Confirm(ctx context.Context, userID common.ID, id common.ID) error
+	SetSecret(ctx context.Context, userID common.ID, id common.ID, secret string) error
+	DisableByTarget(ctx context.Context, userID common.ID, kind string, target string) error
+	RecordFailure(ctx context.Context, userID common.ID, id common.ID, maxFailures int) (repoChannel, error)
+	ResetFailures(ctx context.Context, userID common.ID, id common.ID) error
+	Delete(ctx context.Context, userID common.ID, id common.ID) error
+	GetRules(ctx context.Context, userID common.ID) ([]repoRule, error)
+	SaveRule(ctx context.Context, userID common.ID, rule repoRule) error
+	DeleteRule(ctx context.Context, userID common.ID, id common.ID) error
+	GetTemplates(ctx context.Context, userID common.ID) ([]repoTemplate, error)
+	SaveTemplate(ctx context.Context, userID common.ID, template repoTemplate) error
+	DeleteTemplate(ctx context.Context, userID common.ID, channelID common.ID, event string) error
+	SaveSlackInstallation(ctx context.Context, channel repoChannel, installation repoSlackInstallation) error
+	GetSlackUser(ctx context.Context, teamID string, slackUserID string) (string, error)
+	LinkSlackUser(ctx context.Context, teamID string, slackUserID string, userID common.ID) error
+	ThreadRepo
+}
+
+// ThreadRepo stores the Slack threads started by Slack app channels.
+type ThreadRepo interface {
+	GetThread(ctx context.Context, channelID common.ID, key string) (string, error)
+	SetThread(ctx context.Context, channelID common.ID, key string, ts string) error
+	DeleteThread(ctx context.Context, channelID common.ID, key string) error
+}
+
+type ChannelsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *ChannelsRepo {
+	return &ChannelsRepo{db}
+}
+
+func (r *ChannelsRepo) Save(ctx context.Context, channel repoChannel) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `insert into notification_channels (id, user_id, kind, name, target, secret, enabled, confirmed)
+    values ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.Exec(
+		ctx,
+		q,
+		channel.ID,
+		channel.UserID,
+		channel.Kind,
+		channel.Name,
+		channel.Target,
+		channel.Secret,
+		channel.Enabled,
+		channel.Confirmed,
+	)
+
+	return err
+}
+
+func (r *ChannelsRepo) GetAll(ctx context.Context, userID common.ID) ([]repoChannel, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      id, user_id, kind, name, target, secret, enabled, confirmed, consecutive_failures, created_at
+    from
+      notification_channels
+    where
+      user_id = $1
+    order by id`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoChannel])
+}
+
+func (r *ChannelsRepo) Get(ctx context.Context, userID common.ID, id common.ID) (repoChannel, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      id, user_id, kind, name, target, secret, enabled, confirmed, consecutive_failures, created_at
+    from
+      notification_channels
+    where
+      id = $1 and user_id = $2`
+
+	rows, _ := r.db.Query(ctx, q, id, userID)
+	channel, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[repoChannel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoChannel{}, ErrNotFound
+		}
+		return repoChannel{}, err
+	}
+
+	return channel, nil
+}
+
+func (r *ChannelsRepo) Count(ctx context.Context, userID common.ID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	count := 0
+	q := "select count(*) from notification_channels where user_id = $1"
+
+	err := r.db.QueryRow(ctx, q, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *ChannelsRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetEnabled enables or disables the channel, resetting its count of failed deliveries.
+func (r *ChannelsRepo) SetEnabled(ctx context.Context, userID common.ID, id common.ID, enabled bool) error {
+	q := `update notification_channels set enabled = $3, consecutive_failures = 0 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID, enabled)
+}
+
+// Confirm marks the channel as confirmed from its target and enables it.
+func (r *ChannelsRepo) Confirm(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `update notification_channels set confirmed = true, enabled = true, consecutive_failures = 0 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID)
+}
+
+func (r *ChannelsRepo) SetSecret(ctx context.Context, userID common.ID, id common.ID, secret string) error {
+	q := `update notification_channels set secret = $3 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID, secret)
+}
+
+func (r *ChannelsRepo) DisableByTarget(ctx context.Context, userID common.ID, kind string, target string) error {
+	q := `update notification_channels set enabled = false where user_id = $1 and kind = $2 and lower(target) = lower($3)`
+	return r.update(ctx, q, userID, kind, target)
+}
+
+// RecordFailure increments the count of consecutive failed deliveries of the channel,
+// disabling it once it reaches maxFailures, and returns the updated channel.
+func (r *ChannelsRepo) RecordFailure(ctx context.Context, userID common.ID, id common.ID, maxFailures int) (repoChannel, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    update
+      notification_channels
+    set
+      consecutive_failures = consecutive_failures + 1,
+      enabled = enabled and consecutive_failures + 1 < $3
+    where
+      id = $1 and user_id = $2
+    returning
+      id, user_id, kind, name, target, secret, enabled, confirmed, consecutive_failures, created_at`
+
+	rows, _ := r.db.Query(ctx, q, id, userID, maxFailures)
+	channel, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[repoChannel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoChannel{}, ErrNotFound
+		}
+		return repoChannel{}, err
+	}
+
+	return channel, nil
+}
+
+func (r *ChannelsRepo) ResetFailures(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `update notification_channels set consecutive_failures = 0 where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID)
+}
+
+// Delete removes the channel, its rules are deleted on cascade.
+func (r *ChannelsRepo) Delete(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `delete from notification_channels where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID)
+}
+
+func (r *ChannelsRepo) GetRules(ctx context.Context, userID common.ID) ([]repoRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      r.id, r.channel_id, r.domain_pattern, r.tag, r.min_severity, r.destination, r.destination_name, r.created_at
+    from
+      notification_rules r
+      join notification_channels c on c.id = r.channel_id
+    where
+      c.user_id = $1
+    order by r.id`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoRule])
+}
+
+// SaveRule inserts the rule only if the channel belongs to the user.
+func (r *ChannelsRepo) SaveRule(ctx context.Context, userID common.ID, rule repoRule) error {
+	q := `
+    insert into notification_rules (id, channel_id, domain_pattern, tag, min_severity, destination, destination_name)
+    select $1, $2, $3, $4, $5, $6, $7
+    where exists (select 1 from notification_channels where id = $2 and user_id = $8)`
+	return r.update(
+		ctx,
+		q,
+		rule.ID,
+		rule.ChannelID,
+		rule.DomainPattern,
+		rule.Tag,
+		rule.MinSeverity,
+		rule.Destination,
+		rule.DestinationName,
+		userID,
+	)
+}
+
+func (r *ChannelsRepo) DeleteRule(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `
+    delete from notification_rules r
+    using notification_channels c
+    where r.id = $1 and r.channel_id = c.id and c.user_id = $2`
+
+	err := r.update(ctx, q, id, userID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrRuleNotFound
+	}
+	return err
+}
+
+func (r *ChannelsRepo) GetTemplates(ctx context.Context, userID common.ID) ([]repoTemplate, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      t.channel_id, t.event, t.body, t.updated_at
+    from
+      notification_templates t
+      join notification_channels c on c.id = t.channel_id
+    where
+      c.user_id = $1`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoTemplate])
+}
+
+// SaveTemplate creates or replaces the template of the event, only if the channel belongs to the user.
+func (r *ChannelsRepo) SaveTemplate(ctx context.Context, userID common.ID, template repoTemplate) error {
+	q := `
+    insert into notification_templates (channel_id, event, body, updated_at)
+    select $1, $2, $3, $4
+    where exists (select 1 from notification_channels where id = $1 and user_id = $5)
+    on conflict (channel_id, event) do update set body = excluded.body, updated_at = excluded.updated_at`
+	return r.update(ctx, q, template.ChannelID, template.Event, template.Body, template.UpdatedAt, userID)
+}
+
+func (r *ChannelsRepo) DeleteTemplate(ctx context.Context, userID common.ID, channelID common.ID, event string) error {
+	q := `
+    delete from notification_templates t
+    using notification_channels c
+    where t.channel_id = $1 and t.event = $2 and t.channel_id = c.id and c.user_id = $3`
+	return r.update(ctx, q, channelID, event, userID)
+}
+
+// SaveSlackInstallation saves the channel created by installing the Slack app along with the details of the installation.
+func (r *ChannelsRepo) SaveSlackInstallation(ctx context.Context, channel repoChannel, installation repoSlackInstallation) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		q := `insert into notification_channels (id, user_id, kind, name, target, secret, enabled)
+      values ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := tx.Exec(ctx, q, channel.ID, channel.UserID, channel.Kind, channel.Name, channel.Target, channel.Secret, channel.Enabled)
+		if err != nil {
+			return err
+		}
+
+		q = `insert into slack_installations (channel_id, user_id, team_id, team_name, slack_user_id, bot_user_id, created_at)
+      values ($1, $2, $3, $4, $5, $6, $7)`
+		_, err = tx.Exec(
+			ctx,
+			q,
+			installation.ChannelID,
+			installation.UserID,
+			installation.TeamID,
+			installation.TeamName,
+			installation.SlackUserID,
+			installation.BotUserID,
+			installation.CreatedAt,
+		)
+		if err != nil || installation.SlackUserID == "" {
+			return err
+		}
+
+		// Whoever installs the app can use the slash commands right away.
+		_, err = tx.Exec(ctx, linkSlackUserQuery, installation.TeamID, installation.SlackUserID, installation.UserID)
+		return err
+	})
+}
+
+const linkSlackUserQuery = `
+  insert into slack_users (team_id, slack_user_id, user_id)
+  values ($1, $2, $3)
+  on conflict (team_id, slack_user_id) do update set user_id = excluded.user_id, created_at = (now() at time zone 'utc')`
+
+// GetSlackUser returns the ID of the user a Slack user is linked to.
+func (r *ChannelsRepo) GetSlackUser(ctx context.Context, teamID string, slackUserID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select user_id from slack_users where team_id = $1 and slack_user_id = $2`
+
+	userID := ""
+	err := r.db.QueryRow(ctx, q, teamID, slackUserID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrSlackUserNotFound
+		}
+		return "", err
+	}
+
+	return userID, nil
+}
+
+func (r *ChannelsRepo) LinkSlackUser(ctx context.Context, teamID string, slackUserID string, userID common.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx, linkSlackUserQuery, teamID, slackUserID, userID)
+	return err
+}
+
+// GetThread returns the timestamp of the message that started the thread, empty if there is none.
+func (r *ChannelsRepo) GetThread(ctx context.Context, channelID common.ID, key string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select ts from slack_threads where channel_id = $1 and thread_key = $2`
+
+	ts := ""
+	err := r.db.QueryRow(ctx, q, channelID, key).Scan(&ts)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	return ts, nil
+}
+
+func (r *ChannelsRepo) SetThread(ctx context.Context, channelID common.ID, key string, ts string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    insert into slack_threads (channel_id, thread_key, ts, updated_at)
+    values ($1, $2, $3, $4)
+    on conflict (channel_id, thread_key) do update set ts = excluded.ts, updated_at = excluded.updated_at`
+
+	_, err := r.db.Exec(ctx, q, channelID, key, ts, time.Now().UTC())
+	return err
+}
+
+func (r *ChannelsRepo) DeleteThread(ctx context.Context, channelID common.ID, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `delete from slack_threads where channel_id = $1 and thread_key = $2`
+
+	_, err := r.db.Exec(ctx, q, channelID, key)
+	return err
+}
@@ -0,0 +1,48 @@
+package channels
+
+import "time"
+
+// repoChannel represents a Channel in the Repository layer.
+type repoChannel struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	Kind      string    `db:"kind"`
+	Name      string    `db:"name"`
+	Target    string    `db:"target"`
+	Secret    string    `db:"secret"`
+	Enabled   bool      `db:"enabled"`
+	Confirmed bool      `db:"confirmed"`
+	Failures  int       `db:"consecutive_failures"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// repoRule represents a Rule in the Repository layer.
+type repoRule struct {
+	ID              string    `db:"id"`
+	ChannelID       string    `db:"channel_id"`
+	DomainPattern   string    `db:"domain_pattern"`
+	Tag             string    `db:"tag"`
+	MinSeverity     string    `db:"min_severity"`
+	Destination     string    `db:"destination"`
+	DestinationName string    `db:"destination_name"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// repoSlackInstallation represents a SlackInstallation in the Repository layer.
+type repoSlackInstallation struct {
+	ChannelID   string    `db:"channel_id"`
+	UserID      string    `db:"user_id"`
+	TeamID      string    `db:"team_id"`
+	TeamName    string    `db:"team_name"`
+	SlackUserID string    `db:"slack_user_id"`
+	BotUserID   string    `db:"bot_user_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// repoTemplate represents a message Template in the Repository layer.
+type repoTemplate struct {
+	ChannelID string    `db:"channel_id"`
+	Event     string    `db:"event"`
+	Body      string    `db:"body"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,421 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+const (
+	webhookSecretLength = 32
+	maxNameLength       = 64
+	// MaxFailures is the number of consecutive failed deliveries after which a channel is disabled.
+	MaxFailures = 5
+)
+
+type Service interface {
+	Create(ctx context.Context, req CreateReq) (Channel, error)
+	GetAll(ctx context.Context, req GetAllReq) ([]Channel, error)
+	Get(ctx context.Context, req GetReq) (Channel, error)
+	SetEnabled(ctx context.Context, req SetEnabledReq) (Channel, error)
+	Confirm(ctx context.Context, req ConfirmReq) (Channel, error)
+	RotateSecret(ctx context.Context, req RotateSecretReq) (Channel, error)
+	Delete(ctx context.Context, req DeleteReq) error
+	AddRule(ctx context.Context, req AddRuleReq) (Channel, error)
+	DeleteRule(ctx context.Context, req DeleteRuleReq) error
+	SetTemplate(ctx context.Context, req SetTemplateReq) (Channel, error)
+	DeleteTemplate(ctx context.Context, req DeleteTemplateReq) (Channel, error)
+	DisableEmail(ctx context.Context, req DisableEmailReq) error
+	ReportDelivery(ctx context.Context, req ReportDeliveryReq) (Channel, bool, error)
+	Route(ctx context.Context, req RouteReq) ([]Channel, error)
+	InstallSlack(ctx context.Context, req InstallSlackReq) (Channel, error)
+	GetSlackUser(ctx context.Context, req SlackUserReq) (common.ID, error)
+	LinkSlackUser(ctx context.Context, req LinkSlackUserReq) error
+	EnableWebPush(ctx context.Context, req EnableWebPushReq) (Channel, error)
+}
+
+type ChannelsService struct {
+	repo               Repo
+	maxChannelsPerUser int
+}
+
+func NewService(repo Repo, maxChannelsPerUser int) *ChannelsService {
+	return &ChannelsService{
+		repo:               repo,
+		maxChannelsPerUser: maxChannelsPerUser,
+	}
+}
+
+// Create saves a new channel, JSON webhooks get a signing secret.
+// Email channels are disabled until the address is confirmed, so they can't be used to send mail to anyone.
+func (s *ChannelsService) Create(ctx context.Context, req CreateReq) (Channel, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return Channel{}, ErrInvalidName
+	}
+
+	if req.Kind.IsApp() {
+		return Channel{}, fmt.Errorf("%s channels are added by installing the app: %w", req.Kind.Label(), ErrInvalidKind)
+	}
+
+	target, err := ParseTarget(req.Kind, req.Target)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	// The credential is kept as the secret of the channel, for webhooks it's generated instead.
+	secret, err := ParseCredential(req.Kind, req.Credential)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	count, err := s.repo.Count(ctx, req.UserID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if count >= s.maxChannelsPerUser {
+		return Channel{}, fmt.Errorf("cannot have more than %d channels: %w", s.maxChannelsPerUser, ErrTooMany)
+	}
+
+	if req.Kind == KindWebhook {
+		secret = newWebhookSecret()
+	}
+
+	channel := New(req.UserID, req.Kind, name, target, secret)
+	if req.Kind == KindEmail {
+		channel.Enabled = false
+		channel.Confirmed = false
+	}
+	err = s.repo.Save(ctx, serviceToRepoAdapter(channel))
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
+
+// GetAll returns the channels of the user along with their rules and templates.
+func (s *ChannelsService) GetAll(ctx context.Context, req GetAllReq) ([]Channel, error) {
+	chs, err := s.repo.GetAll(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.GetRules(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesByChannel := map[string][]Rule{}
+	for _, r := range rules {
+		rule, err := repoToRuleAdapter(r)
+		if err != nil {
+			return nil, err
+		}
+		rulesByChannel[r.ChannelID] = append(rulesByChannel[r.ChannelID], rule)
+	}
+
+	templates, err := s.repo.GetTemplates(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	templatesByChannel := map[string][]Template{}
+	for _, t := range templates {
+		template, err := repoToTemplateAdapter(t)
+		if err != nil {
+			return nil, err
+		}
+		templatesByChannel[t.ChannelID] = append(templatesByChannel[t.ChannelID], template)
+	}
+
+	channels := make([]Channel, len(chs))
+	for i, c := range chs {
+		channel, err := repoToServiceAdapter(c)
+		if err != nil {
+			return nil, err
+		}
+		if rules, ok := rulesByChannel[c.ID]; ok {
+			channel.Rules = rules
+		}
+		if templates, ok := templatesByChannel[c.ID]; ok {
+			channel.Templates = templates
+		}
+		channels[i] = channel
+	}
+
+	return channels, nil
+}
+
+func (s *ChannelsService) Get(ctx context.Context, req GetReq) (Channel, error) {
+	channels, err := s.GetAll(ctx, GetAllReq{UserID: req.UserID})
+	if err != nil {
+		return Channel{}, err
+	}
+
+	for _, c := range channels {
+		if c.ID == req.ID {
+			return c, nil
+		}
+	}
+
+	return Channel{}, ErrNotFound
+}
+
+// SetEnabled enables or disables the channel, unconfirmed channels can't be enabled.
+func (s *ChannelsService) SetEnabled(ctx context.Context, req SetEnabledReq) (Channel, error) {
+	if req.Enabled {
+		channel, err := s.repo.Get(ctx, req.UserID, req.ID)
+		if err != nil {
+			return Channel{}, err
+		}
+		if !channel.Confirmed {
+			return Channel{}, ErrUnconfirmed
+		}
+	}
+
+	err := s.repo.SetEnabled(ctx, req.UserID, req.ID, req.Enabled)
+	if err != nil {
+		return Channel{}, err
+	}
+	return s.Get(ctx, GetReq{ID: req.ID, UserID: req.UserID})
+}
+
+// Confirm enables the channel once the link sent to its target has been followed.
+func (s *ChannelsService) Confirm(ctx context.Context, req ConfirmReq) (Channel, error) {
+	err := s.repo.Confirm(ctx, req.UserID, req.ID)
+	if err != nil {
+		return Channel{}, err
+	}
+	return s.Get(ctx, GetReq{ID: req.ID, UserID: req.UserID})
+}
+
+func (s *ChannelsService) RotateSecret(ctx context.Context, req RotateSecretReq) (Channel, error) {
+	channel, err := s.repo.Get(ctx, req.UserID, req.ID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if channel.Kind != KindWebhook.String() {
+		return Channel{}, fmt.Errorf("only JSON webhooks have a signing secret: %w", ErrInvalidKind)
+	}
+
+	err = s.repo.SetSecret(ctx, req.UserID, req.ID, newWebhookSecret())
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return s.Get(ctx, GetReq{ID: req.ID, UserID: req.UserID})
+}
+
+func (s *ChannelsService) Delete(ctx context.Context, req DeleteReq) error {
+	return s.repo.Delete(ctx, req.UserID, req.ID)
+}
+
+// AddRule adds a routing rule to the channel and returns the updated channel.
+func (s *ChannelsService) AddRule(ctx context.Context, req AddRuleReq) (Channel, error) {
+	pattern, err := ParsePattern(req.DomainPattern)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	rule := NewRule(req.ChannelID, pattern, req.Tag, req.MinSeverity)
+	if req.Destination != "" {
+		channel, err := s.repo.Get(ctx, req.UserID, req.ChannelID)
+		if err != nil {
+			return Channel{}, err
+		}
+
+		if channel.Kind != KindSlackApp.String() {
+			return Channel{}, fmt.Errorf("only Slack app channels can post to another Slack channel: %w", ErrInvalidTarget)
+		}
+
+		rule.Destination, err = ParseTarget(KindSlackApp, req.Destination)
+		if err != nil {
+			return Channel{}, err
+		}
+		rule.DestinationName = strings.TrimPrefix(strings.TrimSpace(req.DestinationName), "#")
+	}
+
+	err = s.repo.SaveRule(ctx, req.UserID, ruleToRepoAdapter(rule))
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return s.Get(ctx, GetReq{ID: req.ChannelID, UserID: req.UserID})
+}
+
+func (s *ChannelsService) DeleteRule(ctx context.Context, req DeleteRuleReq) error {
+	return s.repo.DeleteRule(ctx, req.UserID, req.ID)
+}
+
+// SetTemplate validates and saves the message template of the channel for the event.
+func (s *ChannelsService) SetTemplate(ctx context.Context, req SetTemplateReq) (Channel, error) {
+	body := strings.TrimSpace(req.Body)
+	_, err := notifier.ParseMessageTemplate(body)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	err = s.repo.SaveTemplate(ctx, req.UserID, repoTemplate{
+		ChannelID: req.ChannelID.String(),
+		Event:     string(req.Event),
+		Body:      body,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return s.Get(ctx, GetReq{ID: req.ChannelID, UserID: req.UserID})
+}
+
+// DeleteTemplate restores the default message of the channel for the event.
+func (s *ChannelsService) DeleteTemplate(ctx context.Context, req DeleteTemplateReq) (Channel, error) {
+	err := s.repo.DeleteTemplate(ctx, req.UserID, req.ChannelID, string(req.Event))
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return s.Get(ctx, GetReq{ID: req.ChannelID, UserID: req.UserID})
+}
+
+// DisableEmail disables the email channels of the user sending to the address.
+func (s *ChannelsService) DisableEmail(ctx context.Context, req DisableEmailReq) error {
+	return s.repo.DisableByTarget(ctx, req.UserID, KindEmail.String(), req.Address)
+}
+
+// ReportDelivery keeps track of consecutive failed deliveries through the channel,
+// it returns the updated channel and whether it has just been disabled because of them.
+func (s *ChannelsService) ReportDelivery(ctx context.Context, req ReportDeliveryReq) (Channel, bool, error) {
+	if req.OK {
+		err := s.repo.ResetFailures(ctx, req.UserID, req.ID)
+		if err != nil {
+			return Channel{}, false, err
+		}
+		channel, err := s.Get(ctx, GetReq{ID: req.ID, UserID: req.UserID})
+		return channel, false, err
+	}
+
+	c, err := s.repo.RecordFailure(ctx, req.UserID, req.ID, MaxFailures)
+	if err != nil {
+		return Channel{}, false, err
+	}
+
+	channel, err := repoToServiceAdapter(c)
+	if err != nil {
+		return Channel{}, false, err
+	}
+
+	return channel, !channel.Enabled && channel.Failures == MaxFailures, nil
+}
+
+// Route returns the channels of the user the notification has to be delivered through.
+func (s *ChannelsService) Route(ctx context.Context, req RouteReq) ([]Channel, error) {
+	channels, err := s.GetAll(ctx, GetAllReq{UserID: req.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	routed := []Channel{}
+	for _, c := range channels {
+		if c.Routes(req.Notification) {
+			routed = append(routed, c)
+		}
+	}
+
+	return routed, nil
+}
+
+// InstallSlack creates a channel posting to the Slack channel picked while installing the app,
+// the bot token is kept as the secret of the channel.
+func (s *ChannelsService) InstallSlack(ctx context.Context, req InstallSlackReq) (Channel, error) {
+	target, err := ParseTarget(KindSlackApp, req.ChannelID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if req.BotToken == "" {
+		return Channel{}, fmt.Errorf("missing bot token: %w", ErrInvalidTarget)
+	}
+
+	count, err := s.repo.Count(ctx, req.UserID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if count >= s.maxChannelsPerUser {
+		return Channel{}, fmt.Errorf("cannot have more than %d channels: %w", s.maxChannelsPerUser, ErrTooMany)
+	}
+
+	name := fmt.Sprintf("%s #%s", req.TeamName, req.ChannelName)
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	channel := New(req.UserID, KindSlackApp, strings.ToValidUTF8(name, ""), target, req.BotToken)
+	err = s.repo.SaveSlackInstallation(ctx, serviceToRepoAdapter(channel), slackInstallationToRepoAdapter(channel, req))
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
+
+func newWebhookSecret() string {
+	return "whsec_" + common.GenerateRandomString(webhookSecretLength)
+}
+
+// GetSlackUser returns the ID of the user the Slack user is linked to, ErrSlackUserNotFound if there is none.
+func (s *ChannelsService) GetSlackUser(ctx context.Context, req SlackUserReq) (common.ID, error) {
+	userID, err := s.repo.GetSlackUser(ctx, req.TeamID, req.SlackUserID)
+	if err != nil {
+		return common.ID{}, err
+	}
+	return common.ParseID(userID)
+}
+
+// LinkSlackUser lets the Slack user act on behalf of the user, replacing a previous link.
+func (s *ChannelsService) LinkSlackUser(ctx context.Context, req LinkSlackUserReq) error {
+	if req.TeamID == "" || req.SlackUserID == "" {
+		return ErrSlackUserNotFound
+	}
+	return s.repo.LinkSlackUser(ctx, req.TeamID, req.SlackUserID, req.UserID)
+}
+
+// EnableWebPush returns the channel notifying the browsers of the user, creating it the first time a browser subscribes
+// and enabling it again if it was disabled.
+func (s *ChannelsService) EnableWebPush(ctx context.Context, req EnableWebPushReq) (Channel, error) {
+	chs, err := s.GetAll(ctx, GetAllReq{UserID: req.UserID})
+	if err != nil {
+		return Channel{}, err
+	}
+
+	for _, c := range chs {
+		if c.Kind != KindWebPush {
+			continue
+		}
+		if c.Enabled {
+			return c, nil
+		}
+		return s.SetEnabled(ctx, SetEnabledReq{ID: c.ID, UserID: req.UserID, Enabled: true})
+	}
+
+	count := len(chs)
+	if count >= s.maxChannelsPerUser {
+		return Channel{}, fmt.Errorf("cannot have more than %d channels: %w", s.maxChannelsPerUser, ErrTooMany)
+	}
+
+	channel := New(req.UserID, KindWebPush, KindWebPush.Label(), req.UserID.String(), "")
+	err = s.repo.Save(ctx, serviceToRepoAdapter(channel))
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
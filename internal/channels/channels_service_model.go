@@ -0,0 +1,302 @@
+package channels
+
+import (
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+type CreateReq struct {
+	UserID common.ID
+	Kind   Kind
+	Name   string
+	Target string
+	// Credential is the token of Telegram, Matrix and ntfy channels.
+	Credential string
+}
+
+type GetAllReq struct {
+	UserID common.ID
+}
+
+type GetReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type SetEnabledReq struct {
+	ID      common.ID
+	UserID  common.ID
+	Enabled bool
+}
+
+type RotateSecretReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type DeleteReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type AddRuleReq struct {
+	ChannelID     common.ID
+	UserID        common.ID
+	DomainPattern string
+	Tag           string
+	MinSeverity   notifier.Severity
+	// Destination overrides the Slack channel of Slack app channels.
+	Destination     string
+	DestinationName string
+}
+
+type DeleteRuleReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type SetTemplateReq struct {
+	ChannelID common.ID
+	UserID    common.ID
+	Event     notifier.Event
+	Body      string
+}
+
+type DeleteTemplateReq struct {
+	ChannelID common.ID
+	UserID    common.ID
+	Event     notifier.Event
+}
+
+type ConfirmReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type DisableEmailReq struct {
+	UserID  common.ID
+	Address string
+}
+
+type ReportDeliveryReq struct {
+	ID     common.ID
+	UserID common.ID
+	OK     bool
+}
+
+type RouteReq struct {
+	UserID       common.ID
+	Notification notifier.Notification
+}
+
+type InstallSlackReq struct {
+	UserID      common.ID
+	TeamID      string
+	TeamName    string
+	SlackUserID string
+	BotUserID   string
+	BotToken    string
+	ChannelID   string
+	ChannelName string
+}
+
+type EnableWebPushReq struct {
+	UserID common.ID
+}
+
+// SlackUserReq identifies a Slack user by its workspace and user IDs.
+type SlackUserReq struct {
+	TeamID      string
+	SlackUserID string
+}
+
+type LinkSlackUserReq struct {
+	UserID      common.ID
+	TeamID      string
+	SlackUserID string
+}
+
+// Channel is a destination for notifications, e.g. a Slack webhook or an email address.
+type Channel struct {
+	ID      common.ID
+	UserID  common.ID
+	Kind    Kind
+	Name    string
+	Target  string
+	Secret  string
+	Enabled bool
+	// Confirmed is false for email channels until the link sent to the address is followed.
+	Confirmed bool
+	Failures  int
+	CreatedAt time.Time
+	Rules     []Rule
+	Templates []Template
+}
+
+// Rule restricts which notifications are delivered through a Channel.
+// Rules of Slack app channels can post to a Destination other than the default Slack channel.
+type Rule struct {
+	ID              common.ID
+	ChannelID       common.ID
+	DomainPattern   string
+	Tag             string
+	MinSeverity     notifier.Severity
+	Destination     string
+	DestinationName string
+	CreatedAt       time.Time
+}
+
+// Template customises the message sent through a Channel for an event.
+type Template struct {
+	Event     notifier.Event
+	Body      string
+	UpdatedAt time.Time
+}
+
+func New(userID common.ID, kind Kind, name string, target string, secret string) Channel {
+	return Channel{
+		ID:        common.NewID(),
+		UserID:    userID,
+		Kind:      kind,
+		Name:      name,
+		Target:    target,
+		Secret:    secret,
+		Enabled:   true,
+		Confirmed: true,
+		CreatedAt: time.Now(),
+		Rules:     []Rule{},
+		Templates: []Template{},
+	}
+}
+
+func NewRule(channelID common.ID, domainPattern string, tag string, minSeverity notifier.Severity) Rule {
+	return Rule{
+		ID:            common.NewID(),
+		ChannelID:     channelID,
+		DomainPattern: domainPattern,
+		Tag:           tag,
+		MinSeverity:   minSeverity,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// slackInstallationToRepoAdapter transforms the installation of the Slack app that created the channel to the Repository layer.
+func slackInstallationToRepoAdapter(c Channel, req InstallSlackReq) repoSlackInstallation {
+	return repoSlackInstallation{
+		ChannelID:   c.ID.String(),
+		UserID:      c.UserID.String(),
+		TeamID:      req.TeamID,
+		TeamName:    req.TeamName,
+		SlackUserID: req.SlackUserID,
+		BotUserID:   req.BotUserID,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+// serviceToRepoAdapter transforms a Channel from the Service layer to the Repository layer.
+func serviceToRepoAdapter(c Channel) repoChannel {
+	return repoChannel{
+		ID:        c.ID.String(),
+		UserID:    c.UserID.String(),
+		Kind:      c.Kind.String(),
+		Name:      c.Name,
+		Target:    c.Target,
+		Secret:    c.Secret,
+		Enabled:   c.Enabled,
+		Confirmed: c.Confirmed,
+		Failures:  c.Failures,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// repoToServiceAdapter transforms a Channel from the Repository layer to the Service layer.
+func repoToServiceAdapter(c repoChannel) (Channel, error) {
+	parsedID, err := common.ParseID(c.ID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	parsedUserID, err := common.ParseID(c.UserID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	parsedKind, err := ParseKind(c.Kind)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return Channel{
+		ID:        parsedID,
+		UserID:    parsedUserID,
+		Kind:      parsedKind,
+		Name:      c.Name,
+		Target:    c.Target,
+		Secret:    c.Secret,
+		Enabled:   c.Enabled,
+		Confirmed: c.Confirmed,
+		Failures:  c.Failures,
+		CreatedAt: c.CreatedAt,
+		Rules:     []Rule{},
+		Templates: []Template{},
+	}, nil
+}
+
+// ruleToRepoAdapter transforms a Rule from the Service layer to the Repository layer.
+func ruleToRepoAdapter(r Rule) repoRule {
+	return repoRule{
+		ID:              r.ID.String(),
+		ChannelID:       r.ChannelID.String(),
+		DomainPattern:   r.DomainPattern,
+		Tag:             r.Tag,
+		MinSeverity:     string(r.MinSeverity),
+		Destination:     r.Destination,
+		DestinationName: r.DestinationName,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+// repoToRuleAdapter transforms a Rule from the Repository layer to the Service layer.
+func repoToRuleAdapter(r repoRule) (Rule, error) {
+	parsedID, err := common.ParseID(r.ID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	parsedChannelID, err := common.ParseID(r.ChannelID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	parsedSeverity, err := notifier.ParseSeverity(r.MinSeverity)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return Rule{
+		ID:              parsedID,
+		ChannelID:       parsedChannelID,
+		DomainPattern:   r.DomainPattern,
+		Tag:             r.Tag,
+		MinSeverity:     parsedSeverity,
+		Destination:     r.Destination,
+		DestinationName: r.DestinationName,
+		CreatedAt:       r.CreatedAt,
+	}, nil
+}
+
+// repoToTemplateAdapter transforms a Template from the Repository layer to the Service layer.
+func repoToTemplateAdapter(t repoTemplate) (Template, error) {
+	parsedEvent, err := notifier.ParseEvent(t.Event)
+	if err != nil {
+		return Template{}, err
+	}
+
+	return Template{
+		Event:     parsedEvent,
+		Body:      t.Body,
+		UpdatedAt: t.UpdatedAt,
+	}, nil
+}
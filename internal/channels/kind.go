@@ -0,0 +1,212 @@
+package channels
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// Kind is the type of notifier a channel delivers through.
+type Kind string
+
+const (
+	KindChat       Kind = "chat"
+	KindSlack      Kind = "slack"
+	KindDiscord    Kind = "discord"
+	KindTeams      Kind = "teams"
+	KindMattermost Kind = "mattermost"
+	KindEmail      Kind = "email"
+	KindWebhook    Kind = "webhook"
+	KindPagerDuty  Kind = "pagerduty"
+	KindOpsgenie   Kind = "opsgenie"
+	KindTelegram   Kind = "telegram"
+	KindMatrix     Kind = "matrix"
+	KindNtfy       Kind = "ntfy"
+	KindSlackApp   Kind = "slackapp"
+	KindWebPush    Kind = "webpush"
+)
+
+// Kinds lists every kind of channel, in the order they are offered to users.
+var Kinds = []Kind{
+	KindChat,
+	KindSlack,
+	KindDiscord,
+	KindTeams,
+	KindMattermost,
+	KindEmail,
+	KindWebhook,
+	KindPagerDuty,
+	KindOpsgenie,
+	KindTelegram,
+	KindMatrix,
+	KindNtfy,
+	KindSlackApp,
+	KindWebPush,
+}
+
+func ParseKind(kind string) (Kind, error) {
+	k := Kind(strings.TrimSpace(kind))
+	for _, valid := range Kinds {
+		if k == valid {
+			return k, nil
+		}
+	}
+	return "", ErrInvalidKind
+}
+
+func (k Kind) String() string {
+	return string(k)
+}
+
+// Label is the human friendly name of the kind.
+func (k Kind) Label() string {
+	switch k {
+	case KindChat:
+		return "Chat (auto-detect)"
+	case KindSlack:
+		return "Slack"
+	case KindDiscord:
+		return "Discord"
+	case KindTeams:
+		return "Microsoft Teams"
+	case KindMattermost:
+		return "Mattermost"
+	case KindEmail:
+		return "Email"
+	case KindWebhook:
+		return "JSON Webhook"
+	case KindPagerDuty:
+		return "PagerDuty"
+	case KindOpsgenie:
+		return "Opsgenie"
+	case KindTelegram:
+		return "Telegram"
+	case KindMatrix:
+		return "Matrix"
+	case KindNtfy:
+		return "ntfy"
+	case KindSlackApp:
+		return "Slack app"
+	case KindWebPush:
+		return "Browser notifications"
+	default:
+		return string(k)
+	}
+}
+
+// IsIncident reports whether the kind opens incidents in a paging tool,
+// such channels are only meant for alerts about a certificate, not for reports.
+func (k Kind) IsIncident() bool {
+	return k == KindPagerDuty || k == KindOpsgenie
+}
+
+// IsApp reports whether channels of the kind are added by installing an app
+// or allowing notifications in a browser, rather than from a form.
+func (k Kind) IsApp() bool {
+	return k == KindSlackApp || k == KindWebPush
+}
+
+// chatKind returns the chat platform of chat channels.
+func (k Kind) chatKind() (notifier.ChatKind, bool) {
+	switch k {
+	case KindChat:
+		return notifier.ChatKindAuto, true
+	case KindSlack:
+		return notifier.ChatKindSlack, true
+	case KindDiscord:
+		return notifier.ChatKindDiscord, true
+	case KindTeams:
+		return notifier.ChatKindTeams, true
+	case KindMattermost:
+		return notifier.ChatKindMattermost, true
+	default:
+		return notifier.ChatKindAuto, false
+	}
+}
+
+var slackChannelIDRegex = regexp.MustCompile(`^[CG][A-Z0-9]{6,}$`)
+
+// ParseTarget validates where notifications are sent to for the given kind:
+// a webhook URL, an email address, an integration key, a chat or room ID, a Slack channel ID
+// or, for browser notifications, the ID of the user whose browsers are notified.
+func ParseTarget(kind Kind, target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return "", ErrInvalidTarget
+	}
+
+	switch kind {
+	case KindEmail:
+		addr, err := mail.ParseAddress(target)
+		if err != nil {
+			return "", ErrInvalidTarget
+		}
+		return addr.Address, nil
+	case KindPagerDuty, KindOpsgenie:
+		return target, nil
+	case KindWebPush:
+		userID, err := common.ParseID(target)
+		if err != nil {
+			return "", ErrInvalidTarget
+		}
+		return userID.String(), nil
+	case KindSlackApp:
+		if !slackChannelIDRegex.MatchString(target) {
+			return "", ErrInvalidTarget
+		}
+		return target, nil
+	case KindTelegram:
+		if !notifier.ValidTelegramChat(target) {
+			return "", fmt.Errorf("telegram target must be a chat ID or a @channel: %w", ErrInvalidTarget)
+		}
+		return target, nil
+	case KindMatrix:
+		homeserver, room, err := notifier.ParseMatrixTarget(target)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidTarget, err)
+		}
+		return homeserver + "/" + room, nil
+	case KindNtfy:
+		server, topic, err := notifier.ParseNtfyTarget(target)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidTarget, err)
+		}
+		return server + "/" + topic, nil
+	default:
+		u, err := common.ParseURL(target)
+		if err != nil {
+			return "", err
+		}
+		return u.String(), nil
+	}
+}
+
+// ParseCredential validates the token a channel authenticates with: required for Telegram bots and Matrix accounts,
+// optional for ntfy (only protected topics need one) and ignored by the other kinds.
+func ParseCredential(kind Kind, credential string) (string, error) {
+	credential = strings.TrimSpace(credential)
+
+	switch kind {
+	case KindTelegram:
+		if !notifier.ValidTelegramToken(credential) {
+			return "", fmt.Errorf("the token of the Telegram bot, as given by BotFather, is required: %w", ErrInvalidCredential)
+		}
+		return credential, nil
+	case KindMatrix:
+		if credential == "" || strings.ContainsAny(credential, " \t\n") {
+			return "", fmt.Errorf("the access token of the Matrix account is required: %w", ErrInvalidCredential)
+		}
+		return credential, nil
+	case KindNtfy:
+		if strings.ContainsAny(credential, " \t\n") {
+			return "", ErrInvalidCredential
+		}
+		return credential, nil
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,97 @@
+package channels
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+func TestParseKind(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  Kind
+		err   error
+	}{
+		{"slack", KindSlack, nil},
+		{" email ", KindEmail, nil},
+		{"pagerduty", KindPagerDuty, nil},
+		{"", "", ErrInvalidKind},
+		{"fax", "", ErrInvalidKind},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseKind(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		kind  Kind
+		input string
+		want  string
+		err   error
+	}{
+		{KindSlack, "https://hooks.slack.com/services/x", "https://hooks.slack.com/services/x", nil},
+		{KindWebhook, "http://example.com", "", common.ErrInvalidURL},
+		{KindEmail, "Ops <ops@example.com>", "ops@example.com", nil},
+		{KindEmail, "not an email", "", ErrInvalidTarget},
+		{KindPagerDuty, " routing-key ", "routing-key", nil},
+		{KindOpsgenie, "", "", ErrInvalidTarget},
+		{KindTelegram, "-1001234567890", "-1001234567890", nil},
+		{KindTelegram, "@ops_alerts", "@ops_alerts", nil},
+		{KindTelegram, "ops alerts", "", ErrInvalidTarget},
+		{KindMatrix, "https://matrix.example.org/!abc123:example.org", "https://matrix.example.org/!abc123:example.org", nil},
+		{KindMatrix, "!abc123:example.org", "", ErrInvalidTarget},
+		{KindNtfy, "https://ntfy.sh/my-alerts/", "https://ntfy.sh/my-alerts", nil},
+		{KindNtfy, "https://ntfy.sh/", "", ErrInvalidTarget},
+		{KindWebPush, "0190f5a4-7b1e-7c3a-9a5e-2f1d3c4b5a69", "0190f5a4-7b1e-7c3a-9a5e-2f1d3c4b5a69", nil},
+		{KindWebPush, "not-a-user", "", ErrInvalidTarget},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseTarget(tc.kind, tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestParseCredential(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		kind  Kind
+		input string
+		want  string
+		err   error
+	}{
+		{KindTelegram, " 123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw ", "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", nil},
+		{KindTelegram, "", "", ErrInvalidCredential},
+		{KindMatrix, "syt_token", "syt_token", nil},
+		{KindMatrix, "", "", ErrInvalidCredential},
+		{KindNtfy, "", "", nil},
+		{KindNtfy, "tk_token", "tk_token", nil},
+		{KindSlack, "ignored", "", nil},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseCredential(tc.kind, tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// Notifiers builds the notifier.Notifier that delivers through a Channel.
+type Notifiers struct {
+	AppURL   string
+	Emailer  notifier.Notifier
+	Incident notifier.IncidentConfig
+	Threads  ThreadRepo
+	// WebPush notifies the browsers of a user, nil when VAPID keys are not configured.
+	WebPush notifier.Notifier
+}
+
+func (n Notifiers) For(c Channel) notifier.Notifier {
+	if chatKind, ok := c.Kind.chatKind(); ok {
+		return notifier.NewChatNotifier(chatKind, c.Target, n.AppURL)
+	}
+
+	switch c.Kind {
+	case KindEmail:
+		return n.Emailer
+	case KindWebhook:
+		return notifier.NewWebhooker(c.Secret, n.AppURL)
+	case KindSlackApp:
+		var threads notifier.ThreadStore
+		if n.Threads != nil {
+			threads = slackThreads{repo: n.Threads, channelID: c.ID}
+		}
+		return notifier.NewSlackAppNotifier(c.Secret, n.AppURL, threads)
+	case KindTelegram:
+		return notifier.NewTelegramNotifier(c.Secret, n.AppURL)
+	case KindMatrix:
+		return notifier.NewMatrixNotifier(c.Secret, n.AppURL)
+	case KindNtfy:
+		return notifier.NewNtfyNotifier(c.Secret, n.AppURL)
+	case KindWebPush:
+		if n.WebPush == nil {
+			return notifier.NewWebPushNotifier(nil, nil, n.AppURL)
+		}
+		return n.WebPush
+	case KindPagerDuty:
+		return notifier.NewIncidentNotifier(notifier.IncidentProviderPagerDuty, n.Incident, n.AppURL)
+	default:
+		return notifier.NewIncidentNotifier(notifier.IncidentProviderOpsgenie, n.Incident, n.AppURL)
+	}
+}
+
+// Send delivers the notification through the channel using its message template,
+// falling back to the default message if the template cannot be rendered,
+// in which case the returned error wraps ErrTemplate.
+func (n Notifiers) Send(c Channel, notification notifier.Notification) error {
+	prepared, tmplErr := c.Prepare(notification, n.AppURL)
+
+	err := n.For(c).Notify(c.DestinationFor(prepared), prepared)
+	if err != nil {
+		return err
+	}
+
+	if tmplErr != nil {
+		return fmt.Errorf("%w: %w", ErrTemplate, tmplErr)
+	}
+
+	return nil
+}
+
+// slackThreads keeps the Slack threads of a channel.
+type slackThreads struct {
+	repo      ThreadRepo
+	channelID common.ID
+}
+
+func (t slackThreads) GetThread(key string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	return t.repo.GetThread(ctx, t.channelID, key)
+}
+
+func (t slackThreads) SetThread(key string, ts string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	return t.repo.SetThread(ctx, t.channelID, key, ts)
+}
+
+func (t slackThreads) DeleteThread(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	return t.repo.DeleteThread(ctx, t.channelID, key)
+}
@@ -0,0 +1,78 @@
+package channels
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// ParsePattern validates a glob matched against domains, e.g. *.example.com.
+// An empty pattern matches every domain.
+func ParsePattern(pattern string) (string, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return "", nil
+	}
+
+	_, err := path.Match(pattern, "")
+	if err != nil || strings.Contains(pattern, "/") || len(pattern) > 253 {
+		return "", ErrInvalidPattern
+	}
+
+	return pattern, nil
+}
+
+// Matches reports whether the notification satisfies every condition of the rule,
+// empty conditions always match. Resolved notifications skip the severity, so they reach
+// the channels that got the alert they resolve, e.g. to close the incident it opened.
+func (r Rule) Matches(n notifier.Notification) bool {
+	if r.DomainPattern != "" {
+		ok, _ := path.Match(r.DomainPattern, strings.ToLower(n.Domain))
+		if !ok {
+			return false
+		}
+	}
+
+	if r.Tag != "" && !slices.Contains(n.Tags, r.Tag) {
+		return false
+	}
+
+	if n.Status == notifier.StatusResolved {
+		return true
+	}
+
+	return notifier.SeverityOf(n.Status).AtLeast(r.MinSeverity)
+}
+
+// DestinationFor returns where the notification is sent to: the destination of the first matching rule
+// that has one, or the target of the channel.
+func (c Channel) DestinationFor(n notifier.Notification) string {
+	for _, r := range c.Rules {
+		if r.Destination != "" && r.Matches(n) {
+			return r.Destination
+		}
+	}
+	return c.Target
+}
+
+// Routes reports whether the notification should be delivered through the channel:
+// enabled and confirmed channels without rules get everything, otherwise any rule has to match.
+func (c Channel) Routes(n notifier.Notification) bool {
+	if !c.Enabled || !c.Confirmed {
+		return false
+	}
+
+	if len(c.Rules) == 0 {
+		return true
+	}
+
+	for _, r := range c.Rules {
+		if r.Matches(n) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package channels
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"", "", nil},
+		{"  *.Example.com ", "*.example.com", nil},
+		{"api.example.com", "api.example.com", nil},
+		{"[a-", "", ErrInvalidPattern},
+		{"example.com/path", "", ErrInvalidPattern},
+	}
+
+	for _, tc := range tt {
+		got, err := ParsePattern(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	t.Parallel()
+
+	expiredProd := notifier.Notification{Domain: "api.example.com", Status: "expired", Tags: []string{"prod"}}
+	soonStaging := notifier.Notification{Domain: "staging.example.org", Status: "expires soon", Tags: []string{"staging"}}
+	resolvedProd := notifier.Notification{Domain: "api.example.com", Status: notifier.StatusResolved, Tags: []string{"prod"}}
+
+	tt := []struct {
+		name string
+		rule Rule
+		n    notifier.Notification
+		want bool
+	}{
+		{"empty rule", Rule{MinSeverity: notifier.SeverityInfo}, soonStaging, true},
+		{"domain match", Rule{DomainPattern: "*.example.com"}, expiredProd, true},
+		{"domain mismatch", Rule{DomainPattern: "*.example.com"}, soonStaging, false},
+		{"tag match", Rule{Tag: "prod"}, expiredProd, true},
+		{"tag mismatch", Rule{Tag: "prod"}, soonStaging, false},
+		{"severity match", Rule{MinSeverity: notifier.SeverityCritical}, expiredProd, true},
+		{"severity mismatch", Rule{MinSeverity: notifier.SeverityCritical}, soonStaging, false},
+		{"all conditions", Rule{DomainPattern: "api.*", Tag: "prod", MinSeverity: notifier.SeverityCritical}, expiredProd, true},
+		{"resolved skips severity", Rule{MinSeverity: notifier.SeverityCritical}, resolvedProd, true},
+		{"resolved domain mismatch", Rule{DomainPattern: "*.example.org", MinSeverity: notifier.SeverityCritical}, resolvedProd, false},
+		{"resolved tag mismatch", Rule{Tag: "staging", MinSeverity: notifier.SeverityCritical}, resolvedProd, false},
+	}
+
+	for _, tc := range tt {
+		got := tc.rule.Matches(tc.n)
+		if got != tc.want {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestChannelRoutes(t *testing.T) {
+	t.Parallel()
+
+	n := notifier.Notification{Domain: "example.com", Status: "expires soon"}
+	critical := Rule{MinSeverity: notifier.SeverityCritical}
+	warning := Rule{MinSeverity: notifier.SeverityWarning}
+
+	tt := []struct {
+		name    string
+		channel Channel
+		want    bool
+	}{
+		{"no rules", Channel{Enabled: true, Confirmed: true}, true},
+		{"disabled", Channel{Enabled: false, Confirmed: true}, false},
+		{"unconfirmed", Channel{Enabled: true, Confirmed: false}, false},
+		{"no rule matches", Channel{Enabled: true, Confirmed: true, Rules: []Rule{critical}}, false},
+		{"any rule matches", Channel{Enabled: true, Confirmed: true, Rules: []Rule{critical, warning}}, true},
+	}
+
+	for _, tc := range tt {
+		got := tc.channel.Routes(n)
+		if got != tc.want {
+			t.Errorf("%s: expected %t, got %t", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package channels
+
+import "github.com/germandv/domainator/internal/notifier"
+
+// TemplateFor returns the template body for the event,
+// falling back to the one for any event, or empty if the default message is used.
+func (c Channel) TemplateFor(event notifier.Event) string {
+	fallback := ""
+	for _, t := range c.Templates {
+		if t.Event == event {
+			return t.Body
+		}
+		if t.Event == notifier.EventAny {
+			fallback = t.Body
+		}
+	}
+	return fallback
+}
+
+// Prepare returns the notification to deliver through the channel,
+// with its message rendered from the channel template if there is one.
+// Notifications that already have a message, like notices about channels, are kept as they are.
+func (c Channel) Prepare(n notifier.Notification, appURL string) (notifier.Notification, error) {
+	if n.Message != "" {
+		return n, nil
+	}
+
+	body := c.TemplateFor(notifier.EventOf(n.Status))
+	if body == "" {
+		return n, nil
+	}
+
+	msg, err := notifier.RenderMessage(body, n, appURL)
+	if err != nil {
+		return n, err
+	}
+
+	n.Message = msg
+	return n, nil
+}
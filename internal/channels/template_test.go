@@ -0,0 +1,52 @@
+package channels
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+func TestChannelPrepare(t *testing.T) {
+	t.Parallel()
+
+	c := Channel{
+		Templates: []Template{
+			{Event: notifier.EventAny, Body: "{{.Domain}}: {{.Status}}"},
+			{Event: notifier.EventExpiring, Body: "{{.Domain}} expires in {{.DaysLeft}} days"},
+			{Event: notifier.EventError, Body: "{{.Unknown}}"},
+		},
+	}
+
+	tt := []struct {
+		name   string
+		status string
+		want   string
+		err    error
+	}{
+		{"event template", "expires soon", "example.com expires in 3 days", nil},
+		{"fallback template", "expired", "example.com: expired", nil},
+		{"invalid template", "Cannot Connect", "", notifier.ErrInvalidTemplate},
+	}
+
+	for _, tc := range tt {
+		n := notifier.Notification{Domain: "example.com", Status: tc.status, Hours: 72}
+		got, err := c.Prepare(n, "http://localhost:4000")
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected error %v, got %v", tc.name, tc.err, err)
+		}
+		if got.Message != tc.want {
+			t.Errorf("%s: expected message %q, got %q", tc.name, tc.want, got.Message)
+		}
+	}
+
+	n, err := c.Prepare(notifier.Notification{Status: "expired", Message: "notice"}, "")
+	if err != nil || n.Message != "notice" {
+		t.Errorf("Expected message to be kept, got %q (err: %v)", n.Message, err)
+	}
+
+	n, err = Channel{}.Prepare(notifier.Notification{Status: "expired"}, "")
+	if err != nil || n.Message != "" {
+		t.Errorf("Expected default message without templates, got %q (err: %v)", n.Message, err)
+	}
+}
@@ -3,16 +3,22 @@ package cntxt
 import (
 	"context"
 	"net/http"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
 
 const (
-	ContextKeyUserID = contextKey("userID")
-	ContextKeyAvatar = contextKey("avatar")
+	ContextKeyUserID    = contextKey("userID")
+	ContextKeyAvatar    = contextKey("avatar")
+	ContextKeyRequestID = contextKey("requestID")
 )
 
+// SetUserID also adds the user ID to the span of the request.
 func SetUserID(r *http.Request, userID string) *http.Request {
+	trace.SpanFromContext(r.Context()).SetAttributes(semconv.EnduserID(userID))
 	return r.WithContext(context.WithValue(r.Context(), ContextKeyUserID, userID))
 }
 
@@ -35,3 +41,20 @@ func GetAvatar(r *http.Request) string {
 	}
 	return avatar
 }
+
+func SetRequestID(r *http.Request, requestID string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ContextKeyRequestID, requestID))
+}
+
+func GetRequestID(r *http.Request) string {
+	return RequestID(r.Context())
+}
+
+// RequestID returns the ID of the request the context belongs to, for code that only has the context.
+func RequestID(ctx context.Context) string {
+	id, ok := ctx.Value(ContextKeyRequestID).(string)
+	if !ok {
+		return ""
+	}
+	return id
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+
+	"github.com/germandv/domainator/internal/tracing"
 )
 
 var levels = map[string]slog.Level{
@@ -13,6 +15,7 @@ var levels = map[string]slog.Level{
 	"error": slog.LevelError,
 }
 
+// GetLogger returns a logger that adds the trace and request IDs of the context to the records.
 func GetLogger(format string, level string) (*slog.Logger, error) {
 	lvl, ok := levels[level]
 	if !ok {
@@ -22,9 +25,9 @@ func GetLogger(format string, level string) (*slog.Logger, error) {
 	opts := &slog.HandlerOptions{Level: lvl}
 	switch format {
 	case "text":
-		return slog.New(slog.NewTextHandler(os.Stdout, opts)), nil
+		return slog.New(tracing.NewLogHandler(slog.NewTextHandler(os.Stdout, opts))), nil
 	case "json":
-		return slog.New(slog.NewJSONHandler(os.Stdout, opts)), nil
+		return slog.New(tracing.NewLogHandler(slog.NewJSONHandler(os.Stdout, opts))), nil
 	default:
 		return nil, errors.New("invalid log format, use one of 'text' or 'json'")
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/germandv/domainator/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -24,6 +25,7 @@ func InitWithConnStr(connStr string) (*pgxpool.Pool, error) {
 	}
 
 	cfg.MaxConnIdleTime = 15 * time.Minute
+	cfg.ConnConfig.Tracer = tracing.QueryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
 	if err != nil {
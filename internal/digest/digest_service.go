@@ -0,0 +1,128 @@
+package digest
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/outbox"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// maxEvents caps the changes fetched for a report.
+const maxEvents = 500
+
+type Service interface {
+	SendDue(ctx context.Context, req SendDueReq, logger *slog.Logger) (int, error)
+}
+
+type SendDueReq struct {
+	Now time.Time
+}
+
+type DigestService struct {
+	users    users.Service
+	certs    certs.Service
+	channels channels.Service
+	outbox   outbox.Service
+	appURL   string
+}
+
+func NewService(
+	usersService users.Service,
+	certsService certs.Service,
+	channelsService channels.Service,
+	outboxService outbox.Service,
+	appURL string,
+) *DigestService {
+	return &DigestService{
+		users:    usersService,
+		certs:    certsService,
+		channels: channelsService,
+		outbox:   outboxService,
+		appURL:   appURL,
+	}
+}
+
+// SendDue queues a digest for every user whose schedule is due at req.Now,
+// returning how many were queued. Failing users are logged and skipped.
+func (s *DigestService) SendDue(ctx context.Context, req SendDueReq, logger *slog.Logger) (int, error) {
+	subscribers, err := s.users.GetWithDigest(ctx, users.GetWithDigestReq{})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, u := range subscribers {
+		if !u.Digest.Due(req.Now, u.Timezone) {
+			continue
+		}
+
+		err := s.send(ctx, u, req.Now)
+		if err != nil {
+			logger.Error("failed to send digest", "user", u.ID.String(), "error", err.Error())
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (s *DigestService) send(ctx context.Context, u users.User, now time.Time) error {
+	// Changes are reported since the previous digest, but no further back than one period.
+	since := u.Digest.LastSent
+	if earliest := now.Add(-u.Digest.Frequency.Period()); since.Before(earliest) {
+		since = earliest
+	}
+
+	cs, err := s.certs.GetAll(ctx, certs.GetAllReq{UserID: u.ID})
+	if err != nil {
+		return err
+	}
+
+	events, err := s.certs.GetEvents(ctx, certs.GetEventsReq{UserID: u.ID, Since: since, Limit: maxEvents})
+	if err != nil {
+		return err
+	}
+
+	chs, err := s.channels.GetAll(ctx, channels.GetAllReq{UserID: u.ID})
+	if err != nil {
+		return err
+	}
+
+	report := NewReport(u, cs, events, since, now, s.appURL)
+	n := notifier.Notification{
+		UserID:  u.ID.String(),
+		Domain:  report.Title,
+		Status:  notifier.StatusDigest,
+		Message: report.Text(),
+	}
+
+	for _, c := range Recipients(u.Digest, chs) {
+		err = s.outbox.Enqueue(ctx, outbox.EnqueueReq{UserID: u.ID, ChannelID: c.ID, Notification: n})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.users.SetDigestSent(ctx, users.SetDigestSentReq{UserID: u.ID, SentAt: now})
+}
+
+// Recipients returns the enabled channels the digest is delivered through:
+// the one chosen by the user, or all of them except incident channels.
+func Recipients(d users.Digest, chs []channels.Channel) []channels.Channel {
+	recipients := []channels.Channel{}
+	for _, c := range chs {
+		if !c.Enabled || c.Kind.IsIncident() {
+			continue
+		}
+		if d.ChannelID.String() == "" || d.ChannelID.String() == c.ID.String() {
+			recipients = append(recipients, c)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,118 @@
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// maxChanges is the number of changes listed in a report, the rest are only counted.
+const maxChanges = 50
+
+// Report is the content of a digest, dates are in the timezone of the user.
+type Report struct {
+	Title        string
+	Date         time.Time
+	Since        time.Time
+	Days         int
+	Expiring     []certs.Cert
+	Errors       []certs.Cert
+	Changes      []certs.Event
+	DashboardURL string
+}
+
+// NewReport lists the certs of the user expiring within the digest days or failing,
+// and the changes since the given time, oldest first.
+func NewReport(u users.User, cs []certs.Cert, events []certs.Event, since time.Time, now time.Time, appURL string) Report {
+	loc := u.Timezone.Location()
+	title := "Daily digest"
+	if u.Digest.Frequency == users.FrequencyWeekly {
+		title = "Weekly digest"
+	}
+
+	report := Report{
+		Title:        title,
+		Date:         now.In(loc),
+		Since:        since.In(loc),
+		Days:         u.Digest.Days,
+		Expiring:     []certs.Cert{},
+		Errors:       []certs.Cert{},
+		Changes:      make([]certs.Event, len(events)),
+		DashboardURL: strings.TrimSuffix(appURL, "/") + "/dashboard",
+	}
+
+	horizon := now.AddDate(0, 0, u.Digest.Days)
+	for _, c := range cs {
+		if c.Pending() {
+			// Not checked yet, its registration is listed in the changes once it is.
+			continue
+		}
+		if c.Error != "" {
+			report.Errors = append(report.Errors, c)
+		} else if c.ExpiresAt.Before(horizon) {
+			report.Expiring = append(report.Expiring, c)
+		}
+	}
+
+	sort.Slice(report.Expiring, func(i, j int) bool {
+		return report.Expiring[i].ExpiresAt.Before(report.Expiring[j].ExpiresAt)
+	})
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Domain.String() < report.Errors[j].Domain.String()
+	})
+
+	copy(report.Changes, events)
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		return report.Changes[i].CreatedAt.Before(report.Changes[j].CreatedAt)
+	})
+
+	return report
+}
+
+// Text renders the report as plain text, which reads well in every notifier.
+func (r Report) Text() string {
+	loc := r.Date.Location()
+	b := new(strings.Builder)
+
+	fmt.Fprintf(b, "%s for %s\n", r.Title, r.Date.Format(time.DateOnly))
+
+	fmt.Fprintf(b, "\nExpiring in the next %d days (%d):\n", r.Days, len(r.Expiring))
+	for _, c := range r.Expiring {
+		days := int(c.ExpiresAt.Sub(r.Date).Hours() / 24)
+		fmt.Fprintf(b, "- %s: %s, %d days left\n", c.Domain.String(), c.ExpiresAt.In(loc).Format(time.DateOnly), max(days, 0))
+	}
+	if len(r.Expiring) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	fmt.Fprintf(b, "\nErrors (%d):\n", len(r.Errors))
+	for _, c := range r.Errors {
+		fmt.Fprintf(b, "- %s: %s\n", c.Domain.String(), c.Error)
+	}
+	if len(r.Errors) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	fmt.Fprintf(b, "\nChanges since %s (%d):\n", r.Since.Format("2006-01-02 15:04"), len(r.Changes))
+	for i, e := range r.Changes {
+		if i == maxChanges {
+			fmt.Fprintf(b, "- and %d more\n", len(r.Changes)-maxChanges)
+			break
+		}
+		fmt.Fprintf(b, "- %s %s %s", e.CreatedAt.In(loc).Format("2006-01-02 15:04"), e.Domain, e.Kind)
+		if e.Detail != "" {
+			fmt.Fprintf(b, ": %s", e.Detail)
+		}
+		b.WriteString("\n")
+	}
+	if len(r.Changes) == 0 {
+		b.WriteString("- None\n")
+	}
+
+	fmt.Fprintf(b, "\nDashboard: %s", r.DashboardURL)
+	return b.String()
+}
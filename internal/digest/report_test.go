@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func TestReportText(t *testing.T) {
+	t.Parallel()
+
+	madrid, err := users.ParseTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	u := users.User{
+		Timezone: madrid,
+		Digest:   users.Digest{Frequency: users.FrequencyWeekly, Days: 14},
+	}
+
+	cert := func(domain string, expiresIn time.Duration, error string) certs.Cert {
+		d, err := certs.ParseDomain(domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certs.Cert{Domain: d, ExpiresAt: now.Add(expiresIn), Error: error}
+	}
+
+	cs := []certs.Cert{
+		cert("later.example.com", 10*24*time.Hour, ""),
+		cert("soon.example.com", 3*24*time.Hour, ""),
+		cert("fine.example.com", 60*24*time.Hour, ""),
+		cert("down.example.com", 60*24*time.Hour, "CannotConnect"),
+		{Domain: cert("pending.example.com", 0, "").Domain},
+	}
+	events := []certs.Event{
+		{Domain: "new.example.com", Kind: certs.EventRegistered, CreatedAt: now.Add(-time.Hour)},
+		{Domain: "renewed.example.com", Kind: certs.EventRenewed, Detail: "now expires 2027-01-17", CreatedAt: now.Add(-48 * time.Hour)},
+	}
+
+	report := NewReport(u, cs, events, now.Add(-7*24*time.Hour), now, "http://localhost:4000/")
+	text := report.Text()
+
+	expected := []string{
+		"Weekly digest for 2026-10-19",
+		"Expiring in the next 14 days (2):\n- soon.example.com: 2026-10-22, 3 days left\n- later.example.com: 2026-10-29, 10 days left\n",
+		"Errors (1):\n- down.example.com: CannotConnect\n",
+		"Changes since 2026-10-12 08:00 (2):\n- 2026-10-17 08:00 renewed.example.com renewed: now expires 2027-01-17\n- 2026-10-19 07:00 new.example.com registered\n",
+		"Dashboard: http://localhost:4000/dashboard",
+	}
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Errorf("Expected report to contain %q, got:\n%s", e, text)
+		}
+	}
+
+	empty := NewReport(u, nil, nil, now.Add(-24*time.Hour), now, "http://localhost:4000").Text()
+	if strings.Count(empty, "- None") != 3 {
+		t.Errorf("Expected three empty sections, got:\n%s", empty)
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	t.Parallel()
+
+	slack := channels.Channel{ID: common.NewID(), Kind: channels.KindSlack, Enabled: true}
+	email := channels.Channel{ID: common.NewID(), Kind: channels.KindEmail, Enabled: true}
+	disabled := channels.Channel{ID: common.NewID(), Kind: channels.KindDiscord, Enabled: false}
+	pagerduty := channels.Channel{ID: common.NewID(), Kind: channels.KindPagerDuty, Enabled: true}
+	chs := []channels.Channel{slack, email, disabled, pagerduty}
+
+	all := Recipients(users.Digest{}, chs)
+	if len(all) != 2 || all[0].ID != slack.ID || all[1].ID != email.ID {
+		t.Errorf("Expected slack and email channels, got %+v", all)
+	}
+
+	chosen := Recipients(users.Digest{ChannelID: email.ID}, chs)
+	if len(chosen) != 1 || chosen[0].ID != email.ID {
+		t.Errorf("Expected email channel, got %+v", chosen)
+	}
+
+	none := Recipients(users.Digest{ChannelID: disabled.ID}, chs)
+	if len(none) != 0 {
+		t.Errorf("Expected no channels, got %+v", none)
+	}
+}
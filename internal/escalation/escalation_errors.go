@@ -0,0 +1,16 @@
+package escalation
+
+import "errors"
+
+var (
+	ErrInvalidName      = errors.New("name is required and must be at most 64 characters")
+	ErrInvalidDelay     = errors.New("delay must be between 1 and 168 hours")
+	ErrInvalidSnooze    = errors.New("snooze must be between 1 and 168 hours")
+	ErrInvalidState     = errors.New("invalid incident state")
+	ErrTooManySteps     = errors.New("too many escalation steps")
+	ErrNotFound         = errors.New("escalation policy not found")
+	ErrStepNotFound     = errors.New("escalation step not found")
+	ErrIncidentNotFound = errors.New("incident not found")
+	ErrAcknowledged     = errors.New("incident has already been acknowledged")
+	ErrResolved         = errors.New("incident has already been resolved")
+)
@@ -0,0 +1,289 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const QueryTimeout = 5 * time.Second
+
+type Repo interface {
+	SavePolicy(ctx context.Context, policy repoPolicy) error
+	GetPolicies(ctx context.Context, userID common.ID) ([]repoPolicy, error)
+	DeletePolicy(ctx context.Context, userID common.ID, id common.ID) error
+	SaveStep(ctx context.Context, userID common.ID, step repoStep) error
+	GetSteps(ctx context.Context, userID common.ID) ([]repoStep, error)
+	DeleteStep(ctx context.Context, userID common.ID, id common.ID) error
+	SaveIncident(ctx context.Context, incident repoIncident) error
+	UpdateIncident(ctx context.Context, incident repoIncident) error
+	GetIncident(ctx context.Context, id common.ID) (repoIncident, error)
+	GetUnresolvedIncident(ctx context.Context, policyID common.ID, certificateID common.ID) (repoIncident, error)
+	GetIncidents(ctx context.Context, userID common.ID, limit int) ([]repoIncident, error)
+	GetDueIncidents(ctx context.Context, now time.Time) ([]repoIncident, error)
+	ResolveIncidents(ctx context.Context, userID common.ID, certificateID common.ID, resolvedAt time.Time) error
+}
+
+type EscalationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *EscalationRepo {
+	return &EscalationRepo{db}
+}
+
+const incidentColumns = `
+  i.id, i.user_id, i.policy_id, p.name as policy_name, i.certificate_id, i.domain, i.status, i.payload,
+  i.state, i.step, i.next_escalation_at, i.acknowledged_by, i.acknowledged_via, i.acknowledged_at,
+  i.opened_at, i.resolved_at, i.updated_at`
+
+func (r *EscalationRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *EscalationRepo) SavePolicy(ctx context.Context, policy repoPolicy) error {
+	q := `insert into escalation_policies (id, user_id, name, min_severity, created_at) values ($1, $2, $3, $4, $5)`
+	return r.update(ctx, q, policy.ID, policy.UserID, policy.Name, policy.MinSeverity, policy.CreatedAt)
+}
+
+func (r *EscalationRepo) GetPolicies(ctx context.Context, userID common.ID) ([]repoPolicy, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      id, user_id, name, min_severity, created_at
+    from
+      escalation_policies
+    where
+      user_id = $1
+    order by created_at`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoPolicy])
+}
+
+func (r *EscalationRepo) DeletePolicy(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `delete from escalation_policies where id = $1 and user_id = $2`
+	return r.update(ctx, q, id, userID)
+}
+
+// SaveStep appends the step to the policy, as long as both the policy and the channel belong to the user.
+func (r *EscalationRepo) SaveStep(ctx context.Context, userID common.ID, step repoStep) error {
+	q := `
+    insert into escalation_steps (id, policy_id, channel_id, delay_hours, created_at)
+    select $1, $2, $3, $4, $5
+    where
+      exists (select 1 from escalation_policies where id = $2 and user_id = $6) and
+      exists (select 1 from notification_channels where id = $3 and user_id = $6)`
+	return r.update(ctx, q, step.ID, step.PolicyID, step.ChannelID, step.DelayHours, step.CreatedAt, userID)
+}
+
+// GetSteps returns the steps of all the policies of the user, in the order they are notified.
+func (r *EscalationRepo) GetSteps(ctx context.Context, userID common.ID) ([]repoStep, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      s.id, s.policy_id, s.channel_id, c.name as channel_name, s.delay_hours, s.created_at
+    from
+      escalation_steps s
+      join escalation_policies p on p.id = s.policy_id
+      join notification_channels c on c.id = s.channel_id
+    where
+      p.user_id = $1
+    order by s.created_at, s.id`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoStep])
+}
+
+func (r *EscalationRepo) DeleteStep(ctx context.Context, userID common.ID, id common.ID) error {
+	q := `
+    delete from escalation_steps s
+    using escalation_policies p
+    where s.id = $1 and s.policy_id = p.id and p.user_id = $2`
+
+	err := r.update(ctx, q, id, userID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrStepNotFound
+	}
+	return err
+}
+
+func (r *EscalationRepo) SaveIncident(ctx context.Context, incident repoIncident) error {
+	q := `
+    insert into incidents (
+      id, user_id, policy_id, certificate_id, domain, status, payload, state, step, next_escalation_at,
+      acknowledged_by, acknowledged_via, acknowledged_at, opened_at, resolved_at, updated_at
+    )
+    values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	return r.update(
+		ctx,
+		q,
+		incident.ID,
+		incident.UserID,
+		incident.PolicyID,
+		incident.CertificateID,
+		incident.Domain,
+		incident.Status,
+		incident.Payload,
+		incident.State,
+		incident.Step,
+		incident.NextEscalationAt,
+		incident.AcknowledgedBy,
+		incident.AcknowledgedVia,
+		incident.AcknowledgedAt,
+		incident.OpenedAt,
+		incident.ResolvedAt,
+		incident.UpdatedAt,
+	)
+}
+
+func (r *EscalationRepo) UpdateIncident(ctx context.Context, incident repoIncident) error {
+	q := `
+    update
+      incidents
+    set
+      status = $2,
+      payload = $3,
+      state = $4,
+      step = $5,
+      next_escalation_at = $6,
+      acknowledged_by = $7,
+      acknowledged_via = $8,
+      acknowledged_at = $9,
+      resolved_at = $10,
+      updated_at = $11
+    where
+      id = $1`
+
+	err := r.update(
+		ctx,
+		q,
+		incident.ID,
+		incident.Status,
+		incident.Payload,
+		incident.State,
+		incident.Step,
+		incident.NextEscalationAt,
+		incident.AcknowledgedBy,
+		incident.AcknowledgedVia,
+		incident.AcknowledgedAt,
+		incident.ResolvedAt,
+		incident.UpdatedAt,
+	)
+	if errors.Is(err, ErrNotFound) {
+		return ErrIncidentNotFound
+	}
+	return err
+}
+
+func (r *EscalationRepo) getIncident(ctx context.Context, query string, args ...any) (repoIncident, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	rows, _ := r.db.Query(ctx, query, args...)
+	incident, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[repoIncident])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoIncident{}, ErrIncidentNotFound
+		}
+		return repoIncident{}, err
+	}
+
+	return incident, nil
+}
+
+func (r *EscalationRepo) GetIncident(ctx context.Context, id common.ID) (repoIncident, error) {
+	q := `select` + incidentColumns + `
+    from
+      incidents i
+      join escalation_policies p on p.id = i.policy_id
+    where
+      i.id = $1`
+	return r.getIncident(ctx, q, id)
+}
+
+// GetUnresolvedIncident returns the incident the policy has open for the certificate, if any.
+func (r *EscalationRepo) GetUnresolvedIncident(ctx context.Context, policyID common.ID, certificateID common.ID) (repoIncident, error) {
+	q := `select` + incidentColumns + `
+    from
+      incidents i
+      join escalation_policies p on p.id = i.policy_id
+    where
+      i.policy_id = $1 and i.certificate_id = $2 and i.state <> 'resolved'`
+	return r.getIncident(ctx, q, policyID, certificateID)
+}
+
+// GetIncidents returns the latest incidents of the user, newest first.
+func (r *EscalationRepo) GetIncidents(ctx context.Context, userID common.ID, limit int) ([]repoIncident, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select` + incidentColumns + `
+    from
+      incidents i
+      join escalation_policies p on p.id = i.policy_id
+    where
+      i.user_id = $1
+    order by i.opened_at desc
+    limit $2`
+
+	rows, _ := r.db.Query(ctx, q, userID, limit)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoIncident])
+}
+
+// GetDueIncidents returns the open and snoozed incidents of every user that are due to escalate.
+func (r *EscalationRepo) GetDueIncidents(ctx context.Context, now time.Time) ([]repoIncident, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `select` + incidentColumns + `
+    from
+      incidents i
+      join escalation_policies p on p.id = i.policy_id
+    where
+      i.state in ('open', 'snoozed') and i.next_escalation_at <= $1
+    order by i.next_escalation_at`
+
+	rows, _ := r.db.Query(ctx, q, now)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoIncident])
+}
+
+// ResolveIncidents resolves every unresolved incident of the certificate.
+func (r *EscalationRepo) ResolveIncidents(ctx context.Context, userID common.ID, certificateID common.ID, resolvedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    update
+      incidents
+    set
+      state = 'resolved',
+      next_escalation_at = null,
+      resolved_at = $3,
+      updated_at = $3
+    where
+      user_id = $1 and certificate_id = $2 and state <> 'resolved'`
+
+	_, err := r.db.Exec(ctx, q, userID, certificateID, resolvedAt)
+	return err
+}
@@ -0,0 +1,45 @@
+package escalation
+
+import "time"
+
+// repoPolicy represents a Policy in the Repository layer.
+type repoPolicy struct {
+	ID          string    `db:"id"`
+	UserID      string    `db:"user_id"`
+	Name        string    `db:"name"`
+	MinSeverity string    `db:"min_severity"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// repoStep represents a Step in the Repository layer,
+// ChannelName is read from the channel it notifies.
+type repoStep struct {
+	ID          string    `db:"id"`
+	PolicyID    string    `db:"policy_id"`
+	ChannelID   string    `db:"channel_id"`
+	ChannelName string    `db:"channel_name"`
+	DelayHours  int       `db:"delay_hours"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// repoIncident represents an Incident in the Repository layer,
+// PolicyName is read from the policy it belongs to.
+type repoIncident struct {
+	ID               string     `db:"id"`
+	UserID           string     `db:"user_id"`
+	PolicyID         string     `db:"policy_id"`
+	PolicyName       string     `db:"policy_name"`
+	CertificateID    string     `db:"certificate_id"`
+	Domain           string     `db:"domain"`
+	Status           string     `db:"status"`
+	Payload          []byte     `db:"payload"`
+	State            string     `db:"state"`
+	Step             int        `db:"step"`
+	NextEscalationAt *time.Time `db:"next_escalation_at"`
+	AcknowledgedBy   string     `db:"acknowledged_by"`
+	AcknowledgedVia  string     `db:"acknowledged_via"`
+	AcknowledgedAt   *time.Time `db:"acknowledged_at"`
+	OpenedAt         time.Time  `db:"opened_at"`
+	ResolvedAt       *time.Time `db:"resolved_at"`
+	UpdatedAt        time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,366 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/outbox"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+const (
+	maxNameLength = 64
+	maxIncidents  = 100
+)
+
+type Service interface {
+	CreatePolicy(ctx context.Context, req CreatePolicyReq) (Policy, error)
+	GetPolicies(ctx context.Context, req GetPoliciesReq) ([]Policy, error)
+	DeletePolicy(ctx context.Context, req DeletePolicyReq) error
+	AddStep(ctx context.Context, req AddStepReq) (Policy, error)
+	DeleteStep(ctx context.Context, req DeleteStepReq) (Policy, error)
+	Trigger(ctx context.Context, req TriggerReq) ([]common.ID, error)
+	Escalate(ctx context.Context, req EscalateReq, logger *slog.Logger) (int, error)
+	GetIncidents(ctx context.Context, req GetIncidentsReq) ([]Incident, error)
+	GetIncident(ctx context.Context, req GetIncidentReq) (Incident, error)
+	Acknowledge(ctx context.Context, req AcknowledgeReq) (Incident, error)
+	Snooze(ctx context.Context, req SnoozeReq) (Incident, error)
+}
+
+type EscalationService struct {
+	repo       Repo
+	outbox     outbox.Service
+	linkSigner *signer.Signer
+	appURL     string
+}
+
+func NewService(repo Repo, outboxService outbox.Service, linkSigner *signer.Signer, appURL string) *EscalationService {
+	return &EscalationService{
+		repo:       repo,
+		outbox:     outboxService,
+		linkSigner: linkSigner,
+		appURL:     strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+func (s *EscalationService) CreatePolicy(ctx context.Context, req CreatePolicyReq) (Policy, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return Policy{}, ErrInvalidName
+	}
+
+	policy := NewPolicy(req.UserID, name, req.MinSeverity)
+	err := s.repo.SavePolicy(ctx, policyToRepoAdapter(policy))
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
+// GetPolicies returns the policies of the user along with their steps.
+func (s *EscalationService) GetPolicies(ctx context.Context, req GetPoliciesReq) ([]Policy, error) {
+	ps, err := s.repo.GetPolicies(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := s.repo.GetSteps(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	stepsByPolicy := map[string][]Step{}
+	for _, st := range steps {
+		step, err := repoToStepAdapter(st)
+		if err != nil {
+			return nil, err
+		}
+		stepsByPolicy[st.PolicyID] = append(stepsByPolicy[st.PolicyID], step)
+	}
+
+	policies := make([]Policy, len(ps))
+	for i, p := range ps {
+		policy, err := repoToPolicyAdapter(p)
+		if err != nil {
+			return nil, err
+		}
+		if steps, ok := stepsByPolicy[p.ID]; ok {
+			policy.Steps = steps
+		}
+		policies[i] = policy
+	}
+
+	return policies, nil
+}
+
+func (s *EscalationService) getPolicy(ctx context.Context, userID common.ID, id common.ID) (Policy, error) {
+	policies, err := s.GetPolicies(ctx, GetPoliciesReq{UserID: userID})
+	if err != nil {
+		return Policy{}, err
+	}
+
+	for _, p := range policies {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+
+	return Policy{}, ErrNotFound
+}
+
+// DeletePolicy deletes the policy along with its incidents.
+func (s *EscalationService) DeletePolicy(ctx context.Context, req DeletePolicyReq) error {
+	return s.repo.DeletePolicy(ctx, req.UserID, req.ID)
+}
+
+// AddStep appends a step notifying the channel to the policy and returns the updated policy.
+func (s *EscalationService) AddStep(ctx context.Context, req AddStepReq) (Policy, error) {
+	if req.DelayHours < 1 || req.DelayHours > MaxHours {
+		return Policy{}, ErrInvalidDelay
+	}
+
+	policy, err := s.getPolicy(ctx, req.UserID, req.PolicyID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if len(policy.Steps) >= MaxSteps {
+		return Policy{}, fmt.Errorf("cannot have more than %d steps: %w", MaxSteps, ErrTooManySteps)
+	}
+
+	err = s.repo.SaveStep(ctx, req.UserID, stepToRepoAdapter(NewStep(req.PolicyID, req.ChannelID, req.DelayHours)))
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return s.getPolicy(ctx, req.UserID, req.PolicyID)
+}
+
+func (s *EscalationService) DeleteStep(ctx context.Context, req DeleteStepReq) (Policy, error) {
+	err := s.repo.DeleteStep(ctx, req.UserID, req.ID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return s.getPolicy(ctx, req.UserID, req.PolicyID)
+}
+
+// Trigger opens an incident for every policy of the user the notification applies to,
+// unless one is already open for the certificate, and resolves them once the certificate is healthy.
+// It returns the channels notified, so they can be skipped when routing the same notification.
+func (s *EscalationService) Trigger(ctx context.Context, req TriggerReq) ([]common.ID, error) {
+	n := req.Notification
+	certificateID, err := common.ParseID(n.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if n.Status == notifier.StatusResolved || n.Status == "OK" {
+		return nil, s.repo.ResolveIncidents(ctx, req.UserID, certificateID, now)
+	}
+
+	policies, err := s.GetPolicies(ctx, GetPoliciesReq{UserID: req.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	notified := []common.ID{}
+	for _, p := range policies {
+		if !p.Applies(n) {
+			continue
+		}
+
+		existing, err := s.repo.GetUnresolvedIncident(ctx, p.ID, certificateID)
+		if err == nil {
+			// Keep the latest status, so that the next steps are told about it.
+			incident, err := repoToIncidentAdapter(existing)
+			if err != nil {
+				return notified, err
+			}
+			incident.Status = n.Status
+			incident.Notification = n
+			incident.UpdatedAt = now
+			err = s.updateIncident(ctx, incident)
+			if err != nil {
+				return notified, err
+			}
+			continue
+		}
+		if !errors.Is(err, ErrIncidentNotFound) {
+			return notified, err
+		}
+
+		incident := NewIncident(p, certificateID, n, now)
+		i, err := incidentToRepoAdapter(incident)
+		if err != nil {
+			return notified, err
+		}
+		err = s.repo.SaveIncident(ctx, i)
+		if err != nil {
+			return notified, err
+		}
+
+		channelID, err := s.notify(ctx, incident, p)
+		if err != nil {
+			return notified, err
+		}
+		notified = append(notified, channelID)
+	}
+
+	return notified, nil
+}
+
+// Escalate notifies the next step of every incident that hasn't been acknowledged in time,
+// and the current one again for those whose snooze is over, returning how many were escalated.
+// Failing incidents are logged and skipped.
+func (s *EscalationService) Escalate(ctx context.Context, req EscalateReq, logger *slog.Logger) (int, error) {
+	due, err := s.repo.GetDueIncidents(ctx, req.Now)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, d := range due {
+		incident, err := repoToIncidentAdapter(d)
+		if err != nil {
+			logger.Error("Failed to parse incident", "id", d.ID, "error", err.Error())
+			continue
+		}
+
+		policy, err := s.getPolicy(ctx, incident.UserID, incident.PolicyID)
+		if err != nil {
+			logger.Error("Failed to get escalation policy", "id", d.ID, "error", err.Error())
+			continue
+		}
+
+		incident, ok := incident.Escalate(policy, req.Now)
+		err = s.updateIncident(ctx, incident)
+		if err != nil {
+			logger.Error("Failed to update incident", "id", d.ID, "error", err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		_, err = s.notify(ctx, incident, policy)
+		if err != nil {
+			logger.Error("Failed to queue escalation", "id", d.ID, "error", err.Error())
+			continue
+		}
+		escalated++
+	}
+
+	return escalated, nil
+}
+
+// GetIncidents returns the latest incidents of the user, newest first.
+func (s *EscalationService) GetIncidents(ctx context.Context, req GetIncidentsReq) ([]Incident, error) {
+	is, err := s.repo.GetIncidents(ctx, req.UserID, maxIncidents)
+	if err != nil {
+		return nil, err
+	}
+
+	incidents := make([]Incident, len(is))
+	for i, incident := range is {
+		incidents[i], err = repoToIncidentAdapter(incident)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return incidents, nil
+}
+
+// GetIncident returns any incident, it's meant to be used with the ID of a verified acknowledge token.
+func (s *EscalationService) GetIncident(ctx context.Context, req GetIncidentReq) (Incident, error) {
+	i, err := s.repo.GetIncident(ctx, req.ID)
+	if err != nil {
+		return Incident{}, err
+	}
+	return repoToIncidentAdapter(i)
+}
+
+// Acknowledge stops the escalation of the incident, the channel being the one the acknowledge link was sent through.
+func (s *EscalationService) Acknowledge(ctx context.Context, req AcknowledgeReq) (Incident, error) {
+	incident, err := s.GetIncident(ctx, GetIncidentReq{ID: req.ID})
+	if err != nil {
+		return Incident{}, err
+	}
+
+	via := ""
+	policy, err := s.getPolicy(ctx, incident.UserID, incident.PolicyID)
+	if err != nil {
+		return Incident{}, err
+	}
+	for _, step := range policy.Steps {
+		if step.ChannelID == req.ChannelID {
+			via = step.ChannelName
+			break
+		}
+	}
+
+	by := strings.TrimSpace(req.By)
+	if len(by) > maxNameLength {
+		return Incident{}, ErrInvalidName
+	}
+
+	acknowledged, err := incident.Acknowledge(by, via, time.Now().UTC())
+	if err != nil {
+		return incident, err
+	}
+
+	if acknowledged.State == incident.State {
+		return incident, nil
+	}
+
+	return acknowledged, s.updateIncident(ctx, acknowledged)
+}
+
+// Snooze pauses the escalation of the incident for req.Hours.
+func (s *EscalationService) Snooze(ctx context.Context, req SnoozeReq) (Incident, error) {
+	incident, err := s.GetIncident(ctx, GetIncidentReq{ID: req.ID})
+	if err != nil {
+		return Incident{}, err
+	}
+
+	snoozed, err := incident.Snooze(req.Hours, time.Now().UTC())
+	if err != nil {
+		return incident, err
+	}
+
+	return snoozed, s.updateIncident(ctx, snoozed)
+}
+
+func (s *EscalationService) updateIncident(ctx context.Context, incident Incident) error {
+	i, err := incidentToRepoAdapter(incident)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateIncident(ctx, i)
+}
+
+// notify queues the notification of the incident to the channel of its current step,
+// with a link to acknowledge or snooze it.
+func (s *EscalationService) notify(ctx context.Context, incident Incident, policy Policy) (common.ID, error) {
+	step := policy.Steps[incident.Step]
+
+	n := incident.Notification
+	token := s.linkSigner.Sign(AckPurpose, AckValue(incident.ID, step.ChannelID), time.Now().Add(AckLinkTTL))
+	n.AckURL = s.appURL + "/incidents/ack?token=" + token
+
+	err := s.outbox.Enqueue(ctx, outbox.EnqueueReq{
+		UserID:       incident.UserID,
+		ChannelID:    step.ChannelID,
+		Notification: n,
+	})
+	return step.ChannelID, err
+}
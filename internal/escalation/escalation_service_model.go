@@ -0,0 +1,301 @@
+package escalation
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+type CreatePolicyReq struct {
+	UserID      common.ID
+	Name        string
+	MinSeverity notifier.Severity
+}
+
+type GetPoliciesReq struct {
+	UserID common.ID
+}
+
+type DeletePolicyReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+type AddStepReq struct {
+	PolicyID   common.ID
+	UserID     common.ID
+	ChannelID  common.ID
+	DelayHours int
+}
+
+type DeleteStepReq struct {
+	ID       common.ID
+	PolicyID common.ID
+	UserID   common.ID
+}
+
+type TriggerReq struct {
+	UserID       common.ID
+	Notification notifier.Notification
+}
+
+type EscalateReq struct {
+	Now time.Time
+}
+
+type GetIncidentsReq struct {
+	UserID common.ID
+}
+
+type GetIncidentReq struct {
+	ID common.ID
+}
+
+type AcknowledgeReq struct {
+	ID        common.ID
+	ChannelID common.ID
+	By        string
+}
+
+type SnoozeReq struct {
+	ID    common.ID
+	Hours int
+}
+
+// Policy escalates notifications that are not acknowledged through a sequence of channels.
+type Policy struct {
+	ID          common.ID
+	UserID      common.ID
+	Name        string
+	MinSeverity notifier.Severity
+	CreatedAt   time.Time
+	Steps       []Step
+}
+
+// Step of a Policy, notified DelayHours after the previous one.
+type Step struct {
+	ID          common.ID
+	PolicyID    common.ID
+	ChannelID   common.ID
+	ChannelName string
+	DelayHours  int
+	CreatedAt   time.Time
+}
+
+// Incident tracks the escalation of a notification until it's acknowledged or the certificate is healthy again.
+// A zero NextEscalationAt means there is nothing left to notify.
+type Incident struct {
+	ID               common.ID
+	UserID           common.ID
+	PolicyID         common.ID
+	PolicyName       string
+	CertificateID    common.ID
+	Domain           string
+	Status           string
+	Notification     notifier.Notification
+	State            State
+	Step             int
+	NextEscalationAt time.Time
+	AcknowledgedBy   string
+	AcknowledgedVia  string
+	AcknowledgedAt   time.Time
+	OpenedAt         time.Time
+	ResolvedAt       time.Time
+	UpdatedAt        time.Time
+}
+
+func NewPolicy(userID common.ID, name string, minSeverity notifier.Severity) Policy {
+	return Policy{
+		ID:          common.NewID(),
+		UserID:      userID,
+		Name:        name,
+		MinSeverity: minSeverity,
+		CreatedAt:   time.Now().UTC(),
+		Steps:       []Step{},
+	}
+}
+
+func NewStep(policyID common.ID, channelID common.ID, delayHours int) Step {
+	return Step{
+		ID:         common.NewID(),
+		PolicyID:   policyID,
+		ChannelID:  channelID,
+		DelayHours: delayHours,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// policyToRepoAdapter transforms a Policy from the Service layer to the Repository layer.
+func policyToRepoAdapter(p Policy) repoPolicy {
+	return repoPolicy{
+		ID:          p.ID.String(),
+		UserID:      p.UserID.String(),
+		Name:        p.Name,
+		MinSeverity: string(p.MinSeverity),
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+// repoToPolicyAdapter transforms a Policy from the Repository layer to the Service layer.
+func repoToPolicyAdapter(p repoPolicy) (Policy, error) {
+	parsedID, err := common.ParseID(p.ID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	parsedUserID, err := common.ParseID(p.UserID)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	parsedSeverity, err := notifier.ParseSeverity(p.MinSeverity)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{
+		ID:          parsedID,
+		UserID:      parsedUserID,
+		Name:        p.Name,
+		MinSeverity: parsedSeverity,
+		CreatedAt:   p.CreatedAt,
+		Steps:       []Step{},
+	}, nil
+}
+
+// stepToRepoAdapter transforms a Step from the Service layer to the Repository layer.
+func stepToRepoAdapter(s Step) repoStep {
+	return repoStep{
+		ID:          s.ID.String(),
+		PolicyID:    s.PolicyID.String(),
+		ChannelID:   s.ChannelID.String(),
+		ChannelName: s.ChannelName,
+		DelayHours:  s.DelayHours,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// repoToStepAdapter transforms a Step from the Repository layer to the Service layer.
+func repoToStepAdapter(s repoStep) (Step, error) {
+	parsedID, err := common.ParseID(s.ID)
+	if err != nil {
+		return Step{}, err
+	}
+
+	parsedPolicyID, err := common.ParseID(s.PolicyID)
+	if err != nil {
+		return Step{}, err
+	}
+
+	parsedChannelID, err := common.ParseID(s.ChannelID)
+	if err != nil {
+		return Step{}, err
+	}
+
+	return Step{
+		ID:          parsedID,
+		PolicyID:    parsedPolicyID,
+		ChannelID:   parsedChannelID,
+		ChannelName: s.ChannelName,
+		DelayHours:  s.DelayHours,
+		CreatedAt:   s.CreatedAt,
+	}, nil
+}
+
+// incidentToRepoAdapter transforms an Incident from the Service layer to the Repository layer.
+func incidentToRepoAdapter(i Incident) (repoIncident, error) {
+	payload, err := json.Marshal(i.Notification)
+	if err != nil {
+		return repoIncident{}, err
+	}
+
+	return repoIncident{
+		ID:               i.ID.String(),
+		UserID:           i.UserID.String(),
+		PolicyID:         i.PolicyID.String(),
+		PolicyName:       i.PolicyName,
+		CertificateID:    i.CertificateID.String(),
+		Domain:           i.Domain,
+		Status:           i.Status,
+		Payload:          payload,
+		State:            string(i.State),
+		Step:             i.Step,
+		NextEscalationAt: toNullTime(i.NextEscalationAt),
+		AcknowledgedBy:   i.AcknowledgedBy,
+		AcknowledgedVia:  i.AcknowledgedVia,
+		AcknowledgedAt:   toNullTime(i.AcknowledgedAt),
+		OpenedAt:         i.OpenedAt,
+		ResolvedAt:       toNullTime(i.ResolvedAt),
+		UpdatedAt:        i.UpdatedAt,
+	}, nil
+}
+
+// repoToIncidentAdapter transforms an Incident from the Repository layer to the Service layer.
+func repoToIncidentAdapter(i repoIncident) (Incident, error) {
+	parsedID, err := common.ParseID(i.ID)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	parsedUserID, err := common.ParseID(i.UserID)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	parsedPolicyID, err := common.ParseID(i.PolicyID)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	parsedCertificateID, err := common.ParseID(i.CertificateID)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	parsedState, err := ParseState(i.State)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	notification := notifier.Notification{}
+	err = json.Unmarshal(i.Payload, &notification)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	return Incident{
+		ID:               parsedID,
+		UserID:           parsedUserID,
+		PolicyID:         parsedPolicyID,
+		PolicyName:       i.PolicyName,
+		CertificateID:    parsedCertificateID,
+		Domain:           i.Domain,
+		Status:           i.Status,
+		Notification:     notification,
+		State:            parsedState,
+		Step:             i.Step,
+		NextEscalationAt: fromNullTime(i.NextEscalationAt),
+		AcknowledgedBy:   i.AcknowledgedBy,
+		AcknowledgedVia:  i.AcknowledgedVia,
+		AcknowledgedAt:   fromNullTime(i.AcknowledgedAt),
+		OpenedAt:         i.OpenedAt,
+		ResolvedAt:       fromNullTime(i.ResolvedAt),
+		UpdatedAt:        i.UpdatedAt,
+	}, nil
+}
+
+func toNullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func fromNullTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,170 @@
+package escalation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// State of an Incident.
+type State string
+
+const (
+	StateOpen         State = "open"
+	StateSnoozed      State = "snoozed"
+	StateAcknowledged State = "acknowledged"
+	StateResolved     State = "resolved"
+)
+
+func ParseState(s string) (State, error) {
+	switch State(s) {
+	case StateOpen, StateSnoozed, StateAcknowledged, StateResolved:
+		return State(s), nil
+	default:
+		return "", ErrInvalidState
+	}
+}
+
+const (
+	// MaxSteps is the number of steps a Policy can have.
+	MaxSteps = 5
+	// MaxHours caps both the delay of a Step and how long an Incident can be snoozed.
+	MaxHours = 168
+	// AckPurpose is the signer purpose of the tokens in acknowledge links.
+	AckPurpose = "incident"
+	// AckLinkTTL is how long acknowledge links work.
+	AckLinkTTL = 30 * 24 * time.Hour
+)
+
+// AckValue is the value signed in acknowledge tokens,
+// the channel tells through which one the incident was acknowledged.
+func AckValue(incidentID common.ID, channelID common.ID) string {
+	return incidentID.String() + ":" + channelID.String()
+}
+
+// ParseAckValue returns the incident and channel IDs of a verified acknowledge token value.
+func ParseAckValue(value string) (common.ID, common.ID, error) {
+	incidentID, channelID, _ := strings.Cut(value, ":")
+
+	parsedIncidentID, err := common.ParseID(incidentID)
+	if err != nil {
+		return common.ID{}, common.ID{}, err
+	}
+
+	parsedChannelID, err := common.ParseID(channelID)
+	if err != nil {
+		return common.ID{}, common.ID{}, err
+	}
+
+	return parsedIncidentID, parsedChannelID, nil
+}
+
+// Applies reports whether the notification opens an incident under the policy:
+// it needs steps to notify and a certificate problem at least as severe as MinSeverity.
+func (p Policy) Applies(n notifier.Notification) bool {
+	if len(p.Steps) == 0 || n.ID == "" {
+		return false
+	}
+
+	switch n.Status {
+	case "OK", notifier.StatusResolved, notifier.StatusDigest, notifier.StatusChannelDisabled:
+		return false
+	}
+
+	return notifier.SeverityOf(n.Status).AtLeast(p.MinSeverity)
+}
+
+// nextEscalation returns when the step after the given one is due, zero if it's the last one.
+func (p Policy) nextEscalation(step int, from time.Time) time.Time {
+	if step+1 >= len(p.Steps) {
+		return time.Time{}
+	}
+	return from.Add(time.Duration(p.Steps[step+1].DelayHours) * time.Hour)
+}
+
+// NewIncident opens an incident for the notification about the certificate,
+// the first step of the policy is to be notified right away.
+func NewIncident(p Policy, certificateID common.ID, n notifier.Notification, now time.Time) Incident {
+	return Incident{
+		ID:               common.NewID(),
+		UserID:           p.UserID,
+		PolicyID:         p.ID,
+		PolicyName:       p.Name,
+		CertificateID:    certificateID,
+		Domain:           n.Domain,
+		Status:           n.Status,
+		Notification:     n,
+		State:            StateOpen,
+		Step:             0,
+		NextEscalationAt: p.nextEscalation(0, now),
+		OpenedAt:         now,
+		UpdatedAt:        now,
+	}
+}
+
+// Escalate moves the incident to the step to notify once NextEscalationAt is reached:
+// snoozed incidents notify their current step again and open ones the next step.
+// It returns false when there is no step left to notify.
+func (i Incident) Escalate(p Policy, now time.Time) (Incident, bool) {
+	i.UpdatedAt = now
+	i.NextEscalationAt = time.Time{}
+
+	switch i.State {
+	case StateSnoozed:
+		i.State = StateOpen
+		// Steps may have been removed while snoozed.
+		i.Step = min(i.Step, len(p.Steps)-1)
+	case StateOpen:
+		i.Step++
+	default:
+		return i, false
+	}
+
+	if i.Step < 0 || i.Step >= len(p.Steps) {
+		i.Step = max(len(p.Steps)-1, 0)
+		return i, false
+	}
+
+	i.NextEscalationAt = p.nextEscalation(i.Step, now)
+	return i, true
+}
+
+// Acknowledge stops the escalation, recording who acknowledged the incident and through which channel.
+// Acknowledging it again keeps the first acknowledgement.
+func (i Incident) Acknowledge(by string, via string, now time.Time) (Incident, error) {
+	switch i.State {
+	case StateResolved:
+		return i, ErrResolved
+	case StateAcknowledged:
+		return i, nil
+	}
+
+	i.State = StateAcknowledged
+	i.AcknowledgedBy = by
+	i.AcknowledgedVia = via
+	i.AcknowledgedAt = now
+	i.NextEscalationAt = time.Time{}
+	i.UpdatedAt = now
+	return i, nil
+}
+
+// Snooze pauses the escalation for the given hours, after which the current step is notified again.
+func (i Incident) Snooze(hours int, now time.Time) (Incident, error) {
+	if hours < 1 || hours > MaxHours {
+		return i, ErrInvalidSnooze
+	}
+
+	switch i.State {
+	case StateResolved:
+		return i, ErrResolved
+	case StateAcknowledged:
+		return i, ErrAcknowledged
+	}
+
+	i.State = StateSnoozed
+	i.NextEscalationAt = now.Add(time.Duration(hours) * time.Hour)
+	i.UpdatedAt = now
+	return i, nil
+}
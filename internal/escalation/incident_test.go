@@ -0,0 +1,146 @@
+package escalation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+func testPolicy(delays ...int) Policy {
+	p := NewPolicy(common.NewID(), "On call", notifier.SeverityWarning)
+	for _, d := range delays {
+		p.Steps = append(p.Steps, NewStep(p.ID, common.NewID(), d))
+	}
+	return p
+}
+
+func TestPolicyApplies(t *testing.T) {
+	t.Parallel()
+
+	p := testPolicy(1, 2)
+	tt := []struct {
+		name   string
+		policy Policy
+		n      notifier.Notification
+		want   bool
+	}{
+		{"expiring", p, notifier.Notification{ID: "1", Status: "expires soon"}, true},
+		{"error", p, notifier.Notification{ID: "1", Status: "Cannot Connect"}, true},
+		{"resolved", p, notifier.Notification{ID: "1", Status: notifier.StatusResolved}, false},
+		{"digest", p, notifier.Notification{ID: "1", Status: notifier.StatusDigest}, false},
+		{"no certificate", p, notifier.Notification{Status: "expired"}, false},
+		{"no steps", testPolicy(), notifier.Notification{ID: "1", Status: "expired"}, false},
+		{"below severity", Policy{MinSeverity: notifier.SeverityCritical, Steps: p.Steps}, notifier.Notification{ID: "1", Status: "expires soon"}, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.policy.Applies(tc.n)
+			if got != tc.want {
+				t.Errorf("Expected %t but got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIncidentEscalate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := testPolicy(0, 2, 4)
+	n := notifier.Notification{ID: common.NewID().String(), Domain: "example.com", Status: "expired"}
+
+	i := NewIncident(p, common.NewID(), n, now)
+	if i.Step != 0 || !i.NextEscalationAt.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("Expected step 0 escalating in 2 hours, got step %d at %s", i.Step, i.NextEscalationAt)
+	}
+
+	i, ok := i.Escalate(p, now.Add(2*time.Hour))
+	if !ok || i.Step != 1 || !i.NextEscalationAt.Equal(now.Add(6*time.Hour)) {
+		t.Fatalf("Expected step 1 escalating in 4 hours, got %t, step %d at %s", ok, i.Step, i.NextEscalationAt)
+	}
+
+	i, ok = i.Escalate(p, now.Add(6*time.Hour))
+	if !ok || i.Step != 2 || !i.NextEscalationAt.IsZero() {
+		t.Fatalf("Expected last step without further escalation, got %t, step %d at %s", ok, i.Step, i.NextEscalationAt)
+	}
+
+	i, ok = i.Escalate(p, now.Add(7*time.Hour))
+	if ok || i.Step != 2 {
+		t.Errorf("Expected nothing left to notify, got %t, step %d", ok, i.Step)
+	}
+}
+
+func TestIncidentSnoozeAndAcknowledge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := testPolicy(0, 2)
+	i := NewIncident(p, common.NewID(), notifier.Notification{Status: "expired"}, now)
+
+	_, err := i.Snooze(0, now)
+	if !errors.Is(err, ErrInvalidSnooze) {
+		t.Errorf("Expected ErrInvalidSnooze, got %v", err)
+	}
+
+	i, err = i.Snooze(24, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if i.State != StateSnoozed || !i.NextEscalationAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("Expected snoozed for 24 hours, got %s until %s", i.State, i.NextEscalationAt)
+	}
+
+	i, ok := i.Escalate(p, now.Add(24*time.Hour))
+	if !ok || i.State != StateOpen || i.Step != 0 {
+		t.Fatalf("Expected the same step notified again, got %t, %s, step %d", ok, i.State, i.Step)
+	}
+
+	i, err = i.Acknowledge("Alice", "Ops Slack", now.Add(25*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if i.State != StateAcknowledged || i.AcknowledgedBy != "Alice" || i.AcknowledgedVia != "Ops Slack" || !i.NextEscalationAt.IsZero() {
+		t.Errorf("Unexpected acknowledged incident %+v", i)
+	}
+
+	again, _ := i.Acknowledge("Bob", "Email", now.Add(26*time.Hour))
+	if again.AcknowledgedBy != "Alice" {
+		t.Errorf("Expected the first acknowledgement to be kept, got %q", again.AcknowledgedBy)
+	}
+
+	_, err = i.Snooze(1, now)
+	if !errors.Is(err, ErrAcknowledged) {
+		t.Errorf("Expected ErrAcknowledged, got %v", err)
+	}
+
+	i.State = StateResolved
+	_, err = i.Acknowledge("Alice", "", now)
+	if !errors.Is(err, ErrResolved) {
+		t.Errorf("Expected ErrResolved, got %v", err)
+	}
+}
+
+func TestAckValue(t *testing.T) {
+	t.Parallel()
+
+	incidentID := common.NewID()
+	channelID := common.NewID()
+
+	gotIncidentID, gotChannelID, err := ParseAckValue(AckValue(incidentID, channelID))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if gotIncidentID != incidentID || gotChannelID != channelID {
+		t.Errorf("Expected %s and %s, got %s and %s", incidentID, channelID, gotIncidentID, gotChannelID)
+	}
+
+	_, _, err = ParseAckValue("nope")
+	if err == nil {
+		t.Error("Expected error for an invalid value")
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// maxAPIBody caps the JSON body of requests to the API.
+const maxAPIBody = 4096
+
+// Statuses of a domain in the API.
+const (
+	APIStatusOK       = "ok"
+	APIStatusExpiring = "expiring"
+	APIStatusExpired  = "expired"
+	APIStatusError    = "error"
+	APIStatusPending  = "pending"
+)
+
+type APIError struct {
+	Error string `json:"error"`
+}
+
+// sendJSON sends v as JSON with the given status code.
+func sendJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func sendAPIError(w http.ResponseWriter, status int, msg string) {
+	sendJSON(w, status, APIError{Error: msg})
+}
+
+// decodeJSON reads the JSON body of the request into v, rejecting unknown fields.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBody))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+type APICreateDomainReq struct {
+	UserID string   `json:"-"`
+	Domain string   `json:"domain"`
+	Tags   []string `json:"tags"`
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r APICreateDomainReq) Parse() (certs.RegisterReq, error) {
+	return RegisterCertReq{
+		Domain: r.Domain,
+		UserID: r.UserID,
+		Tags:   strings.Join(r.Tags, ","),
+	}.Parse()
+}
+
+// APIDomain represents a Cert in the API, dates are RFC 3339 in UTC and null when unknown.
+type APIDomain struct {
+	ID           string     `json:"id"`
+	Domain       string     `json:"domain"`
+	Status       string     `json:"status"`
+	Issuer       string     `json:"issuer"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	Error        string     `json:"error"`
+	Checking     bool       `json:"checking"`
+	Tags         []string   `json:"tags"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CheckedAt    *time.Time `json:"checked_at"`
+	SnoozedUntil *time.Time `json:"snoozed_until"`
+}
+
+type APIDomains struct {
+	Domains []APIDomain `json:"domains"`
+}
+
+// certToAPIAdapter transforms a Cert from the Service layer to the API.
+func certToAPIAdapter(c certs.Cert, now time.Time) APIDomain {
+	tags := make([]string, len(c.Tags))
+	for i, t := range c.Tags {
+		tags[i] = t.String()
+	}
+
+	return APIDomain{
+		ID:           c.ID.String(),
+		Domain:       c.Domain.String(),
+		Status:       apiStatus(c, now),
+		Issuer:       c.Issuer.String(),
+		ExpiresAt:    apiTime(c.ExpiresAt),
+		Error:        c.Error,
+		Checking:     c.Checking,
+		Tags:         tags,
+		CreatedAt:    c.CreatedAt.UTC(),
+		CheckedAt:    apiTime(c.UpdatedAt),
+		SnoozedUntil: apiTime(c.SnoozedUntil),
+	}
+}
+
+// apiStatus is the status of the cert in the API, expiring when the service notifies it as such
+// (certs.ExpirationStatus), like the dashboard shows it.
+func apiStatus(c certs.Cert, now time.Time) string {
+	if c.Error != "" {
+		return APIStatusError
+	}
+	if c.ExpiresAt.IsZero() {
+		return APIStatusPending
+	}
+
+	switch certs.ExpirationStatus(int(c.ExpiresAt.Sub(now).Hours())) {
+	case "":
+		return APIStatusOK
+	case "expired":
+		return APIStatusExpired
+	default:
+		return APIStatusExpiring
+	}
+}
+
+// apiTime returns nil for the zero time, so it's null in the JSON.
+func apiTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// APISettings represents the settings of a User in the API,
+// channels are listed without their targets and secrets.
+type APISettings struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Email      string        `json:"email"`
+	Timezone   string        `json:"timezone"`
+	QuietHours APIQuietHours `json:"quiet_hours"`
+	Digest     APIDigest     `json:"digest"`
+	Channels   []APIChannel  `json:"channels"`
+}
+
+type APIQuietHours struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+type APIDigest struct {
+	Frequency string `json:"frequency"`
+	Weekday   string `json:"weekday"`
+	Hour      int    `json:"hour"`
+	Days      int    `json:"days"`
+	// ChannelID is empty when the digest is sent through every channel.
+	ChannelID string `json:"channel_id"`
+}
+
+type APIChannel struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// userToAPISettingsAdapter transforms a User and its Channels from the Service layer to the API.
+func userToAPISettingsAdapter(u users.User, chs []channels.Channel) APISettings {
+	apiChannels := make([]APIChannel, len(chs))
+	for i, c := range chs {
+		apiChannels[i] = APIChannel{ID: c.ID.String(), Kind: c.Kind.String(), Name: c.Name, Enabled: c.Enabled, Confirmed: c.Confirmed}
+	}
+
+	return APISettings{
+		ID:       u.ID.String(),
+		Name:     u.Name,
+		Email:    u.Email.String(),
+		Timezone: u.Timezone.String(),
+		QuietHours: APIQuietHours{
+			Enabled: u.QuietHours.Enabled,
+			Start:   u.QuietHours.StartString(),
+			End:     u.QuietHours.EndString(),
+		},
+		Digest: APIDigest{
+			Frequency: string(u.Digest.Frequency),
+			Weekday:   strings.ToLower(u.Digest.Weekday.String()),
+			Hour:      u.Digest.Hour,
+			Days:      u.Digest.Days,
+			ChannelID: u.Digest.ChannelID.String(),
+		},
+		Channels: apiChannels,
+	}
+}
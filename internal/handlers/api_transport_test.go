@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+)
+
+func TestAPIStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		cert certs.Cert
+		want string
+	}{
+		{"ok", certs.Cert{ExpiresAt: now.Add(30 * 24 * time.Hour)}, APIStatusOK},
+		{"ok_in_14_days", certs.Cert{ExpiresAt: now.Add(10 * 24 * time.Hour)}, APIStatusOK},
+		{"expires_soon", certs.Cert{ExpiresAt: now.Add(48 * time.Hour)}, APIStatusExpiring},
+		{"expires_today", certs.Cert{ExpiresAt: now.Add(5 * time.Hour)}, APIStatusExpiring},
+		{"expired", certs.Cert{ExpiresAt: now.Add(-time.Hour)}, APIStatusExpired},
+		{"error", certs.Cert{Error: "CannotConnect"}, APIStatusError},
+		{"pending", certs.Cert{}, APIStatusPending},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := apiStatus(tc.cert, now)
+			if got != tc.want {
+				t.Errorf("Expected status %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
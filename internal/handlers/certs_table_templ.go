@@ -23,7 +23,7 @@ func certsTable(certificates []TransportCert) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<table id=\"table\"><thead><tr><th scope=\"col\">Domain</th><th scope=\"col\">Expires</th><th scope=\"col\">Issuer</th><th scope=\"col\">Status</th><th scope=\"col\"></th></tr></thead> <tbody>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<table id=\"table\"><thead><tr><th scope=\"col\">Domain</th><th scope=\"col\">Expires</th><th scope=\"col\">Issuer</th><th scope=\"col\">Tags</th><th scope=\"col\">Status</th><th scope=\"col\"></th></tr></thead> <tbody>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
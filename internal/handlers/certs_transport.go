@@ -11,6 +11,7 @@ import (
 type RegisterCertReq struct {
 	Domain string
 	UserID string
+	Tags   string
 }
 
 // Parse converts it from the Transport layer to the Service layer.
@@ -25,9 +26,15 @@ func (r RegisterCertReq) Parse() (certs.RegisterReq, error) {
 		return certs.RegisterReq{}, err
 	}
 
+	tags, err := certs.ParseTags(r.Tags)
+	if err != nil {
+		return certs.RegisterReq{}, err
+	}
+
 	return certs.RegisterReq{
 		Domain: domain,
 		UserID: userID,
+		Tags:   tags,
 	}, nil
 }
 
@@ -70,6 +77,59 @@ func (r UpdateCertReq) Parse() (certs.UpdateReq, error) {
 	}, nil
 }
 
+type GetCertReq struct {
+	ID     string
+	UserID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r GetCertReq) Parse() (certs.GetReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return certs.GetReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return certs.GetReq{}, err
+	}
+
+	return certs.GetReq{
+		ID:     id,
+		UserID: userID,
+	}, nil
+}
+
+type SetCertTagsReq struct {
+	ID     string
+	UserID string
+	Tags   string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SetCertTagsReq) Parse() (certs.SetTagsReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return certs.SetTagsReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return certs.SetTagsReq{}, err
+	}
+
+	tags, err := certs.ParseTags(r.Tags)
+	if err != nil {
+		return certs.SetTagsReq{}, err
+	}
+
+	return certs.SetTagsReq{
+		ID:     id,
+		UserID: userID,
+		Tags:   tags,
+	}, nil
+}
+
 type DeleteCertReq struct {
 	ID     string
 	UserID string
@@ -103,15 +163,19 @@ type TransportCert struct {
 	Status     string
 	Error      string
 	LastUpdate string
+	Tags       []string
 }
 
-// serviceToTransportAdapter transforms a Cert from the Service layer to the Transport layer.
-func serviceToTransportAdapter(c certs.Cert) TransportCert {
+// serviceToTransportAdapter transforms a Cert from the Service layer to the Transport layer,
+// with its dates in the given location, the timezone of the user.
+func serviceToTransportAdapter(c certs.Cert, loc *time.Location) TransportCert {
 	now := time.Now()
 	diffDays := c.ExpiresAt.Sub(now).Hours() / 24
 	status := ""
 
 	switch {
+	case c.Checking || c.Pending():
+		status = "Checking…"
 	case c.Error != "":
 		status = c.Error
 	case diffDays < 0:
@@ -122,14 +186,28 @@ func serviceToTransportAdapter(c certs.Cert) TransportCert {
 		status = fmt.Sprintf("Expires in %d days", int(diffDays))
 	}
 
+	tags := make([]string, len(c.Tags))
+	for i, t := range c.Tags {
+		tags[i] = t.String()
+	}
+
 	return TransportCert{
 		ID:         c.ID.String(),
-		CreatedAt:  c.CreatedAt.Format(time.DateOnly),
-		ExpiresAt:  c.ExpiresAt.Format(time.DateOnly),
+		CreatedAt:  c.CreatedAt.In(loc).Format(time.DateOnly),
+		ExpiresAt:  transportDate(c.ExpiresAt, loc),
 		Domain:     c.Domain.String(),
 		Issuer:     c.Issuer.String(),
 		Status:     status,
 		Error:      c.Error,
-		LastUpdate: c.UpdatedAt.Format(time.DateOnly),
+		LastUpdate: transportDate(c.UpdatedAt, loc),
+		Tags:       tags,
+	}
+}
+
+// transportDate returns an empty string for the zero time, the certs that haven't been checked yet don't have dates.
+func transportDate(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.In(loc).Format(time.DateOnly)
 }
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+type CreateChannelReq struct {
+	UserID     string
+	Kind       string
+	Name       string
+	Target     string
+	Credential string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r CreateChannelReq) Parse() (channels.CreateReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return channels.CreateReq{}, err
+	}
+
+	kind, err := channels.ParseKind(r.Kind)
+	if err != nil {
+		return channels.CreateReq{}, err
+	}
+
+	return channels.CreateReq{
+		UserID:     userID,
+		Kind:       kind,
+		Name:       r.Name,
+		Target:     r.Target,
+		Credential: r.Credential,
+	}, nil
+}
+
+type ChannelReq struct {
+	ID     string
+	UserID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r ChannelReq) Parse() (channels.GetReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return channels.GetReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return channels.GetReq{}, err
+	}
+
+	return channels.GetReq{
+		ID:     id,
+		UserID: userID,
+	}, nil
+}
+
+type SetTemplateReq struct {
+	ChannelID string
+	UserID    string
+	Event     string
+	Body      string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SetTemplateReq) Parse() (channels.SetTemplateReq, error) {
+	channelID, err := common.ParseID(r.ChannelID)
+	if err != nil {
+		return channels.SetTemplateReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return channels.SetTemplateReq{}, err
+	}
+
+	event, err := notifier.ParseEvent(r.Event)
+	if err != nil {
+		return channels.SetTemplateReq{}, err
+	}
+
+	return channels.SetTemplateReq{
+		ChannelID: channelID,
+		UserID:    userID,
+		Event:     event,
+		Body:      r.Body,
+	}, nil
+}
+
+type AddRuleReq struct {
+	ChannelID     string
+	UserID        string
+	DomainPattern string
+	Tag           string
+	MinSeverity   string
+	// Destination is the Slack channel picked for Slack app channels, as "ID|name".
+	Destination string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r AddRuleReq) Parse() (channels.AddRuleReq, error) {
+	channelID, err := common.ParseID(r.ChannelID)
+	if err != nil {
+		return channels.AddRuleReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return channels.AddRuleReq{}, err
+	}
+
+	tag := ""
+	if strings.TrimSpace(r.Tag) != "" {
+		t, err := certs.ParseTag(r.Tag)
+		if err != nil {
+			return channels.AddRuleReq{}, err
+		}
+		tag = t.String()
+	}
+
+	severity, err := notifier.ParseSeverity(r.MinSeverity)
+	if err != nil {
+		return channels.AddRuleReq{}, err
+	}
+
+	destination, destinationName, _ := strings.Cut(r.Destination, "|")
+
+	return channels.AddRuleReq{
+		ChannelID:       channelID,
+		UserID:          userID,
+		DomainPattern:   r.DomainPattern,
+		Tag:             tag,
+		MinSeverity:     severity,
+		Destination:     destination,
+		DestinationName: destinationName,
+	}, nil
+}
+
+// TransportChannel represents a Channel in the Transport layer.
+type TransportChannel struct {
+	ID         string
+	Kind       string
+	KindLabel  string
+	Name       string
+	Target     string
+	Secret     string
+	IsSlackApp bool
+	Enabled    bool
+	Confirmed  bool
+	Failures   int
+	Rules      []TransportRule
+	Templates  []TransportTemplate
+}
+
+// TransportRule represents a routing Rule in the Transport layer.
+type TransportRule struct {
+	ID          string
+	Description string
+}
+
+// TransportTemplate represents a message Template in the Transport layer.
+type TransportTemplate struct {
+	Event      string
+	EventLabel string
+	Body       string
+}
+
+// TransportOption is an option of a select.
+type TransportOption struct {
+	Value string
+	Label string
+}
+
+func channelKinds() []TransportOption {
+	kinds := []TransportOption{}
+	for _, k := range channels.Kinds {
+		// App channels are created by installing the app, not by pasting a target.
+		if k.IsApp() {
+			continue
+		}
+		kinds = append(kinds, TransportOption{Value: k.String(), Label: k.Label()})
+	}
+	return kinds
+}
+
+func messageVariables() []TransportOption {
+	vars := make([]TransportOption, len(notifier.MessageVariables))
+	for i, v := range notifier.MessageVariables {
+		vars[i] = TransportOption{Value: v.Name, Label: v.Description}
+	}
+	return vars
+}
+
+func messageEvents() []TransportOption {
+	events := make([]TransportOption, len(notifier.Events))
+	for i, e := range notifier.Events {
+		events[i] = TransportOption{Value: string(e), Label: e.Label()}
+	}
+	return events
+}
+
+// channelToTransportAdapter transforms a Channel from the Service layer to the Transport layer.
+func channelToTransportAdapter(c channels.Channel) TransportChannel {
+	target := c.Target
+	if c.Kind == channels.KindPagerDuty || c.Kind == channels.KindOpsgenie {
+		target = maskKey(target)
+	}
+	if c.Kind == channels.KindWebPush {
+		target = "Every browser you enabled notifications in"
+	}
+
+	// Only webhook secrets are meant to be shared, the others are credentials.
+	secret := c.Secret
+	if c.Kind != channels.KindWebhook {
+		secret = ""
+	}
+
+	rules := make([]TransportRule, len(c.Rules))
+	for i, r := range c.Rules {
+		rules[i] = TransportRule{ID: r.ID.String(), Description: describeRule(r)}
+	}
+
+	templates := make([]TransportTemplate, len(c.Templates))
+	for i, t := range c.Templates {
+		templates[i] = TransportTemplate{Event: string(t.Event), EventLabel: t.Event.Label(), Body: t.Body}
+	}
+
+	return TransportChannel{
+		ID:         c.ID.String(),
+		Kind:       c.Kind.String(),
+		KindLabel:  c.Kind.Label(),
+		Name:       c.Name,
+		Target:     target,
+		Secret:     secret,
+		IsSlackApp: c.Kind == channels.KindSlackApp,
+		Enabled:    c.Enabled,
+		Confirmed:  c.Confirmed,
+		Failures:   c.Failures,
+		Rules:      rules,
+		Templates:  templates,
+	}
+}
+
+func describeRule(r channels.Rule) string {
+	conditions := []string{}
+	if r.DomainPattern != "" {
+		conditions = append(conditions, fmt.Sprintf("domain matches %s", r.DomainPattern))
+	}
+	if r.Tag != "" {
+		conditions = append(conditions, fmt.Sprintf("tagged %s", r.Tag))
+	}
+	if r.MinSeverity != notifier.SeverityInfo {
+		conditions = append(conditions, fmt.Sprintf("%s or worse", r.MinSeverity))
+	}
+
+	description := "Any notification"
+	if len(conditions) > 0 {
+		description = strings.Join(conditions, ", ")
+	}
+	if r.DestinationName != "" {
+		description += fmt.Sprintf(", posted to #%s", r.DestinationName)
+	}
+	return description
+}
+
+// maskKey hides all but the last characters of integration keys.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
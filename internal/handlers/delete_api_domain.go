@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func APIDeleteDomain(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+		id := r.PathValue("id")
+
+		req := DeleteCertReq{UserID: userID, ID: id}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = certsService.Delete(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, certs.ErrNotFound) {
+				sendAPIError(w, http.StatusNotFound, "domain not found")
+			} else {
+				logger.ErrorContext(r.Context(), "error deleting domain", "err", err.Error(), "domain", id, "user", userID)
+				sendAPIError(w, http.StatusInternalServerError, "error deleting domain")
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "deleted domain from the API", "domain", id, "user", userID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func DeleteChannel(logger *slog.Logger, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+		id := r.PathValue("id")
+
+		req := ChannelReq{ID: id, UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = channelsService.Delete(r.Context(), channels.DeleteReq{ID: parsedReq.ID, UserID: parsedReq.UserID})
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Channel not found", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error deleting channel", "err", err.Error(), "channel", id, "user", userID)
+				http.Error(w, "Error deleting channel", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "deleted notification channel", "channel", id, "user", userID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
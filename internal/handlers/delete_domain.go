@@ -30,13 +30,13 @@ func DeleteDomain(logger *slog.Logger, certsService certs.Service) http.HandlerF
 			if errors.Is(err, certs.ErrNotFound) {
 				http.Error(w, "Domain not found", http.StatusNotFound)
 			} else {
-				logger.Error("error deleting domain", "err", err.Error(), "domain", id, "user", userID)
+				logger.ErrorContext(r.Context(), "error deleting domain", "err", err.Error(), "domain", id, "user", userID)
 				http.Error(w, "Error deleting domain", http.StatusInternalServerError)
 			}
 			return
 		}
 
-		logger.Info("deleted domain", "domain", id, "user", userID)
+		logger.InfoContext(r.Context(), "deleted domain", "domain", id, "user", userID)
 		w.WriteHeader(http.StatusOK)
 	}
 }
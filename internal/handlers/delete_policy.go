@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/escalation"
+)
+
+func DeletePolicy(escalationService escalation.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := PolicyReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = escalationService.DeletePolicy(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, escalation.ErrNotFound) {
+				http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error deleting escalation policy", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
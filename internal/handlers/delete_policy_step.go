@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/escalation"
+)
+
+func DeletePolicyStep(escalationService escalation.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := PolicyReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stepID, err := common.ParseID(r.PathValue("stepID"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		policy, err := escalationService.DeleteStep(r.Context(), escalation.DeleteStepReq{
+			ID:       stepID,
+			PolicyID: parsedReq.ID,
+			UserID:   parsedReq.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, escalation.ErrStepNotFound) || errors.Is(err, escalation.ErrNotFound) {
+				http.Error(w, "Escalation step not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error deleting escalation step", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: parsedReq.UserID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		c := PolicyCard(policyToTransportAdapter(policy, chs))
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/push"
+)
+
+// PushUnsubscribe deletes the push subscription of the browser, the channel is kept for the other browsers of the user.
+func PushUnsubscribe(logger *slog.Logger, pushService push.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := PushUnsubscribeReq{UserID: userID}
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushBody)).Decode(&req)
+		if err != nil {
+			http.Error(w, "Invalid push subscription", http.StatusBadRequest)
+			return
+		}
+
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = pushService.Unsubscribe(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, push.ErrNotFound) {
+				http.Error(w, "Push subscription not found", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error deleting push subscription", "err", err.Error(), "user", userID)
+				http.Error(w, "Error deleting push subscription", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "unsubscribed browser from push notifications", "user", userID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+)
+
+func DeleteRule(channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ChannelReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ruleID, err := common.ParseID(r.PathValue("ruleID"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = channelsService.DeleteRule(r.Context(), channels.DeleteRuleReq{ID: ruleID, UserID: parsedReq.UserID})
+		if err != nil {
+			if errors.Is(err, channels.ErrRuleNotFound) {
+				http.Error(w, "Rule not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error deleting rule", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		channel, err := channelsService.Get(r.Context(), parsedReq)
+		if err != nil {
+			http.Error(w, "Error getting channel", http.StatusInternalServerError)
+			return
+		}
+
+		c := ChannelCard(channelToTransportAdapter(channel))
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+func DeleteTemplate(channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ChannelReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		event, err := notifier.ParseEvent(r.PathValue("event"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		channel, err := channelsService.DeleteTemplate(r.Context(), channels.DeleteTemplateReq{
+			ChannelID: parsedReq.ID,
+			UserID:    parsedReq.UserID,
+			Event:     event,
+		})
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Template not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error deleting template", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		c := ChannelCard(channelToTransportAdapter(channel))
+		SendTempl(w, r, c)
+	}
+}
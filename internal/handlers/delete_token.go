@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func RevokeToken(logger *slog.Logger, tokensService apitokens.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+		id := r.PathValue("id")
+
+		req := TokenReq{ID: id, UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = tokensService.Revoke(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, apitokens.ErrNotFound) {
+				http.Error(w, "Access token not found", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error revoking access token", "err", err.Error(), "token", id, "user", userID)
+				http.Error(w, "Error revoking access token", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "revoked access token", "token", id, "user", userID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/germandv/domainator/internal/certs"
 	"github.com/germandv/domainator/internal/cntxt"
 	"github.com/germandv/domainator/internal/common"
 	"github.com/germandv/domainator/internal/tlsermock"
+	"github.com/germandv/domainator/internal/users"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,7 +33,9 @@ func TestRegisterDomain(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	certsRepo := certs.NewRepo(db)
-	certsService := certs.NewService(tlsermock.New(), certsRepo, 2)
+	certsService := certs.NewService(tlsermock.New(), certsRepo, nil, 2)
+	usersService := users.NewService(users.NewRepo(db))
+	runProbes(t, certsService, logger)
 
 	t.Run("register_new_domain", func(t *testing.T) {
 		formData := url.Values{}
@@ -43,7 +47,7 @@ func TestRegisterDomain(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
 
-		handler := RegisterDomain(logger, certsService)
+		handler := RegisterDomain(logger, certsService, usersService)
 		handler.ServeHTTP(w, r)
 
 		if w.Code != 200 {
@@ -54,11 +58,19 @@ func TestRegisterDomain(t *testing.T) {
 		if !strings.Contains(resp, `<th scope="row" class="w-250">example.com</th>`) {
 			t.Errorf("Domain col not found in response: %s", resp)
 		}
-		if !strings.Contains(resp, `<td class="w-250">Test-Issuer</td>`) {
-			t.Errorf("Issuer col not found in response: %s", resp)
+		if !strings.Contains(resp, `<td><span class="chip">Checking…</span></td>`) {
+			t.Errorf("Checking status not included in response: %s", resp)
 		}
-		if !strings.Contains(resp, `<td><span class="chip">Expires in 29 days</span></td>`) {
-			t.Errorf("Expiration col not included in response: %s", resp)
+
+		cert, err := waitForCheck(certsService, "example.com", "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.Issuer.String() != "Test-Issuer" {
+			t.Errorf("Expected issuer Test-Issuer, got %s", cert.Issuer.String())
+		}
+		if days := int(time.Until(cert.ExpiresAt).Hours() / 24); days != 29 {
+			t.Errorf("Expected the cert to expire in 29 days, got %d", days)
 		}
 	})
 
@@ -72,7 +84,7 @@ func TestRegisterDomain(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
 
-		handler := RegisterDomain(logger, certsService)
+		handler := RegisterDomain(logger, certsService, usersService)
 		handler.ServeHTTP(w, r)
 
 		if w.Code != 400 {
@@ -90,16 +102,19 @@ func TestRegisterDomain(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
 
-		handler := RegisterDomain(logger, certsService)
+		handler := RegisterDomain(logger, certsService, usersService)
 		handler.ServeHTTP(w, r)
 
 		if w.Code != 200 {
 			t.Errorf("Expected status code 200, got %d", w.Code)
 		}
 
-		resp := w.Body.String()
-		if !strings.Contains(resp, `<td><span class="chip error-text">Expired</span></td>`) {
-			t.Errorf("Expiration col not included in response: %s", resp)
+		cert, err := waitForCheck(certsService, "expired.com", "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.ExpiresAt.After(time.Now()) {
+			t.Errorf("Expected the cert to be expired, it expires at %s", cert.ExpiresAt)
 		}
 	})
 
@@ -111,13 +126,22 @@ func TestRegisterDomain(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest("POST", "/domain", body)
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
+		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a892")
 
-		handler := RegisterDomain(logger, certsService)
+		handler := RegisterDomain(logger, certsService, usersService)
 		handler.ServeHTTP(w, r)
 
-		if w.Code != 400 {
-			t.Errorf("Expected status code 400, got %d", w.Code)
+		if w.Code != 200 {
+			t.Errorf("Expected status code 200, got %d", w.Code)
+		}
+
+		// The domain is registered and the error of the check is kept, so it can be fixed.
+		cert, err := waitForCheck(certsService, "notconnect.com", "018ec52b-dd69-7df4-b8e7-edcdc9a3a892")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.Error == "" {
+			t.Errorf("Expected the error of the check, got none")
 		}
 	})
 
@@ -131,7 +155,7 @@ func TestRegisterDomain(t *testing.T) {
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a891")
 
-		handler := RegisterDomain(logger, certsService)
+		handler := RegisterDomain(logger, certsService, usersService)
 		handler.ServeHTTP(w, r)
 
 		if w.Code != 400 {
@@ -150,7 +174,8 @@ func TestDeleteDomain(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	certsRepo := certs.NewRepo(db)
-	certsService := certs.NewService(tlsermock.New(), certsRepo, 2)
+	certsService := certs.NewService(tlsermock.New(), certsRepo, nil, 2)
+	usersService := users.NewService(users.NewRepo(db))
 
 	// Register a domain.
 	formData := url.Values{}
@@ -160,7 +185,7 @@ func TestDeleteDomain(t *testing.T) {
 	r := httptest.NewRequest("POST", "/domain", body)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a444")
-	handler := RegisterDomain(logger, certsService)
+	handler := RegisterDomain(logger, certsService, usersService)
 	handler.ServeHTTP(w, r)
 	if w.Code != 200 {
 		t.Errorf("Expected 200 when registering, got %d", w.Code)
@@ -170,7 +195,7 @@ func TestDeleteDomain(t *testing.T) {
 	w = httptest.NewRecorder()
 	r = httptest.NewRequest("GET", "/", nil)
 	r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a444")
-	handler = GetDashboard(certsService)
+	handler = GetDashboard(certsService, usersService)
 	handler.ServeHTTP(w, r)
 	if w.Code != 200 {
 		t.Errorf("Expected 200 when fetching dashboard page, got %d", w.Code)
@@ -201,7 +226,7 @@ func TestDeleteDomain(t *testing.T) {
 	w = httptest.NewRecorder()
 	r = httptest.NewRequest("GET", "/", nil)
 	r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a444")
-	handler = GetDashboard(certsService)
+	handler = GetDashboard(certsService, usersService)
 	handler.ServeHTTP(w, r)
 	if w.Code != 200 {
 		t.Errorf("Expected 200 when fetching dashboard page, got %d", w.Code)
@@ -217,7 +242,9 @@ func TestUpdateDomain(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	certsRepo := certs.NewRepo(db)
-	certsService := certs.NewService(tlsermock.New(), certsRepo, 2)
+	certsService := certs.NewService(tlsermock.New(), certsRepo, nil, 2)
+	usersService := users.NewService(users.NewRepo(db))
+	runProbes(t, certsService, logger)
 
 	// Register a domain.
 	formData := url.Values{}
@@ -227,16 +254,16 @@ func TestUpdateDomain(t *testing.T) {
 	r := httptest.NewRequest("POST", "/domain", body)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
-	handler := RegisterDomain(logger, certsService)
+	handler := RegisterDomain(logger, certsService, usersService)
 	handler.ServeHTTP(w, r)
 	if w.Code != 200 {
 		t.Errorf("Expected 200 when registering, got %d", w.Code)
 	}
 
-	// Find created cert.
-	certBefore, err := getCert(certsService, "foobar.io", "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
+	// Find created cert, once it's checked.
+	certBefore, err := waitForCheck(certsService, "foobar.io", "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
 	// Update domain.
@@ -244,17 +271,20 @@ func TestUpdateDomain(t *testing.T) {
 	r = httptest.NewRequest("PUT", fmt.Sprintf("/domain/%s", certBefore.ID.String()), nil)
 	r.SetPathValue("id", certBefore.ID.String())
 	r = cntxt.SetUserID(r, "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
-	handler = UpdateDomain(logger, certsService)
+	handler = UpdateDomain(logger, certsService, usersService)
 	handler.ServeHTTP(w, r)
 	if w.Code != 200 {
 		t.Errorf("Expected 200 when updating domain, got %d", w.Code)
 		t.Error(w.Body.String())
 	}
+	if resp := w.Body.String(); !strings.Contains(resp, `<td><span class="chip">Checking…</span></td>`) {
+		t.Errorf("Checking status not included in response: %s", resp)
+	}
 
 	// Fetch updated cert.
-	certAfter, err := getCert(certsService, "foobar.io", "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
+	certAfter, err := waitForCheck(certsService, "foobar.io", "018ec52b-dd69-7df4-b8e7-edcdc9a3a077")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	if certAfter.ID != certBefore.ID {
 		t.Errorf("Domain ID should not have changed")
@@ -283,3 +313,28 @@ func getCert(svc certs.Service, domain string, userID string) (*certs.Cert, erro
 
 	return nil, fmt.Errorf("could not find cert for domain %s", domain)
 }
+
+// runProbes checks the registered and refreshed domains until the test finishes.
+func runProbes(t *testing.T, svc certs.Service, logger *slog.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go svc.RunProbes(ctx, 1, logger)
+}
+
+// waitForCheck returns the cert of the domain once it's no longer queued to be checked.
+func waitForCheck(svc certs.Service, domain string, userID string) (*certs.Cert, error) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		cert, err := getCert(svc, domain, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !cert.Checking {
+			return cert, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("domain %s is still being checked", domain)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
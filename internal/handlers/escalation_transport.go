@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/escalation"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+type CreatePolicyReq struct {
+	UserID      string
+	Name        string
+	MinSeverity string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r CreatePolicyReq) Parse() (escalation.CreatePolicyReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return escalation.CreatePolicyReq{}, err
+	}
+
+	severity, err := notifier.ParseSeverity(r.MinSeverity)
+	if err != nil {
+		return escalation.CreatePolicyReq{}, err
+	}
+
+	return escalation.CreatePolicyReq{
+		UserID:      userID,
+		Name:        r.Name,
+		MinSeverity: severity,
+	}, nil
+}
+
+type PolicyReq struct {
+	ID     string
+	UserID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r PolicyReq) Parse() (escalation.DeletePolicyReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return escalation.DeletePolicyReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return escalation.DeletePolicyReq{}, err
+	}
+
+	return escalation.DeletePolicyReq{
+		ID:     id,
+		UserID: userID,
+	}, nil
+}
+
+type AddStepReq struct {
+	PolicyID   string
+	UserID     string
+	ChannelID  string
+	DelayHours string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r AddStepReq) Parse() (escalation.AddStepReq, error) {
+	policyID, err := common.ParseID(r.PolicyID)
+	if err != nil {
+		return escalation.AddStepReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return escalation.AddStepReq{}, err
+	}
+
+	channelID, err := common.ParseID(r.ChannelID)
+	if err != nil {
+		return escalation.AddStepReq{}, err
+	}
+
+	delay, err := strconv.Atoi(r.DelayHours)
+	if err != nil {
+		return escalation.AddStepReq{}, escalation.ErrInvalidDelay
+	}
+
+	return escalation.AddStepReq{
+		PolicyID:   policyID,
+		UserID:     userID,
+		ChannelID:  channelID,
+		DelayHours: delay,
+	}, nil
+}
+
+type SnoozeIncidentReq struct {
+	ID    common.ID
+	Hours string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SnoozeIncidentReq) Parse() (escalation.SnoozeReq, error) {
+	hours, err := strconv.Atoi(r.Hours)
+	if err != nil {
+		return escalation.SnoozeReq{}, escalation.ErrInvalidSnooze
+	}
+
+	return escalation.SnoozeReq{
+		ID:    r.ID,
+		Hours: hours,
+	}, nil
+}
+
+// TransportPolicy represents an escalation Policy in the Transport layer.
+type TransportPolicy struct {
+	ID       string
+	Name     string
+	Severity string
+	Steps    []TransportStep
+	Channels []TransportOption
+}
+
+// TransportStep represents a Step of an escalation Policy in the Transport layer.
+type TransportStep struct {
+	ID          string
+	Description string
+}
+
+// TransportIncident represents an Incident in the Transport layer.
+type TransportIncident struct {
+	ID              string
+	PolicyName      string
+	Domain          string
+	Status          string
+	State           string
+	Step            string
+	NextEscalation  string
+	AcknowledgedBy  string
+	AcknowledgedVia string
+	AcknowledgedAt  string
+	OpenedAt        string
+	ResolvedAt      string
+}
+
+// policyToTransportAdapter transforms a Policy from the Service layer to the Transport layer,
+// chs are the channels that can be added as steps.
+func policyToTransportAdapter(p escalation.Policy, chs []channels.Channel) TransportPolicy {
+	steps := make([]TransportStep, len(p.Steps))
+	for i, s := range p.Steps {
+		when := "right away"
+		if i > 0 {
+			when = fmt.Sprintf("%d hours later if not acknowledged", s.DelayHours)
+		}
+		steps[i] = TransportStep{ID: s.ID.String(), Description: fmt.Sprintf("Notify %s %s", s.ChannelName, when)}
+	}
+
+	options := make([]TransportOption, len(chs))
+	for i, c := range chs {
+		options[i] = TransportOption{Value: c.ID.String(), Label: c.Name}
+	}
+
+	return TransportPolicy{
+		ID:       p.ID.String(),
+		Name:     p.Name,
+		Severity: string(p.MinSeverity),
+		Steps:    steps,
+		Channels: options,
+	}
+}
+
+// incidentToTransportAdapter transforms an Incident from the Service layer to the Transport layer,
+// showing dates in the given location.
+func incidentToTransportAdapter(i escalation.Incident, loc *time.Location) TransportIncident {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(loc).Format(time.DateTime)
+	}
+
+	return TransportIncident{
+		ID:              i.ID.String(),
+		PolicyName:      i.PolicyName,
+		Domain:          i.Domain,
+		Status:          i.Status,
+		State:           string(i.State),
+		Step:            strconv.Itoa(i.Step + 1),
+		NextEscalation:  formatTime(i.NextEscalationAt),
+		AcknowledgedBy:  i.AcknowledgedBy,
+		AcknowledgedVia: i.AcknowledgedVia,
+		AcknowledgedAt:  formatTime(i.AcknowledgedAt),
+		OpenedAt:        formatTime(i.OpenedAt),
+		ResolvedAt:      formatTime(i.ResolvedAt),
+	}
+}
+
+func snoozeOptions() []TransportOption {
+	return []TransportOption{
+		{Value: "1", Label: "1 hour"},
+		{Value: "4", Label: "4 hours"},
+		{Value: "24", Label: "1 day"},
+		{Value: strconv.Itoa(escalation.MaxHours), Label: "1 week"},
+	}
+}
+
+// parseAckToken verifies the token of an acknowledge link and returns the incident and channel IDs it carries.
+func parseAckToken(linkSigner *signer.Signer, token string) (common.ID, common.ID, error) {
+	value, err := linkSigner.Verify(escalation.AckPurpose, token)
+	if err != nil {
+		return common.ID{}, common.ID{}, err
+	}
+	return escalation.ParseAckValue(value)
+}
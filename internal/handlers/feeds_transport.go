@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/atom"
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/ical"
+	"github.com/germandv/domainator/internal/users"
+)
+
+const (
+	calendarProdID = "-//Domainator//Certificate Expiry//EN"
+	maxFeedEntries = 50
+	maxAlarms      = 5
+	maxAlarmDays   = 90
+)
+
+var (
+	// defaultAlarms remind of an expiry a week and a day before it.
+	defaultAlarms    = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour}
+	ErrInvalidAlarms = fmt.Errorf("alarms must be up to %d comma separated numbers of days between 0 and %d", maxAlarms, maxAlarmDays)
+)
+
+type CalendarFeedReq struct {
+	Token string
+	Tag   string
+	// Alarms are days before the expiry, nil when not given so the default ones are used.
+	Alarms *string
+}
+
+type ParsedCalendarFeedReq struct {
+	Token  string
+	Tag    certs.Tag
+	Alarms []time.Duration
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r CalendarFeedReq) Parse() (ParsedCalendarFeedReq, error) {
+	req := ParsedCalendarFeedReq{Token: r.Token, Alarms: defaultAlarms}
+
+	if r.Tag != "" {
+		tag, err := certs.ParseTag(r.Tag)
+		if err != nil {
+			return ParsedCalendarFeedReq{}, err
+		}
+		req.Tag = tag
+	}
+
+	if r.Alarms != nil {
+		alarms, err := parseAlarms(*r.Alarms)
+		if err != nil {
+			return ParsedCalendarFeedReq{}, err
+		}
+		req.Alarms = alarms
+	}
+
+	return req, nil
+}
+
+// parseAlarms parses days before an event, e.g. "14,3,0", an empty string means no alarms.
+func parseAlarms(s string) ([]time.Duration, error) {
+	alarms := []time.Duration{}
+	if strings.TrimSpace(s) == "" {
+		return alarms, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) > maxAlarms {
+		return nil, ErrInvalidAlarms
+	}
+
+	for _, p := range parts {
+		days, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || days < 0 || days > maxAlarmDays {
+			return nil, ErrInvalidAlarms
+		}
+		alarms = append(alarms, time.Duration(days)*24*time.Hour)
+	}
+
+	return alarms, nil
+}
+
+// certsToCalendar makes an all-day event, on the date in the user's timezone, for the expiry of each certificate,
+// certificates that could not be checked yet have no expiry and are left out.
+func certsToCalendar(cs []certs.Cert, u users.User, req ParsedCalendarFeedReq, appURL string) ical.Calendar {
+	name := "Domainator certificates"
+	if req.Tag != (certs.Tag{}) {
+		name += " (" + req.Tag.String() + ")"
+	}
+
+	loc := u.Timezone.Location()
+	events := []ical.Event{}
+	for _, c := range cs {
+		if c.ExpiresAt.IsZero() || !hasTag(c, req.Tag) {
+			continue
+		}
+
+		expiresAt := c.ExpiresAt.In(loc)
+		details := []string{fmt.Sprintf("Expires at %s (%s)", expiresAt.Format("2006-01-02 15:04"), loc)}
+		if c.Issuer.String() != "" {
+			details = append(details, "Issuer: "+c.Issuer.String())
+		}
+		if len(c.Tags) > 0 {
+			tags := make([]string, len(c.Tags))
+			for i, t := range c.Tags {
+				tags[i] = t.String()
+			}
+			details = append(details, "Tags: "+strings.Join(tags, ", "))
+		}
+		if c.Error != "" {
+			details = append(details, "Last check failed: "+c.Error)
+		}
+
+		events = append(events, ical.Event{
+			UID:         c.ID.String() + "@domainator",
+			Summary:     "TLS certificate of " + c.Domain.String() + " expires",
+			Description: strings.Join(details, "\n"),
+			URL:         appURL + "/dashboard",
+			Date:        expiresAt,
+			Modified:    c.UpdatedAt,
+			Alarms:      req.Alarms,
+		})
+	}
+
+	return ical.Calendar{ProdID: calendarProdID, Name: name, Events: events}
+}
+
+// hasTag reports whether the cert has the tag, every cert has the zero Tag.
+func hasTag(c certs.Cert, tag certs.Tag) bool {
+	if tag == (certs.Tag{}) {
+		return true
+	}
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// eventsToFeed makes an Atom entry of each event, identified by the ID of the event so readers don't show it twice.
+// The feed is as recent as its newest entry, so it only changes when there are new events.
+func eventsToFeed(events []certs.Event, u users.User, appURL string) atom.Feed {
+	updated := u.CreatedAt
+	if len(events) > 0 {
+		updated = events[0].CreatedAt
+	}
+
+	feed := atom.NewFeed("urn:uuid:"+u.ID.String(), "Domainator certificate changes", "Domainator", updated)
+	feed.Links = append(feed.Links, atom.Link{Href: appURL + "/dashboard", Rel: "alternate", Type: "text/html"})
+	if u.FeedToken != "" {
+		feed.Links = append(feed.Links, atom.Link{Href: appURL + "/feeds/" + u.FeedToken + "/events.atom", Rel: "self", Type: "application/atom+xml"})
+	}
+
+	for _, e := range events {
+		feed.Entries = append(feed.Entries, atom.Entry{
+			ID:       "urn:uuid:" + e.ID.String(),
+			Title:    eventTitle(e),
+			Updated:  atom.Time(e.CreatedAt),
+			Links:    []atom.Link{{Href: appURL + "/dashboard", Rel: "alternate", Type: "text/html"}},
+			Summary:  e.Detail,
+			Category: []atom.Category{{Term: string(e.Kind)}},
+		})
+	}
+
+	return feed
+}
+
+func eventTitle(e certs.Event) string {
+	switch e.Kind {
+	case certs.EventRegistered:
+		return e.Domain + " is now monitored"
+	case certs.EventRenewed:
+		return e.Domain + " was renewed"
+	case certs.EventExpiring:
+		return e.Domain + " " + e.Detail
+	case certs.EventExpired:
+		return e.Domain + " expired"
+	case certs.EventError:
+		return e.Domain + " cannot be checked"
+	case certs.EventRecovered:
+		return e.Domain + " is reachable again"
+	case certs.EventDeleted:
+		return e.Domain + " is no longer monitored"
+	default:
+		return e.Domain + ": " + string(e.Kind)
+	}
+}
+
+// TransportFeeds are the URLs of the feeds of a user, empty until they create a feed token.
+type TransportFeeds struct {
+	CalendarURL string
+	EventsURL   string
+}
+
+func feedsToTransportAdapter(u users.User, appURL string) TransportFeeds {
+	if u.FeedToken == "" {
+		return TransportFeeds{}
+	}
+	return TransportFeeds{
+		CalendarURL: appURL + "/feeds/" + u.FeedToken + "/calendar.ics",
+		EventsURL:   appURL + "/feeds/" + u.FeedToken + "/events.atom",
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func APIGetDomains(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := GetAllCertsReq{UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			sendAPIError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		cs, err := certsService.GetAll(r.Context(), parsedReq)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error getting domains", "err", err.Error(), "user", userID)
+			sendAPIError(w, http.StatusInternalServerError, "error getting domains")
+			return
+		}
+
+		now := time.Now()
+		domains := make([]APIDomain, len(cs))
+		for i, c := range cs {
+			domains[i] = certToAPIAdapter(c, now)
+		}
+
+		sendJSON(w, http.StatusOK, APIDomains{Domains: domains})
+	}
+}
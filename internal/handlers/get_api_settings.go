@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func APIGetSettings(logger *slog.Logger, usersService users.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			sendAPIError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		u, err := usersService.GetByID(r.Context(), users.GetByIDReq{UserID: userID})
+		if err != nil {
+			sendAPIError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: userID})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error getting channels", "err", err.Error(), "user", userID.String())
+			sendAPIError(w, http.StatusInternalServerError, "error getting channels")
+			return
+		}
+
+		sendJSON(w, http.StatusOK, userToAPISettingsAdapter(u, chs))
+	}
+}
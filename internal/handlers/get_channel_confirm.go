@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// GetChannelConfirm asks the owner of the address to confirm the email channel,
+// so that link scanners prefetching the URL don't confirm it.
+func GetChannelConfirm(linkSigner *signer.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		_, err := linkSigner.Verify(notifier.ConfirmPurpose, token)
+		if err != nil {
+			http.Error(w, "Invalid or expired confirmation link", http.StatusBadRequest)
+			return
+		}
+
+		c := Layout(ChannelConfirm(token), "Domainator | Confirm email")
+		SendTempl(w, r, c)
+	}
+}
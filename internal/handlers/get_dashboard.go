@@ -5,9 +5,10 @@ import (
 
 	"github.com/germandv/domainator/internal/certs"
 	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
 )
 
-func GetDashboard(certsService certs.Service) http.HandlerFunc {
+func GetDashboard(certsService certs.Service, usersService users.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := cntxt.GetUserID(r)
 		if userID == "" {
@@ -28,9 +29,10 @@ func GetDashboard(certsService certs.Service) http.HandlerFunc {
 			return
 		}
 
+		loc := userLocation(r, usersService)
 		transportCerts := make([]TransportCert, len(certificates))
 		for i, cert := range certificates {
-			transportCerts[i] = serviceToTransportAdapter(cert)
+			transportCerts[i] = serviceToTransportAdapter(cert, loc)
 		}
 
 		c := Layout(Dashboard(transportCerts), "Domainator | Dashboard")
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/updates"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// keepAliveInterval is how often a comment is sent, so proxies don't close an idle stream.
+const keepAliveInterval = 30 * time.Second
+
+// DashboardEvents streams the updates of the certs of the user as Server-Sent Events,
+// one named cert-<id> with the rendered CertRow for the htmx sse extension to swap.
+func DashboardEvents(
+	logger *slog.Logger,
+	broker *updates.Broker,
+	certsService certs.Service,
+	usersService users.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		// The stream outlives the read and write timeouts of the server.
+		rc := http.NewResponseController(w)
+		for _, err := range []error{rc.SetReadDeadline(time.Time{}), rc.SetWriteDeadline(time.Time{})} {
+			if err != nil && !errors.Is(err, http.ErrNotSupported) {
+				logger.ErrorContext(r.Context(), "failed to clear deadlines for events stream", "err", err)
+				http.Error(w, "Error opening events stream", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		certUpdates, unsubscribe := broker.Subscribe(userID)
+		defer unsubscribe()
+
+		loc := userLocation(r, usersService)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		err := rc.Flush()
+		if err != nil {
+			logger.ErrorContext(r.Context(), "failed to flush events stream", "err", err)
+			return
+		}
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				_, err = io.WriteString(w, ": keep-alive\n\n")
+			case update, ok := <-certUpdates:
+				if !ok {
+					return
+				}
+				err = sendCertEvent(r.Context(), w, logger, certsService, loc, userID, update.CertID)
+			}
+
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				logger.DebugContext(r.Context(), "events stream closed", "err", err)
+				return
+			}
+		}
+	}
+}
+
+// sendCertEvent skips certs that were deleted, or can't be read, since the update was published.
+func sendCertEvent(
+	ctx context.Context,
+	w io.Writer,
+	logger *slog.Logger,
+	certsService certs.Service,
+	loc *time.Location,
+	userID string,
+	certID string,
+) error {
+	req := GetCertReq{UserID: userID, ID: certID}
+	parsedReq, err := req.Parse()
+	if err != nil {
+		return nil
+	}
+
+	cert, err := certsService.Get(ctx, parsedReq)
+	if err != nil {
+		if !errors.Is(err, certs.ErrNotFound) {
+			logger.WarnContext(ctx, "failed to get updated cert", "id", certID, "err", err)
+		}
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	err = CertRow(serviceToTransportAdapter(cert, loc)).Render(ctx, buf)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to render updated cert", "id", certID, "err", err)
+		return nil
+	}
+
+	return writeEvent(w, "cert-"+certID, buf.String())
+}
+
+// writeEvent writes a Server-Sent Event, with a data field per line since they can't have newlines.
+func writeEvent(w io.Writer, name string, data string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", name)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"one_line", "<tr></tr>", "event: cert-1\ndata: <tr></tr>\n\n"},
+		{"multiline", "<tr>\n<td>a</td>\n</tr>", "event: cert-1\ndata: <tr>\ndata: <td>a</td>\ndata: </tr>\n\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			err := writeEvent(&b, "cert-1", tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, b.String())
+			}
+		})
+	}
+}
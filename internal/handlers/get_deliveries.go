@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/outbox"
+)
+
+func GetDeliveries(outboxService outbox.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		deliveries, err := outboxService.GetDeliveries(r.Context(), outbox.GetDeliveriesReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting deliveries", http.StatusInternalServerError)
+			return
+		}
+
+		transportDeliveries := make([]TransportDelivery, len(deliveries))
+		for i, d := range deliveries {
+			transportDeliveries[i] = deliveryToTransportAdapter(d)
+		}
+
+		c := Layout(Deliveries(transportDeliveries), "Domainator | Delivery Log")
+		SendTempl(w, r, c)
+	}
+}
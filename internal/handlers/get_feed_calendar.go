@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// GetCalendarFeed serves the iCalendar feed of the expiry dates of the certificates of the user the token belongs to,
+// optionally only those with a tag (?tag=prod) and with custom reminders (?alarms=14,3 days before).
+func GetCalendarFeed(logger *slog.Logger, usersService users.Service, certsService certs.Service, appURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := CalendarFeedReq{Token: r.PathValue("token"), Tag: r.URL.Query().Get("tag")}
+		if r.URL.Query().Has("alarms") {
+			alarms := r.URL.Query().Get("alarms")
+			req.Alarms = &alarms
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		u, err := usersService.GetByFeedToken(r.Context(), users.GetByFeedTokenReq{Token: parsedReq.Token})
+		if err != nil {
+			if errors.Is(err, users.ErrNotFound) {
+				http.Error(w, "Feed not found", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error getting user of calendar feed", "err", err.Error())
+				http.Error(w, "Error getting calendar", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		cs, err := certsService.GetAll(r.Context(), certs.GetAllReq{UserID: u.ID})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error getting certificates of calendar feed", "err", err.Error(), "user", u.ID.String())
+			http.Error(w, "Error getting calendar", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="domainator.ics"`)
+		err = certsToCalendar(cs, u, parsedReq, appURL).Write(w, time.Now())
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error writing calendar feed", "err", err.Error(), "user", u.ID.String())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// GetEventsFeed serves the Atom feed of the latest changes in the certificates of the user the token belongs to:
+// status transitions, renewals and errors found by the worker.
+func GetEventsFeed(logger *slog.Logger, usersService users.Service, certsService certs.Service, appURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u, err := usersService.GetByFeedToken(r.Context(), users.GetByFeedTokenReq{Token: r.PathValue("token")})
+		if err != nil {
+			if errors.Is(err, users.ErrNotFound) {
+				http.Error(w, "Feed not found", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error getting user of events feed", "err", err.Error())
+				http.Error(w, "Error getting feed", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		events, err := certsService.GetEvents(r.Context(), certs.GetEventsReq{UserID: u.ID, Since: time.Time{}, Limit: maxFeedEntries})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error getting events of feed", "err", err.Error(), "user", u.ID.String())
+			http.Error(w, "Error getting feed", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		err = eventsToFeed(events, u, appURL).Write(w)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error writing events feed", "err", err.Error(), "user", u.ID.String())
+		}
+	}
+}
@@ -37,13 +37,13 @@ func GithubLogin(logger *slog.Logger, githubConfig *oauth2.Config, cookieSigning
 			Expires:  time.Now().Add(5 * time.Minute),
 		}, cookieSigningSecret)
 		if err != nil {
-			logger.Error("error writing signed cookie", "err", err.Error())
+			logger.ErrorContext(r.Context(), "error writing signed cookie", "err", err.Error())
 			http.Error(w, "something went wrong", http.StatusInternalServerError)
 			return
 		}
 
 		url := githubConfig.AuthCodeURL(state)
-		logger.Info("redirecting to GitHub for sign in")
+		logger.InfoContext(r.Context(), "redirecting to GitHub for sign in")
 		http.Redirect(w, r, url, http.StatusTemporaryRedirect)
 	}
 }
@@ -61,14 +61,14 @@ func GithubCallback(
 
 		stateCookie, err := cookies.ReadSigned(r, stateCookieName, cookieSigningSecret)
 		if err != nil || state != stateCookie {
-			logger.Error("error reading signed cookie or comparing state", "err", err.Error(), "state", state, "stateCookie", stateCookie)
+			logger.ErrorContext(r.Context(), "error reading signed cookie or comparing state", "err", err.Error(), "state", state, "stateCookie", stateCookie)
 			http.Error(w, "Invalid or missing state", http.StatusUnauthorized)
 			return
 		}
 
 		token, err := githubConfig.Exchange(r.Context(), code)
 		if err != nil {
-			logger.Error("error in GitHub exchange", "err", err.Error())
+			logger.ErrorContext(r.Context(), "error in GitHub exchange", "err", err.Error())
 			http.Error(w, "something went wrong", http.StatusInternalServerError)
 			return
 		}
@@ -82,7 +82,7 @@ func GithubCallback(
 		if userData.Email == "" {
 			email, err := githubauth.GetGithubUserEmail(token)
 			if err != nil {
-				logger.Error("error getting GitHub user email", "err", err.Error())
+				logger.ErrorContext(r.Context(), "error getting GitHub user email", "err", err.Error())
 				http.Error(w, "something went wrong", http.StatusInternalServerError)
 				return
 			}
@@ -106,23 +106,23 @@ func GithubCallback(
 					IdentityProviderID: fmt.Sprintf("%d", userData.ID),
 				})
 				if err != nil {
-					logger.Error("error creating user", "email", email, "err", err.Error())
+					logger.ErrorContext(r.Context(), "error creating user", "email", email, "err", err.Error())
 					http.Error(w, "something went wrong", http.StatusInternalServerError)
 					return
 				}
-				logger.Info("user signed up", "email", email)
+				logger.InfoContext(r.Context(), "user signed up", "email", email)
 			} else {
-				logger.Error("unexpected error getting user by email", "email", email, "err", err.Error())
+				logger.ErrorContext(r.Context(), "unexpected error getting user by email", "email", email, "err", err.Error())
 				http.Error(w, "something went wrong", http.StatusInternalServerError)
 				return
 			}
 		} else {
-			logger.Info("user signed in", "email", email)
+			logger.InfoContext(r.Context(), "user signed in", "email", email)
 		}
 
 		jwt, err := authService.Generate(user.ID.String(), userData.AvatarURL)
 		if err != nil {
-			logger.Error("error generating JWT", "err", err.Error())
+			logger.ErrorContext(r.Context(), "error generating JWT", "err", err.Error())
 			http.Error(w, "something went wrong", http.StatusInternalServerError)
 			return
 		}
@@ -137,7 +137,7 @@ func GithubCallback(
 
 		err = cookies.Write(w, cookie)
 		if err != nil {
-			logger.Error("error writing cookie", "err", err.Error())
+			logger.ErrorContext(r.Context(), "error writing cookie", "err", err.Error())
 			http.Error(w, "something went wrong", http.StatusInternalServerError)
 			return
 		}
@@ -49,7 +49,7 @@ func GetHealthcheck(cacheClient cache.Client, db DBPinger) http.HandlerFunc {
 
 		if deep == "true" {
 			cacheStatus := "up"
-			err := cacheClient.Ping()
+			err := cacheClient.Ping(r.Context())
 			if err != nil {
 				cacheStatus = "down"
 			}
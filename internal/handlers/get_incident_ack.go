@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/escalation"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// GetIncidentAck asks for confirmation before acknowledging or snoozing the incident,
+// so that link scanners prefetching the URL don't acknowledge it.
+func GetIncidentAck(linkSigner *signer.Signer, escalationService escalation.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		incidentID, _, err := parseAckToken(linkSigner, token)
+		if err != nil {
+			http.Error(w, "Invalid incident link", http.StatusBadRequest)
+			return
+		}
+
+		incident, err := escalationService.GetIncident(r.Context(), escalation.GetIncidentReq{ID: incidentID})
+		if err != nil {
+			http.Error(w, "Incident not found", http.StatusNotFound)
+			return
+		}
+
+		c := Layout(IncidentAck(token, incidentToTransportAdapter(incident, time.UTC)), "Domainator | Incident")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/escalation"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func GetIncidents(escalationService escalation.Service, channelsService channels.Service, usersService users.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		policies, err := escalationService.GetPolicies(r.Context(), escalation.GetPoliciesReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting escalation policies", http.StatusInternalServerError)
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		incidents, err := escalationService.GetIncidents(r.Context(), escalation.GetIncidentsReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting incidents", http.StatusInternalServerError)
+			return
+		}
+
+		transportPolicies := make([]TransportPolicy, len(policies))
+		for i, p := range policies {
+			transportPolicies[i] = policyToTransportAdapter(p, chs)
+		}
+
+		loc := userLocation(r, usersService)
+		transportIncidents := make([]TransportIncident, len(incidents))
+		for i, incident := range incidents {
+			transportIncidents[i] = incidentToTransportAdapter(incident, loc)
+		}
+
+		c := Layout(Incidents(transportPolicies, transportIncidents), "Domainator | Incidents")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// certStatuses are the values of the status label of domainator_certs, always exported.
+var certStatuses = []string{"ok", "expiring", "expired", "error", "pending"}
+
+// GetMetrics serves the metrics of the registry in the Prometheus text format, with the certs
+// of every user counted by the database on each scrape. Only aggregates are exported,
+// nothing that identifies a user or their domains.
+// Requests need the token in the Authorization: Bearer header, an empty token rejects them all.
+func GetMetrics(logger *slog.Logger, registry *prometheus.Registry, certsService certs.Service, token string) http.HandlerFunc {
+	registry.MustRegister(newCertsCollector(certsService))
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorLog:      slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// certsCollector counts the certs by status and finds the oldest check when scraped.
+type certsCollector struct {
+	certsService certs.Service
+	total        *prometheus.Desc
+	oldestCheck  *prometheus.Desc
+}
+
+func newCertsCollector(certsService certs.Service) *certsCollector {
+	return &certsCollector{
+		certsService: certsService,
+		total: prometheus.NewDesc(
+			"domainator_certs",
+			"Number of certificates monitored by status: ok, expiring (in less than 3 days), expired, error (the last check failed) or pending (not checked yet).",
+			[]string{"status"}, nil,
+		),
+		oldestCheck: prometheus.NewDesc(
+			"domainator_certs_oldest_check_timestamp_seconds",
+			"Oldest last check of a certificate as a Unix timestamp, 0 when there are none.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *certsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.total
+	ch <- c.oldestCheck
+}
+
+func (c *certsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := c.certsService.Stats(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.total, err)
+		return
+	}
+
+	for _, status := range certStatuses {
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stats.ByStatus[status]), status)
+	}
+	ch <- prometheus.MustNewConstMetric(c.oldestCheck, prometheus.GaugeValue, unixOrZero(stats.OldestCheck))
+}
+
+func unixOrZero(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.Unix())
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGetMetricsUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+	}{
+		{"no_header", "secret", ""},
+		{"wrong_token", "secret", "Bearer nope"},
+		{"empty_token", "", "Bearer "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := GetMetrics(slog.Default(), prometheus.NewRegistry(), nil, tc.token)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/metrics", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			handler.ServeHTTP(w, r)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+// fakeStatsService returns the same stats, the methods the metrics don't use panic.
+type fakeStatsService struct {
+	certs.Service
+	stats certs.Stats
+}
+
+func (f fakeStatsService) Stats(_ context.Context) (certs.Stats, error) {
+	return f.stats, nil
+}
+
+func TestCertsCollector(t *testing.T) {
+	t.Parallel()
+
+	oldest := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service := fakeStatsService{stats: certs.Stats{
+		ByStatus:    map[string]int{"ok": 2, "expiring": 1, "error": 3},
+		OldestCheck: oldest,
+	}}
+
+	want := fmt.Sprintf(`
+# HELP domainator_certs Number of certificates monitored by status: ok, expiring (in less than 3 days), expired, error (the last check failed) or pending (not checked yet).
+# TYPE domainator_certs gauge
+domainator_certs{status="error"} 3
+domainator_certs{status="expired"} 0
+domainator_certs{status="expiring"} 1
+domainator_certs{status="ok"} 2
+domainator_certs{status="pending"} 0
+# HELP domainator_certs_oldest_check_timestamp_seconds Oldest last check of a certificate as a Unix timestamp, 0 when there are none.
+# TYPE domainator_certs_oldest_check_timestamp_seconds gauge
+domainator_certs_oldest_check_timestamp_seconds %d
+`, oldest.Unix())
+
+	err := testutil.CollectAndCompare(newCertsCollector(service), strings.NewReader(want))
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -3,12 +3,16 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/channels"
 	"github.com/germandv/domainator/internal/cntxt"
 	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/slackapp"
 	"github.com/germandv/domainator/internal/users"
+	"github.com/germandv/domainator/internal/webpush"
 )
 
-func GetSettings(userService users.Service) http.HandlerFunc {
+func GetSettings(userService users.Service, channelsService channels.Service, tokensService apitokens.Service, slackCfg *slackapp.Config, vapid *webpush.VAPID, appURL string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userIDstr := cntxt.GetUserID(r)
 		userID, err := common.ParseID(userIDstr)
@@ -23,7 +27,24 @@ func GetSettings(userService users.Service) http.HandlerFunc {
 			return
 		}
 
-		c := Layout(Settings(u.WebhookURL.String()), "Domainator | Settings")
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		tokens, err := tokensService.GetAll(r.Context(), apitokens.GetAllReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error getting access tokens", http.StatusInternalServerError)
+			return
+		}
+
+		vapidKey := ""
+		if vapid.Enabled() {
+			vapidKey = vapid.PublicKey
+		}
+
+		c := Layout(Settings(userToSettingsAdapter(u, chs, slackCfg.Enabled(), vapidKey, appURL, tokens)), "Domainator | Settings")
 		SendTempl(w, r, c)
 	}
 }
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/slackapp"
+)
+
+// ListSlackChannels renders the Slack channels a Slack app channel can post to, as options of the rule form.
+func ListSlackChannels(logger *slog.Logger, channelsService channels.Service, slackCfg *slackapp.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ChannelReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		channel, err := channelsService.Get(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Channel not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "Error getting channel", http.StatusInternalServerError)
+			return
+		}
+
+		if channel.Kind != channels.KindSlackApp {
+			http.Error(w, "Not a Slack app channel", http.StatusBadRequest)
+			return
+		}
+
+		conversations, err := slackapp.ListChannels(r.Context(), slackCfg.APIURL, channel.Secret)
+		if err != nil {
+			// The rule can still post to the default channel.
+			logger.WarnContext(r.Context(), "error listing Slack channels", "err", err.Error(), "channel", channel.ID)
+			conversations = nil
+		}
+
+		options := make([]TransportOption, len(conversations))
+		for i, c := range conversations {
+			options[i] = TransportOption{Value: c.ID + "|" + c.Name, Label: "#" + c.Name}
+		}
+
+		SendTempl(w, r, SlackChannelOptions(options))
+	}
+}
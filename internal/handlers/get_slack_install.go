@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/cookies"
+	"github.com/germandv/domainator/internal/slackapp"
+)
+
+const slackStateCookieName = "the_slack_state_str"
+
+func SlackInstall(logger *slog.Logger, slackCfg *slackapp.Config, cookieSigningSecret []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !slackCfg.Enabled() {
+			http.Error(w, slackapp.ErrNotConfigured.Error(), http.StatusNotFound)
+			return
+		}
+
+		state := common.GenerateRandomString(32)
+
+		err := cookies.WriteSigned(w, http.Cookie{
+			Name:     slackStateCookieName,
+			Value:    state,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   true,
+			Expires:  time.Now().Add(5 * time.Minute),
+		}, cookieSigningSecret)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error writing signed cookie", "err", err.Error())
+			http.Error(w, "something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "redirecting to Slack to install the app", "user", cntxt.GetUserID(r))
+		http.Redirect(w, r, slackCfg.AuthCodeURL(state), http.StatusTemporaryRedirect)
+	}
+}
+
+func SlackCallback(
+	logger *slog.Logger,
+	slackCfg *slackapp.Config,
+	channelsService channels.Service,
+	cookieSigningSecret []byte,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		state := r.FormValue("state")
+		stateCookie, err := cookies.ReadSigned(r, slackStateCookieName, cookieSigningSecret)
+		if err != nil || state != stateCookie {
+			logger.ErrorContext(r.Context(), "error reading signed cookie or comparing state", "err", err, "state", state, "stateCookie", stateCookie)
+			http.Error(w, "Invalid or missing state", http.StatusUnauthorized)
+			return
+		}
+
+		// The user cancelled the installation.
+		if r.FormValue("error") != "" {
+			http.Redirect(w, r, "/settings", http.StatusSeeOther)
+			return
+		}
+
+		installation, err := slackCfg.Exchange(r.Context(), r.FormValue("code"))
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error in Slack exchange", "err", err.Error())
+			http.Error(w, "something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		channel, err := channelsService.InstallSlack(r.Context(), channels.InstallSlackReq{
+			UserID:      userID,
+			TeamID:      installation.TeamID,
+			TeamName:    installation.TeamName,
+			SlackUserID: installation.SlackUserID,
+			BotUserID:   installation.BotUserID,
+			BotToken:    installation.BotToken,
+			ChannelID:   installation.ChannelID,
+			ChannelName: installation.ChannelName,
+		})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error saving Slack installation", "err", err.Error(), "team", installation.TeamID, "user", userID)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "installed Slack app", "channel", channel.ID, "team", installation.TeamID, "user", userID)
+		http.Redirect(w, r, "/settings", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// GetSlackLink asks the signed in user for confirmation before linking the Slack user the token was issued for.
+func GetSlackLink(linkSigner *signer.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		_, _, err := parseSlackLinkToken(linkSigner, token)
+		if err != nil {
+			http.Error(w, "Invalid or expired link, run the command in Slack again", http.StatusBadRequest)
+			return
+		}
+
+		c := Layout(SlackLinkConfirm(token), "Domainator | Link Slack")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// GetUnsubscribe asks for confirmation before unsubscribing,
+// so that link scanners prefetching the URL don't unsubscribe the user.
+func GetUnsubscribe(linkSigner *signer.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		_, err := linkSigner.Verify(notifier.UnsubscribePurpose, token)
+		if err != nil {
+			http.Error(w, "Invalid unsubscribe link", http.StatusBadRequest)
+			return
+		}
+
+		c := Layout(UnsubscribeConfirm(token), "Domainator | Unsubscribe")
+		SendTempl(w, r, c)
+	}
+}
@@ -40,7 +40,7 @@ func Landing() templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</div><div class=\"heavy-border\"><img src=\"static/images/dashboard.png\" alt=\"Dashboard\"></div></div><div class=\"divider\"></div><div class=\"features\"><h2 class=\"bold\">Key Features</h2><div class=\"row\"><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"48px\" height=\"47px\" viewBox=\"0 0 47 47\" version=\"1.1\"><g id=\"surface1\"><path style=\" stroke:none;fill-rule:evenodd;fill:rgb(14.117647%,16.078431%,18.431373%);fill-opacity:1;\" d=\"M 23.429688 0 C 10.472656 0 0 10.769531 0 24.097656 C 0 34.746094 6.710938 43.761719 16.019531 46.953125 C 17.183594 47.195312 17.609375 46.4375 17.609375 45.796875 C 17.609375 45.238281 17.574219 43.324219 17.574219 41.328125 C 11.054688 42.765625 9.699219 38.457031 9.699219 38.457031 C 8.652344 35.664062 7.097656 34.945312 7.097656 34.945312 C 4.964844 33.472656 7.253906 33.472656 7.253906 33.472656 C 9.621094 33.628906 10.863281 35.945312 10.863281 35.945312 C 12.957031 39.613281 16.332031 38.578125 17.6875 37.9375 C 17.882812 36.382812 18.503906 35.304688 19.164062 34.707031 C 13.964844 34.148438 8.496094 32.074219 8.496094 22.820312 C 8.496094 20.1875 9.425781 18.03125 10.898438 16.355469 C 10.667969 15.757812 9.851562 13.285156 11.132812 9.972656 C 11.132812 9.972656 13.113281 9.335938 17.570312 12.445312 C 19.480469 11.917969 21.453125 11.652344 23.429688 11.648438 C 25.410156 11.648438 27.425781 11.929688 29.289062 12.445312 C 33.75 9.335938 35.726562 9.972656 35.726562 9.972656 C 37.007812 13.285156 36.191406 15.757812 35.960938 16.355469 C 37.472656 18.03125 38.363281 20.1875 38.363281 22.820312 C 38.363281 32.074219 32.894531 34.109375 27.65625 34.707031 C 28.511719 35.464844 29.25 36.902344 29.25 39.175781 C 29.25 42.40625 29.210938 45 29.210938 45.796875 C 29.210938 46.4375 29.636719 47.195312 30.800781 46.953125 C 40.109375 43.761719 46.820312 34.746094 46.820312 24.097656 C 46.859375 10.769531 36.347656 0 23.429688 0 Z M 23.429688 0 \"></path></g></svg><h3>GitHub OAuth</h3><p>Sign in with your GitHub account for a seamless onboarding experience.</p></div><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"48px\" height=\"48px\" viewBox=\"0 0 48 48\" version=\"1.1\"><g id=\"surface1\"><path style=\" stroke:none;fill-rule:nonzero;fill:rgb(0%,0%,0%);fill-opacity:1;\" d=\"M 10.085938 30.328125 C 10.078125 33.113281 7.828125 35.367188 5.042969 35.375 C 2.261719 35.371094 0.00390625 33.113281 0 30.328125 C 0.0078125 27.546875 2.261719 25.296875 5.042969 25.289062 L 10.085938 25.289062 Z M 12.625 30.328125 C 12.632812 27.550781 14.886719 25.296875 17.667969 25.289062 C 20.449219 25.296875 22.703125 27.550781 22.710938 30.328125 L 22.710938 42.957031 C 22.703125 45.738281 20.449219 47.992188 17.667969 48 C 14.886719 47.992188 12.632812 45.738281 12.625 42.957031 Z M 17.667969 10.085938 C 14.886719 10.078125 12.636719 7.824219 12.625 5.042969 C 12.632812 2.261719 14.886719 0.0078125 17.667969 0 C 20.449219 0.0078125 22.703125 2.261719 22.710938 5.042969 L 22.710938 10.085938 Z M 17.667969 12.625 C 20.449219 12.632812 22.703125 14.886719 22.710938 17.667969 C 22.703125 20.449219 20.449219 22.703125 17.667969 22.710938 L 5.042969 22.710938 C 2.261719 22.703125 0.0078125 20.449219 0 17.667969 C 0.0078125 14.886719 2.261719 12.632812 5.042969 12.625 Z M 37.910156 17.667969 C 37.917969 14.886719 40.171875 12.632812 42.957031 12.625 C 45.738281 12.632812 47.992188 14.886719 48 17.667969 C 47.992188 20.449219 45.738281 22.703125 42.957031 22.710938 L 37.910156 22.710938 Z M 35.375 17.667969 C 35.367188 20.449219 33.113281 22.703125 30.328125 22.710938 C 27.550781 22.703125 25.296875 20.449219 25.289062 17.667969 L 25.289062 5.042969 C 25.296875 2.261719 27.546875 0.0078125 30.328125 0 C 33.113281 0.00390625 35.371094 2.261719 35.375 5.042969 Z M 30.328125 37.910156 C 33.113281 37.917969 35.371094 40.171875 35.375 42.957031 C 35.371094 45.738281 33.113281 47.996094 30.328125 48 C 27.546875 47.992188 25.296875 45.738281 25.289062 42.957031 L 25.289062 37.910156 Z M 30.328125 35.375 C 27.546875 35.367188 25.292969 33.113281 25.289062 30.328125 C 25.296875 27.550781 27.550781 25.296875 30.328125 25.289062 L 42.957031 25.289062 C 45.738281 25.296875 47.992188 27.546875 48 30.328125 C 47.996094 33.113281 45.738281 35.371094 42.957031 35.375 Z M 30.328125 35.375 \"></path></g></svg><h3>Slack Notifications</h3><p>Get notified when your certificate is about to expire, directly in your Slack workspace.</p></div><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"48\" height=\"48\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"#000000\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><line x1=\"12\" y1=\"1\" x2=\"12\" y2=\"23\"></line><path d=\"M17 5H9.5a3.5 3.5 0 0 0 0 7h5a3.5 3.5 0 0 1 0 7H6\"></path></svg><h3>Free For 10 Domains</h3><p>Track up to ten domains for free! You can support the project with donations.</p></div></div></div>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</div><div class=\"heavy-border\"><img src=\"static/images/dashboard.png\" alt=\"Dashboard\"></div></div><div class=\"divider\"></div><div class=\"features\"><h2 class=\"bold\">Key Features</h2><div class=\"row\"><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"48px\" height=\"47px\" viewBox=\"0 0 47 47\" version=\"1.1\"><g id=\"surface1\"><path style=\" stroke:none;fill-rule:evenodd;fill:rgb(14.117647%,16.078431%,18.431373%);fill-opacity:1;\" d=\"M 23.429688 0 C 10.472656 0 0 10.769531 0 24.097656 C 0 34.746094 6.710938 43.761719 16.019531 46.953125 C 17.183594 47.195312 17.609375 46.4375 17.609375 45.796875 C 17.609375 45.238281 17.574219 43.324219 17.574219 41.328125 C 11.054688 42.765625 9.699219 38.457031 9.699219 38.457031 C 8.652344 35.664062 7.097656 34.945312 7.097656 34.945312 C 4.964844 33.472656 7.253906 33.472656 7.253906 33.472656 C 9.621094 33.628906 10.863281 35.945312 10.863281 35.945312 C 12.957031 39.613281 16.332031 38.578125 17.6875 37.9375 C 17.882812 36.382812 18.503906 35.304688 19.164062 34.707031 C 13.964844 34.148438 8.496094 32.074219 8.496094 22.820312 C 8.496094 20.1875 9.425781 18.03125 10.898438 16.355469 C 10.667969 15.757812 9.851562 13.285156 11.132812 9.972656 C 11.132812 9.972656 13.113281 9.335938 17.570312 12.445312 C 19.480469 11.917969 21.453125 11.652344 23.429688 11.648438 C 25.410156 11.648438 27.425781 11.929688 29.289062 12.445312 C 33.75 9.335938 35.726562 9.972656 35.726562 9.972656 C 37.007812 13.285156 36.191406 15.757812 35.960938 16.355469 C 37.472656 18.03125 38.363281 20.1875 38.363281 22.820312 C 38.363281 32.074219 32.894531 34.109375 27.65625 34.707031 C 28.511719 35.464844 29.25 36.902344 29.25 39.175781 C 29.25 42.40625 29.210938 45 29.210938 45.796875 C 29.210938 46.4375 29.636719 47.195312 30.800781 46.953125 C 40.109375 43.761719 46.820312 34.746094 46.820312 24.097656 C 46.859375 10.769531 36.347656 0 23.429688 0 Z M 23.429688 0 \"></path></g></svg><h3>GitHub OAuth</h3><p>Sign in with your GitHub account for a seamless onboarding experience.</p></div><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"48px\" height=\"48px\" viewBox=\"0 0 48 48\" version=\"1.1\"><g id=\"surface1\"><path style=\" stroke:none;fill-rule:nonzero;fill:rgb(0%,0%,0%);fill-opacity:1;\" d=\"M 10.085938 30.328125 C 10.078125 33.113281 7.828125 35.367188 5.042969 35.375 C 2.261719 35.371094 0.00390625 33.113281 0 30.328125 C 0.0078125 27.546875 2.261719 25.296875 5.042969 25.289062 L 10.085938 25.289062 Z M 12.625 30.328125 C 12.632812 27.550781 14.886719 25.296875 17.667969 25.289062 C 20.449219 25.296875 22.703125 27.550781 22.710938 30.328125 L 22.710938 42.957031 C 22.703125 45.738281 20.449219 47.992188 17.667969 48 C 14.886719 47.992188 12.632812 45.738281 12.625 42.957031 Z M 17.667969 10.085938 C 14.886719 10.078125 12.636719 7.824219 12.625 5.042969 C 12.632812 2.261719 14.886719 0.0078125 17.667969 0 C 20.449219 0.0078125 22.703125 2.261719 22.710938 5.042969 L 22.710938 10.085938 Z M 17.667969 12.625 C 20.449219 12.632812 22.703125 14.886719 22.710938 17.667969 C 22.703125 20.449219 20.449219 22.703125 17.667969 22.710938 L 5.042969 22.710938 C 2.261719 22.703125 0.0078125 20.449219 0 17.667969 C 0.0078125 14.886719 2.261719 12.632812 5.042969 12.625 Z M 37.910156 17.667969 C 37.917969 14.886719 40.171875 12.632812 42.957031 12.625 C 45.738281 12.632812 47.992188 14.886719 48 17.667969 C 47.992188 20.449219 45.738281 22.703125 42.957031 22.710938 L 37.910156 22.710938 Z M 35.375 17.667969 C 35.367188 20.449219 33.113281 22.703125 30.328125 22.710938 C 27.550781 22.703125 25.296875 20.449219 25.289062 17.667969 L 25.289062 5.042969 C 25.296875 2.261719 27.546875 0.0078125 30.328125 0 C 33.113281 0.00390625 35.371094 2.261719 35.375 5.042969 Z M 30.328125 37.910156 C 33.113281 37.917969 35.371094 40.171875 35.375 42.957031 C 35.371094 45.738281 33.113281 47.996094 30.328125 48 C 27.546875 47.992188 25.296875 45.738281 25.289062 42.957031 L 25.289062 37.910156 Z M 30.328125 35.375 C 27.546875 35.367188 25.292969 33.113281 25.289062 30.328125 C 25.296875 27.550781 27.550781 25.296875 30.328125 25.289062 L 42.957031 25.289062 C 45.738281 25.296875 47.992188 27.546875 48 30.328125 C 47.996094 33.113281 45.738281 35.371094 42.957031 35.375 Z M 30.328125 35.375 \"></path></g></svg><h3>Chat &amp; Email Notifications</h3><p>Get notified when your certificate is about to expire, directly in Slack, Discord, Microsoft Teams, Mattermost or your inbox.</p></div><div class=\"card\"><svg xmlns=\"http://www.w3.org/2000/svg\" width=\"48\" height=\"48\" viewBox=\"0 0 24 24\" fill=\"none\" stroke=\"#000000\" stroke-width=\"2\" stroke-linecap=\"round\" stroke-linejoin=\"round\"><line x1=\"12\" y1=\"1\" x2=\"12\" y2=\"23\"></line><path d=\"M17 5H9.5a3.5 3.5 0 0 0 0 7h5a3.5 3.5 0 0 1 0 7H6\"></path></svg><h3>Free For 10 Domains</h3><p>Track up to ten domains for free! You can support the project with donations.</p></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
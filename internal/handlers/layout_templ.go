@@ -38,7 +38,7 @@ func head(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</title><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0 \"><script src=\"static/scripts/htmx.min.js\"></script><script src=\"static/scripts/response-targets.js\"></script><script src=\"static/scripts/main.js\" defer></script><link rel=\"stylesheet\" href=\"static/styles/main.css\"><link rel=\"icon\" type=\"image/png\" href=\"static/images/favicon.png\"></head>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</title><meta charset=\"UTF-8\"><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0 \"><script src=\"/static/scripts/htmx.min.js\"></script><script src=\"/static/scripts/response-targets.js\"></script><script src=\"/static/scripts/sse.js\"></script><script src=\"/static/scripts/main.js\" defer></script><link rel=\"stylesheet\" href=\"/static/styles/main.css\"><link rel=\"icon\" type=\"image/png\" href=\"/static/images/favicon.png\"></head>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -85,7 +85,7 @@ func header() templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</button><ul><li><a href=\"/dashboard\">Dashboard</a></li><li><a href=\"/settings\">Settings</a></li><li><form action=\"/logout\" method=\"POST\"><button class=\"text\" type=\"submit\">Log Out</button></form></li></ul></nav></header>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</button><ul><li><a href=\"/dashboard\">Dashboard</a></li><li><a href=\"/incidents\">Incidents</a></li><li><a href=\"/settings\">Settings</a></li><li><form action=\"/logout\" method=\"POST\"><button class=\"text\" type=\"submit\">Log Out</button></form></li></ul></nav></header>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
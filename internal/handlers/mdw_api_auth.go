@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+// APIAuthMdwBuilder returns a middleware that checks the personal access token in the Authorization header
+// of requests to the API, and if it allows the scope, adds the user ID to the request context.
+//
+// Unlike AuthMdwBuilder it never redirects: requests without a valid token get a 401
+// and those with a token that lacks the scope a 403.
+func APIAuthMdwBuilder(tokensService apitokens.Service, scope apitokens.Scope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="domainator"`)
+				sendAPIError(w, http.StatusUnauthorized, "missing access token")
+				return
+			}
+
+			token, err := tokensService.Authenticate(r.Context(), apitokens.AuthenticateReq{
+				Token: strings.TrimSpace(secret),
+				Scope: scope,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, apitokens.ErrInvalidToken):
+					w.Header().Set("WWW-Authenticate", `Bearer realm="domainator", error="invalid_token"`)
+					sendAPIError(w, http.StatusUnauthorized, err.Error())
+				case errors.Is(err, apitokens.ErrInvalidScope):
+					w.Header().Set("WWW-Authenticate", `Bearer realm="domainator", error="insufficient_scope", scope="`+scope.String()+`"`)
+					sendAPIError(w, http.StatusForbidden, "the access token doesn't have the "+scope.String()+" scope")
+				default:
+					sendAPIError(w, http.StatusInternalServerError, "error checking access token")
+				}
+				return
+			}
+
+			r = cntxt.SetUserID(r, token.UserID.String())
+			next.ServeHTTP(w, r)
+		})
+	}
+}
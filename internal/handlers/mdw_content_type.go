@@ -12,7 +12,7 @@ func contentType(next http.Handler) http.Handler {
 			return
 		}
 
-		if r.URL.Path == "/healthcheck" {
+		if r.URL.Path == "/healthcheck" || strings.HasPrefix(r.URL.Path, "/api/") {
 			w.Header().Set("Content-Type", "application/json; charset=utf-8")
 			next.ServeHTTP(w, r)
 			return
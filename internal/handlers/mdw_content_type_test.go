@@ -51,3 +51,20 @@ func TestContentTypeDefault(t *testing.T) {
 		t.Errorf("Want Content-Type %q, got %q", want, got)
 	}
 }
+
+func TestContentTypeAPI(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/domains", nil)
+
+	contentType(handler).ServeHTTP(w, r)
+
+	want := "application/json; charset=utf-8"
+	got := w.Header().Get("Content-Type")
+
+	if got != want {
+		t.Errorf("Want Content-Type %q, got %q", want, got)
+	}
+}
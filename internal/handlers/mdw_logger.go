@@ -8,7 +8,7 @@ import (
 func loggerBuilder(logger *slog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			logger.Info(
+			logger.InfoContext(r.Context(),
 				"Serving Request",
 				"method", r.Method,
 				"path", r.URL.Path,
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetricsMdw(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /domain/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := MetricsMdwBuilder(registry)(mux)
+
+	for _, path := range []string{"/domain/1", "/domain/2", "/unknown"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", path, nil)
+		handler.ServeHTTP(w, r)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetName() != "domainator_http_request_duration_seconds" {
+		t.Fatalf("Expected only the duration histogram, got %v", families)
+	}
+
+	counts := map[string]uint64{}
+	for _, m := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		counts[labels["method"]+" "+labels["route"]+" "+labels["code"]] = m.GetHistogram().GetSampleCount()
+	}
+
+	want := map[string]uint64{
+		"GET GET /domain/{id} 202": 2,
+		"GET unmatched 404":        1,
+	}
+	for series, count := range want {
+		if counts[series] != count {
+			t.Errorf("Expected %d requests for %q, got %d (%v)", count, series, counts[series], counts)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/germandv/domainator/internal/openapi"
+)
+
+// OpenAPIMdwBuilder returns a middleware that validates the requests to the API and the responses
+// of its handlers against the OpenAPI document, meant for dev and test so they can't drift.
+//
+// Requests that don't match get a 400 without reaching the handler. Responses that don't match,
+// and responses of operations missing from the document, are logged and replaced by a 500.
+func OpenAPIMdwBuilder(logger *slog.Logger, doc *openapi.Document) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, doc.BasePath()+"/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			op, found := doc.Find(r.Method, r.URL.Path)
+			if found {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxAPIBody+1))
+				if err != nil {
+					sendAPIError(w, http.StatusBadRequest, "error reading body")
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+
+				err = doc.ValidateRequest(op, r.Header.Get("Content-Type"), body)
+				if err != nil {
+					sendAPIError(w, http.StatusBadRequest, "request doesn't match the OpenAPI spec: "+err.Error())
+					return
+				}
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(bw, r)
+
+			switch {
+			case found:
+				err := doc.ValidateResponse(op, bw.status, w.Header().Get("Content-Type"), bw.body.Bytes())
+				if err != nil {
+					logger.ErrorContext(r.Context(), "response doesn't match the OpenAPI spec", "err", err.Error(), "method", r.Method, "path", r.URL.Path, "status", bw.status)
+					w.Header().Del("Location")
+					sendAPIError(w, http.StatusInternalServerError, "response doesn't match the OpenAPI spec: "+err.Error())
+					return
+				}
+			case bw.status != http.StatusNotFound && bw.status != http.StatusMethodNotAllowed:
+				logger.ErrorContext(r.Context(), "operation missing from the OpenAPI spec", "method", r.Method, "path", r.URL.Path, "status", bw.status)
+				sendAPIError(w, http.StatusInternalServerError, "operation missing from the OpenAPI spec")
+				return
+			}
+
+			w.WriteHeader(bw.status)
+			_, _ = w.Write(bw.body.Bytes())
+		})
+	}
+}
+
+// bufferedWriter holds the status code and body of a response, so they can be checked before sending it.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (bw *bufferedWriter) WriteHeader(status int) {
+	if bw.wroteHeader {
+		return
+	}
+	bw.status = status
+	bw.wroteHeader = true
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	bw.wroteHeader = true
+	return bw.body.Write(b)
+}
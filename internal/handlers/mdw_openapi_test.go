@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/openapi"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func TestOpenAPIMdw(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		handler http.HandlerFunc
+		want    int
+	}{
+		{
+			name:   "valid",
+			method: "POST",
+			path:   "/api/v1/domains",
+			body:   `{"domain": "example.com"}`,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				sendAPIError(w, http.StatusBadRequest, "invalid domain")
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid_request",
+			method: "POST",
+			path:   "/api/v1/domains",
+			body:   `{"domain": "example.com", "unknown": true}`,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Handler should not be called with an invalid request")
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid_response",
+			method: "GET",
+			path:   "/api/v1/domains",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				sendJSON(w, http.StatusOK, map[string]any{"domains": nil})
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:   "undocumented_status",
+			method: "GET",
+			path:   "/api/v1/domains",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				sendAPIError(w, http.StatusTeapot, "teapot")
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:   "undocumented_operation",
+			method: "GET",
+			path:   "/api/v1/undocumented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				sendJSON(w, http.StatusOK, map[string]any{})
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:   "not_found",
+			method: "GET",
+			path:   "/api/v1/unknown",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name:   "outside_api",
+			method: "GET",
+			path:   "/dashboard",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			},
+			want: http.StatusTeapot,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			OpenAPIMdwBuilder(logger, doc)(tc.handler).ServeHTTP(w, r)
+
+			if w.Code != tc.want {
+				t.Errorf("Expected status code %d, got %d: %s", tc.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain, _ := certs.ParseDomain("example.com")
+	issuer, _ := certs.ParseIssuer("R3")
+	tags, _ := certs.ParseTags("prod,web")
+	cert := certs.New(common.NewID(), domain, issuer, time.Now().Add(10*24*time.Hour), tags)
+	pending := certs.New(common.NewID(), domain, issuer, time.Time{}, nil)
+
+	email, _ := users.ParseEmail("user@example.com")
+	u := users.New("User", email, "github", "1", "")
+	ch := channels.New(u.ID, channels.KindEmail, "Email", "user@example.com", "")
+
+	tests := []struct {
+		name   string
+		schema string
+		value  any
+	}{
+		{"domain", "Domain", certToAPIAdapter(cert, time.Now())},
+		{"pending_domain", "Domain", certToAPIAdapter(pending, time.Now())},
+		{"domains", "Domains", APIDomains{Domains: []APIDomain{}}},
+		{"settings", "Settings", userToAPISettingsAdapter(u, []channels.Channel{ch})},
+		{"settings_without_channels", "Settings", userToAPISettingsAdapter(u, nil)},
+		{"error", "Error", APIError{Error: "error"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v any
+			err = json.Unmarshal(b, &v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = doc.Validate(doc.Components.Schemas[tc.schema], v)
+			if err != nil {
+				t.Errorf("Expected %s to match the %s schema, got %s", b, tc.schema, err)
+			}
+		})
+	}
+}
@@ -18,22 +18,22 @@ func rateLimiterBuilder(
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			key := getKey(r.RemoteAddr)
 
-			current, err := cacheClient.Increment(key)
+			current, err := cacheClient.Increment(r.Context(), key)
 			if err != nil {
-				logger.Error("rate limiter error incrementing counter", "key", key, "err", err)
+				logger.ErrorContext(r.Context(), "rate limiter error incrementing counter", "key", key, "err", err)
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 
 			if current > reqsPerMin {
-				logger.Info("too many requests", "ip", r.RemoteAddr, "count", current)
+				logger.InfoContext(r.Context(), "too many requests", "ip", r.RemoteAddr, "count", current)
 				http.Error(w, "Too many requests", http.StatusTooManyRequests)
 				return
 			} else if current == 1 {
 				// First request, set expiration to 1 minute
-				err = cacheClient.Expire(key, time.Minute)
+				err = cacheClient.Expire(r.Context(), key, time.Minute)
 				if err != nil {
-					logger.Error("rate limiter error setting expiration", "key", key, "err", err)
+					logger.ErrorContext(r.Context(), "rate limiter error setting expiration", "key", key, "err", err)
 					http.Error(w, err.Error(), http.StatusInternalServerError)
 					return
 				}
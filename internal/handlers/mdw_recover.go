@@ -10,7 +10,7 @@ func recoverBuilder(logger *slog.Logger) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					logger.Error("recovered from panic", "err", err)
+					logger.ErrorContext(r.Context(), "recovered from panic", "err", err)
 					w.WriteHeader(http.StatusInternalServerError)
 				}
 			}()
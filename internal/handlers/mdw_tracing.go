@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID is what's accepted as the ID of a request from a proxy in front of the app.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// TracingMdwBuilder returns a middleware that starts a span for every request, child of the one
+// in the traceparent header if any, and gives the request an ID, sent back in X-Request-ID.
+// It has to be the outermost middleware, so the span covers the others and the logs they write.
+func TracingMdwBuilder() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method, trace.SpanKindServer,
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			)
+			defer span.End()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			r = cntxt.SetRequestID(r.WithContext(ctx), requestID)
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			if r.Pattern != "" {
+				span.SetName(r.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(sw.status))
+			if sw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(sw.status))
+			}
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func TestTracingMdw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		incoming string
+		kept     bool
+	}{
+		{"generated", "", false},
+		{"from_proxy", "abc-123", true},
+		{"invalid", "no spaces allowed", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			handler := TracingMdwBuilder()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = cntxt.GetRequestID(r)
+			}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			if tc.incoming != "" {
+				r.Header.Set(RequestIDHeader, tc.incoming)
+			}
+			handler.ServeHTTP(w, r)
+
+			got := w.Header().Get(RequestIDHeader)
+			if got == "" {
+				t.Fatal("Expected a request ID header, got none")
+			}
+			if got != seen {
+				t.Errorf("Expected the handler to see request ID %q, got %q", got, seen)
+			}
+			if tc.kept && got != tc.incoming {
+				t.Errorf("Expected request ID %q, got %q", tc.incoming, got)
+			}
+			if !tc.kept && got == tc.incoming {
+				t.Errorf("Expected a new request ID, got %q", got)
+			}
+		})
+	}
+}
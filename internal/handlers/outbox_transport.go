@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/outbox"
+)
+
+type RetryMessageReq struct {
+	ID     string
+	UserID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r RetryMessageReq) Parse() (outbox.RetryReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return outbox.RetryReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return outbox.RetryReq{}, err
+	}
+
+	return outbox.RetryReq{
+		ID:     id,
+		UserID: userID,
+	}, nil
+}
+
+// TransportDelivery represents a Delivery in the Transport layer.
+type TransportDelivery struct {
+	ID          string
+	MessageID   string
+	ChannelName string
+	Domain      string
+	State       string
+	Dead        bool
+	Attempt     int
+	OK          bool
+	StatusCode  string
+	Response    string
+	Error       string
+	CreatedAt   string
+}
+
+// deliveryToTransportAdapter transforms a Delivery from the Service layer to the Transport layer.
+func deliveryToTransportAdapter(d outbox.Delivery) TransportDelivery {
+	statusCode := "-"
+	if d.StatusCode != 0 {
+		statusCode = strconv.Itoa(d.StatusCode)
+	}
+
+	return TransportDelivery{
+		ID:          d.ID.String(),
+		MessageID:   d.MessageID.String(),
+		ChannelName: d.ChannelName,
+		Domain:      d.Domain,
+		State:       string(d.State),
+		Dead:        d.State == outbox.StateDead,
+		Attempt:     d.Attempt,
+		OK:          d.OK,
+		StatusCode:  statusCode,
+		Response:    d.Response,
+		Error:       d.Error,
+		CreatedAt:   d.CreatedAt.Format(time.DateTime),
+	}
+}
+
+func maxAttempts() int {
+	return outbox.MaxAttempts
+}
+
+func maxFailures() int {
+	return channels.MaxFailures
+}
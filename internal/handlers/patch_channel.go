@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// SendChannelTest sends a test message through the channel.
+func SendChannelTest(logger *slog.Logger, channelsService channels.Service, notifiers channels.Notifiers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := ChannelReq{ID: r.PathValue("id"), UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		channel, err := channelsService.Get(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Channel not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error getting channel", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		err = sendTestMessage(notifiers, channel, userID)
+		if errors.Is(err, channels.ErrUnconfirmed) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.ErrorContext(r.Context(), "Failed to send test message", "error", err, "user", userID, "channel", channel.ID.String(), "kind", channel.Kind)
+			http.Error(w, "Error sending test message", http.StatusInternalServerError)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "Test message sent", "user", userID, "channel", channel.ID.String(), "kind", channel.Kind)
+		c := MessageSent()
+		SendTempl(w, r, c)
+	}
+}
+
+// sendTestMessage sends a test message through the channel,
+// incidents are opened and resolved right away. Nothing is sent to unconfirmed email addresses.
+func sendTestMessage(notifiers channels.Notifiers, channel channels.Channel, userID string) error {
+	if !channel.Confirmed {
+		return channels.ErrUnconfirmed
+	}
+
+	notification := notifier.Notification{
+		ID:        "test-" + channel.ID.String(),
+		UserID:    userID,
+		Domain:    "This is a Test Message",
+		Status:    "OK",
+		Hours:     0,
+		Issuer:    "Domainator",
+		ExpiresAt: time.Now(),
+	}
+
+	incident := channel.Kind == channels.KindPagerDuty || channel.Kind == channels.KindOpsgenie
+	if incident {
+		notification.Status = "expires soon"
+	}
+
+	err := notifiers.Send(channel, notification)
+	if err == nil && incident {
+		notification.Status = notifier.StatusResolved
+		err = notifiers.Send(channel, notification)
+	}
+
+	return err
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// SetDomainTags replaces the tags of a domain with the ones entered in the htmx prompt.
+func SetDomainTags(logger *slog.Logger, certsService certs.Service, usersService users.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "No ID provided", http.StatusBadRequest)
+			return
+		}
+
+		req := SetCertTagsReq{ID: id, UserID: userID, Tags: r.Header.Get("HX-Prompt")}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cert, err := certsService.SetTags(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, certs.ErrNotFound) {
+				http.Error(w, "Domain not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error updating tags", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "updated domain tags", "domain", cert.Domain.String(), "user", userID)
+		loc := userLocation(r, usersService)
+		c := CertRow(serviceToTransportAdapter(cert, loc))
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func APITestChannel(logger *slog.Logger, channelsService channels.Service, notifiers channels.Notifiers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+		id := r.PathValue("id")
+
+		req := ChannelReq{ID: id, UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		channel, err := channelsService.Get(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				sendAPIError(w, http.StatusNotFound, "channel not found")
+			} else {
+				logger.Error("error getting channel", "err", err.Error(), "channel", id, "user", userID)
+				sendAPIError(w, http.StatusInternalServerError, "error getting channel")
+			}
+			return
+		}
+
+		err = sendTestMessage(notifiers, channel, userID)
+		if errors.Is(err, channels.ErrUnconfirmed) {
+			sendAPIError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to send test message", "error", err, "user", userID, "channel", channel.ID.String(), "kind", channel.Kind)
+			sendAPIError(w, http.StatusBadGateway, "error sending test message: "+err.Error())
+			return
+		}
+
+		logger.Info("Test message sent from the API", "user", userID, "channel", channel.ID.String(), "kind", channel.Kind)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func APICreateDomain(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := APICreateDomainReq{}
+		err := decodeJSON(w, r, &req)
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+			return
+		}
+
+		req.UserID = userID
+		parsedReq, err := req.Parse()
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cert, err := certsService.Save(r.Context(), parsedReq)
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		logger.InfoContext(r.Context(), "registered new domain from the API", "domain", cert.Domain.String(), "user", userID)
+		w.Header().Set("Location", "/api/v1/domains/"+cert.ID.String())
+		sendJSON(w, http.StatusCreated, certToAPIAdapter(cert, time.Now()))
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+// APIRefreshDomain queues a check of the certificate of the domain and returns it as it is,
+// checking is true until the result is stored.
+func APIRefreshDomain(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := UpdateCertReq{UserID: userID, ID: r.PathValue("id")}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			sendAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cert, err := certsService.Update(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, certs.ErrNotFound) {
+				sendAPIError(w, http.StatusNotFound, "domain not found")
+			} else {
+				logger.ErrorContext(r.Context(), "error refreshing domain", "err", err.Error(), "user", userID)
+				sendAPIError(w, http.StatusInternalServerError, "error refreshing domain")
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "queued a check of domain from the API", "domain", cert.Domain.String(), "user", userID)
+		sendJSON(w, http.StatusAccepted, certToAPIAdapter(cert, time.Now()))
+	}
+}
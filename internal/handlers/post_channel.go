@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+// EmailConfirmer sends the link that confirms the address of an email channel.
+type EmailConfirmer interface {
+	SendConfirmation(to string, userID string, channelID string) error
+}
+
+// CreateChannel saves the channel, email channels are sent the link that confirms their address.
+func CreateChannel(logger *slog.Logger, channelsService channels.Service, confirmer EmailConfirmer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := CreateChannelReq{
+			UserID:     userID,
+			Kind:       r.FormValue("kind"),
+			Name:       r.FormValue("name"),
+			Target:     r.FormValue("target"),
+			Credential: r.FormValue("credential"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		channel, err := channelsService.Create(r.Context(), parsedReq)
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "created notification channel", "channel", channel.ID.String(), "kind", channel.Kind, "user", userID)
+		if !channel.Confirmed {
+			// The card offers to send it again.
+			err = confirmer.SendConfirmation(channel.Target, userID, channel.ID.String())
+			if err != nil {
+				logger.ErrorContext(r.Context(), "failed to send confirmation email", "error", err.Error(), "channel", channel.ID.String(), "user", userID)
+			}
+		}
+		c := ChannelCard(channelToTransportAdapter(channel))
+		SendTempl(w, r, c)
+	}
+}
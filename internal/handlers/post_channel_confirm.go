@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// ConfirmChannel enables the email channel the token was issued for.
+func ConfirmChannel(logger *slog.Logger, linkSigner *signer.Signer, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		value, err := linkSigner.Verify(notifier.ConfirmPurpose, token)
+		if err != nil {
+			http.Error(w, "Invalid or expired confirmation link", http.StatusBadRequest)
+			return
+		}
+
+		userIDstr, channelIDstr := notifier.ParseConfirmValue(value)
+		userID, err := common.ParseID(userIDstr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		channelID, err := common.ParseID(channelIDstr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, err = channelsService.Confirm(r.Context(), channels.ConfirmReq{ID: channelID, UserID: userID})
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "The channel no longer exists", http.StatusNotFound)
+			} else {
+				logger.ErrorContext(r.Context(), "error confirming channel", "err", err.Error(), "channel", channelIDstr, "user", userIDstr)
+				http.Error(w, "Error confirming the email address", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "email channel confirmed", "channel", channelIDstr, "user", userIDstr)
+		c := Layout(ChannelConfirmed(), "Domainator | Email confirmed")
+		SendTempl(w, r, c)
+	}
+}
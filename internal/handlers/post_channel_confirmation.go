@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+// ResendChannelConfirmation sends the link that confirms the address of an email channel again.
+func ResendChannelConfirmation(logger *slog.Logger, channelsService channels.Service, confirmer EmailConfirmer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := ChannelReq{ID: r.PathValue("id"), UserID: userID}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		channel, err := channelsService.Get(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Channel not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Error getting channel", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if channel.Confirmed {
+			http.Error(w, "The channel is already confirmed", http.StatusBadRequest)
+			return
+		}
+
+		err = confirmer.SendConfirmation(channel.Target, userID, channel.ID.String())
+		if err != nil {
+			logger.ErrorContext(r.Context(), "failed to send confirmation email", "error", err.Error(), "channel", channel.ID.String(), "user", userID)
+			http.Error(w, "Error sending the confirmation email", http.StatusInternalServerError)
+			return
+		}
+
+		c := ConfirmationSent()
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func RotateChannelSecret(channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ChannelReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		channel, err := channelsService.RotateSecret(r.Context(), channels.RotateSecretReq{
+			ID:     parsedReq.ID,
+			UserID: parsedReq.UserID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, channels.ErrNotFound):
+				http.Error(w, "Channel not found", http.StatusNotFound)
+			case errors.Is(err, channels.ErrInvalidKind):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				http.Error(w, "Error rotating secret", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		c := ChannelCard(channelToTransportAdapter(channel))
+		SendTempl(w, r, c)
+	}
+}
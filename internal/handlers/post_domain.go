@@ -6,14 +6,15 @@ import (
 
 	"github.com/germandv/domainator/internal/certs"
 	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
 )
 
-func RegisterDomain(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+func RegisterDomain(logger *slog.Logger, certsService certs.Service, usersService users.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := cntxt.GetUserID(r)
 		domain := r.FormValue("domain")
 
-		req := RegisterCertReq{Domain: domain, UserID: userID}
+		req := RegisterCertReq{Domain: domain, UserID: userID, Tags: r.FormValue("tags")}
 		parsedReq, err := req.Parse()
 		if err != nil {
 			c := RegisterDomainError(err.Error())
@@ -28,8 +29,9 @@ func RegisterDomain(logger *slog.Logger, certsService certs.Service) http.Handle
 			return
 		}
 
-		logger.Info("registered new domain", "domain", domain, "user", userID)
-		c := CertRow(serviceToTransportAdapter(cert))
+		logger.InfoContext(r.Context(), "registered new domain", "domain", domain, "user", userID)
+		loc := userLocation(r, usersService)
+		c := CertRow(serviceToTransportAdapter(cert, loc))
 		SendTempl(w, r, c)
 	}
 }
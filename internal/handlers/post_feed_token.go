@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// RotateFeedToken creates the token of the feed URLs of the user, or replaces it so the previous URLs stop working.
+func RotateFeedToken(usersService users.Service, appURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		u, err := usersService.RotateFeedToken(r.Context(), users.RotateFeedTokenReq{UserID: userID})
+		if err != nil {
+			http.Error(w, "Error creating feed link", http.StatusInternalServerError)
+			return
+		}
+
+		c := FeedsForm(feedsToTransportAdapter(u, appURL))
+		SendTempl(w, r, c)
+	}
+}
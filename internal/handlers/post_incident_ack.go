@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/escalation"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// AcknowledgeIncident stops the escalation of the incident the token was issued for,
+// recording the name entered and the channel the link was sent through.
+func AcknowledgeIncident(logger *slog.Logger, linkSigner *signer.Signer, escalationService escalation.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		incidentID, channelID, err := parseAckToken(linkSigner, token)
+		if err != nil {
+			http.Error(w, "Invalid incident link", http.StatusBadRequest)
+			return
+		}
+
+		incident, err := escalationService.Acknowledge(r.Context(), escalation.AcknowledgeReq{
+			ID:        incidentID,
+			ChannelID: channelID,
+			By:        r.FormValue("by"),
+		})
+		if err != nil && !errors.Is(err, escalation.ErrResolved) {
+			switch {
+			case errors.Is(err, escalation.ErrIncidentNotFound):
+				http.Error(w, "Incident not found", http.StatusNotFound)
+			case errors.Is(err, escalation.ErrInvalidName):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				logger.ErrorContext(r.Context(), "error acknowledging incident", "err", err.Error(), "incident", incidentID.String())
+				http.Error(w, "Error acknowledging incident", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "incident acknowledged", "incident", incidentID.String())
+		c := Layout(IncidentAck(token, incidentToTransportAdapter(incident, time.UTC)), "Domainator | Incident")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/escalation"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// SnoozeIncident pauses the escalation of the incident the token was issued for.
+func SnoozeIncident(logger *slog.Logger, linkSigner *signer.Signer, escalationService escalation.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		incidentID, _, err := parseAckToken(linkSigner, token)
+		if err != nil {
+			http.Error(w, "Invalid incident link", http.StatusBadRequest)
+			return
+		}
+
+		req := SnoozeIncidentReq{ID: incidentID, Hours: r.FormValue("hours")}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		incident, err := escalationService.Snooze(r.Context(), parsedReq)
+		if err != nil {
+			switch {
+			case errors.Is(err, escalation.ErrIncidentNotFound):
+				http.Error(w, "Incident not found", http.StatusNotFound)
+			case errors.Is(err, escalation.ErrAcknowledged), errors.Is(err, escalation.ErrResolved):
+				// Show who acknowledged it, or that it's resolved.
+				c := Layout(IncidentAck(token, incidentToTransportAdapter(incident, time.UTC)), "Domainator | Incident")
+				SendTempl(w, r, c)
+			case errors.Is(err, escalation.ErrInvalidSnooze):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				logger.ErrorContext(r.Context(), "error snoozing incident", "err", err.Error(), "incident", incidentID.String())
+				http.Error(w, "Error snoozing incident", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "incident snoozed", "incident", incidentID.String(), "hours", parsedReq.Hours)
+		c := Layout(IncidentSnoozed(incidentToTransportAdapter(incident, time.UTC)), "Domainator | Incident")
+		SendTempl(w, r, c)
+	}
+}
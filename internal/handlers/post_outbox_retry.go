@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/outbox"
+)
+
+func RetryMessage(logger *slog.Logger, outboxService outbox.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := RetryMessageReq{ID: r.PathValue("id"), UserID: cntxt.GetUserID(r)}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		err = outboxService.Retry(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, outbox.ErrNotFound) {
+				c := ChannelError("the notification is not dead-lettered anymore")
+				SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+				return
+			}
+			logger.ErrorContext(r.Context(), "failed to retry message", "id", req.ID, "error", err.Error())
+			http.Error(w, "Error retrying notification", http.StatusInternalServerError)
+			return
+		}
+
+		c := MessageRequeued()
+		SendTempl(w, r, c)
+	}
+}
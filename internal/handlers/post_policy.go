@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/escalation"
+)
+
+func CreatePolicy(escalationService escalation.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := CreatePolicyReq{
+			UserID:      cntxt.GetUserID(r),
+			Name:        r.FormValue("name"),
+			MinSeverity: r.FormValue("min_severity"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		policy, err := escalationService.CreatePolicy(r.Context(), parsedReq)
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: parsedReq.UserID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		c := PolicyCard(policyToTransportAdapter(policy, chs))
+		SendTempl(w, r, c)
+	}
+}
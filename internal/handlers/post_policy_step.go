@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/escalation"
+)
+
+func AddPolicyStep(escalationService escalation.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := AddStepReq{
+			PolicyID:   r.PathValue("id"),
+			UserID:     cntxt.GetUserID(r),
+			ChannelID:  r.FormValue("channel_id"),
+			DelayHours: r.FormValue("delay_hours"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		policy, err := escalationService.AddStep(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, escalation.ErrNotFound) {
+				http.Error(w, "Escalation policy or channel not found", http.StatusNotFound)
+				return
+			}
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: parsedReq.UserID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		c := PolicyCard(policyToTransportAdapter(policy, chs))
+		SendTempl(w, r, c)
+	}
+}
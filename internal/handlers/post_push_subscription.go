@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/push"
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+// PushSubscribe saves the push subscription of the browser and makes sure
+// the user has a channel delivering to their browsers.
+func PushSubscribe(logger *slog.Logger, pushService push.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		req := PushSubscriptionReq{UserID: userID, UserAgent: r.UserAgent()}
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushBody)).Decode(&req)
+		if err != nil {
+			http.Error(w, "Invalid push subscription", http.StatusBadRequest)
+			return
+		}
+
+		parsedReq, err := req.Parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_, err = pushService.Subscribe(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, push.ErrTooMany) || errors.Is(err, webpush.ErrInvalidSubscription) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				logger.ErrorContext(r.Context(), "error saving push subscription", "err", err.Error(), "user", userID)
+				http.Error(w, "Error saving push subscription", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		channel, err := channelsService.EnableWebPush(r.Context(), channels.EnableWebPushReq{UserID: parsedReq.UserID})
+		if err != nil {
+			if errors.Is(err, channels.ErrTooMany) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				logger.ErrorContext(r.Context(), "error enabling browser notifications", "err", err.Error(), "user", userID)
+				http.Error(w, "Error enabling browser notifications", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		logger.InfoContext(r.Context(), "subscribed browser to push notifications", "channel", channel.ID.String(), "user", userID)
+		w.WriteHeader(http.StatusCreated)
+	}
+}
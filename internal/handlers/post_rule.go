@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+)
+
+func AddRule(channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := AddRuleReq{
+			ChannelID:     r.PathValue("id"),
+			UserID:        cntxt.GetUserID(r),
+			DomainPattern: r.FormValue("domain_pattern"),
+			Tag:           r.FormValue("tag"),
+			MinSeverity:   r.FormValue("min_severity"),
+			Destination:   r.FormValue("destination"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		channel, err := channelsService.AddRule(r.Context(), parsedReq)
+		if err != nil {
+			if errors.Is(err, channels.ErrNotFound) {
+				http.Error(w, "Channel not found", http.StatusNotFound)
+				return
+			}
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		c := ChannelCard(channelToTransportAdapter(channel))
+		SendTempl(w, r, c)
+	}
+}
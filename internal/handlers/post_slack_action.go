@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+	"github.com/germandv/domainator/internal/slackapp"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// SlackAction handles the Recheck and Snooze buttons of the alerts posted by the Slack app.
+// Slack only waits 3 seconds, so the action is acknowledged and its result is posted to the response URL.
+func SlackAction(
+	logger *slog.Logger,
+	slackCfg *slackapp.Config,
+	linkSigner *signer.Signer,
+	appURL string,
+	channelsService channels.Service,
+	certsService certs.Service,
+	usersService users.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, err := readSlackForm(r, slackCfg)
+		if err != nil {
+			logger.WarnContext(r.Context(), "invalid Slack action", "err", err.Error())
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		action, err := slackapp.ParseAction(form)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Link buttons, like Open Dashboard, are reported too.
+		if action.ActionID != notifier.SlackActionRecheck && action.ActionID != notifier.SlackActionSnooze {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		certID, err := common.ParseID(action.Value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), slackWorkTimeout)
+			defer cancel()
+
+			msg := slackAction(ctx, logger, linkSigner, appURL, channelsService, certsService, usersService, action, certID)
+			err := slackapp.Respond(ctx, action.ResponseURL, msg)
+			if err != nil {
+				logger.ErrorContext(ctx, "error replying to Slack action", "err", err.Error(), "action", action.ActionID)
+			}
+		}()
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func slackAction(
+	ctx context.Context,
+	logger *slog.Logger,
+	linkSigner *signer.Signer,
+	appURL string,
+	channelsService channels.Service,
+	certsService certs.Service,
+	usersService users.Service,
+	action slackapp.Action,
+	certID common.ID,
+) slackapp.Message {
+	userID, err := slackUserID(ctx, channelsService, action.TeamID, action.UserID)
+	if err != nil {
+		if errors.Is(err, channels.ErrSlackUserNotFound) {
+			return slackLinkMessage(linkSigner, appURL, action.TeamID, action.UserID)
+		}
+		logger.ErrorContext(ctx, "error getting Slack user", "err", err.Error(), "team", action.TeamID, "slackUser", action.UserID)
+		return slackapp.NewMessage("Something went wrong, try again later.")
+	}
+
+	cert := certs.Cert{}
+	text := ""
+	switch action.ActionID {
+	case notifier.SlackActionRecheck:
+		cert, err = certsService.Update(ctx, certs.UpdateReq{ID: certID, UserID: userID})
+		text = "Queued a check of the certificate"
+	case notifier.SlackActionSnooze:
+		cert, err = certsService.Snooze(ctx, certs.SnoozeReq{ID: certID, UserID: userID, Until: time.Now().Add(slackSnooze)})
+		text = "Snoozed the notifications for 7 days"
+	}
+	if err != nil {
+		if errors.Is(err, certs.ErrNotFound) {
+			return slackapp.NewMessage("The domain was not found, it may have been deleted or belong to someone else.")
+		}
+		return slackapp.NewMessage(fmt.Sprintf("Could not %s the domain: %s", action.ActionID, err.Error()))
+	}
+
+	logger.InfoContext(ctx, "Slack action", "action", action.ActionID, "domain", cert.Domain, "user", userID)
+	return slackCertMessage(text, cert, userIDLocation(ctx, usersService, userID))
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/signer"
+	"github.com/germandv/domainator/internal/slackapp"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// SlackCommand handles the `/domainator` slash command. Quick subcommands are answered right away,
+// the ones that check a certificate are acknowledged and answered through the response URL.
+func SlackCommand(
+	logger *slog.Logger,
+	slackCfg *slackapp.Config,
+	linkSigner *signer.Signer,
+	appURL string,
+	channelsService channels.Service,
+	certsService certs.Service,
+	usersService users.Service,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, err := readSlackForm(r, slackCfg)
+		if err != nil {
+			logger.WarnContext(r.Context(), "invalid Slack command", "err", err.Error())
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		cmd, err := slackapp.ParseCommand(form)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		userID, err := slackUserID(r.Context(), channelsService, cmd.TeamID, cmd.UserID)
+		if err != nil {
+			if errors.Is(err, channels.ErrSlackUserNotFound) {
+				sendSlackMessage(w, slackLinkMessage(linkSigner, appURL, cmd.TeamID, cmd.UserID))
+				return
+			}
+			logger.ErrorContext(r.Context(), "error getting Slack user", "err", err.Error(), "team", cmd.TeamID, "slackUser", cmd.UserID)
+			sendSlackMessage(w, slackapp.NewMessage("Something went wrong, try again later."))
+			return
+		}
+
+		args := strings.Fields(cmd.Text)
+		subcommand := ""
+		if len(args) > 0 {
+			subcommand = strings.ToLower(args[0])
+		}
+
+		switch {
+		case subcommand == "status":
+			cs, err := certsService.GetAll(r.Context(), certs.GetAllReq{UserID: userID})
+			if err != nil {
+				logger.ErrorContext(r.Context(), "error getting certs for Slack", "err", err.Error(), "user", userID)
+				sendSlackMessage(w, slackapp.NewMessage("Something went wrong, try again later."))
+				return
+			}
+
+			domain := ""
+			if len(args) > 1 {
+				d, err := certs.ParseDomain(args[1])
+				if err != nil {
+					sendSlackMessage(w, slackapp.NewMessage(err.Error()))
+					return
+				}
+				domain = d.String()
+			}
+
+			sendSlackMessage(w, slackCertsMessage(cs, domain, userIDLocation(r.Context(), usersService, userID)))
+
+		case subcommand == "add" && len(args) > 1:
+			domain, err := certs.ParseDomain(args[1])
+			if err != nil {
+				sendSlackMessage(w, slackapp.NewMessage(err.Error()))
+				return
+			}
+
+			go func() {
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), slackWorkTimeout)
+				defer cancel()
+
+				msg := slackapp.Message{}
+				cert, err := certsService.Save(ctx, certs.RegisterReq{Domain: domain, UserID: userID})
+				if err != nil {
+					msg = slackapp.NewMessage(fmt.Sprintf("Could not add %s: %s", domain, err.Error()))
+				} else {
+					logger.InfoContext(ctx, "domain registered from Slack", "domain", domain, "user", userID)
+					msg = slackCertMessage(fmt.Sprintf("Now monitoring %s, its certificate is being checked", domain), cert, userIDLocation(ctx, usersService, userID))
+				}
+
+				err = slackapp.Respond(ctx, cmd.ResponseURL, msg)
+				if err != nil {
+					logger.ErrorContext(ctx, "error replying to Slack command", "err", err.Error(), "user", userID)
+				}
+			}()
+
+			sendSlackMessage(w, slackapp.NewMessage(fmt.Sprintf("Adding %s...", domain)))
+
+		default:
+			sendSlackMessage(w, slackapp.NewMessage(slackHelp))
+		}
+	}
+}
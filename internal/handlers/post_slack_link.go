@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// LinkSlack lets the Slack user the token was issued for act as the signed in user.
+func LinkSlack(logger *slog.Logger, linkSigner *signer.Signer, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := common.ParseID(cntxt.GetUserID(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		teamID, slackUserID, err := parseSlackLinkToken(linkSigner, r.URL.Query().Get("token"))
+		if err != nil {
+			http.Error(w, "Invalid or expired link, run the command in Slack again", http.StatusBadRequest)
+			return
+		}
+
+		err = channelsService.LinkSlackUser(r.Context(), channels.LinkSlackUserReq{
+			UserID:      userID,
+			TeamID:      teamID,
+			SlackUserID: slackUserID,
+		})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "error linking Slack user", "err", err.Error(), "team", teamID, "slackUser", slackUserID, "user", userID)
+			http.Error(w, "Error linking Slack", http.StatusInternalServerError)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "linked Slack user", "team", teamID, "slackUser", slackUserID, "user", userID)
+		c := Layout(SlackLinked(), "Domainator | Slack linked")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// PreviewTemplate renders a message template with a sample notification of the selected event.
+func PreviewTemplate(appURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		event, err := notifier.ParseEvent(r.FormValue("event"))
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		if event == notifier.EventAny {
+			event = notifier.EventExpiring
+		}
+
+		msg, err := notifier.RenderMessage(r.FormValue("body"), notifier.SampleNotification(event), appURL)
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		c := MessagePreview(msg)
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
+)
+
+// CreateToken creates a personal access token for the API, its secret is shown only in the response.
+func CreateToken(logger *slog.Logger, tokensService apitokens.Service, usersService users.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := cntxt.GetUserID(r)
+
+		err := r.ParseForm()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		req := CreateTokenReq{UserID: userID, Name: r.FormValue("name"), Scopes: r.Form["scopes"]}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		token, secret, err := tokensService.Create(r.Context(), parsedReq)
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "created access token", "token", token.ID.String(), "user", userID)
+		t := tokenToTransportAdapter(token, userLocation(r, usersService))
+		t.Secret = secret
+		c := TokenCard(t)
+		SendTempl(w, r, c)
+	}
+}
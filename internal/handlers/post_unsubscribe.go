@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// Unsubscribe disables the email channels of the user sending to the address the token was issued for.
+// It handles both the confirmation form and one-click unsubscribe requests (RFC 8058).
+func Unsubscribe(logger *slog.Logger, linkSigner *signer.Signer, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		value, err := linkSigner.Verify(notifier.UnsubscribePurpose, token)
+		if err != nil {
+			http.Error(w, "Invalid unsubscribe link", http.StatusBadRequest)
+			return
+		}
+
+		userIDstr, address := notifier.ParseUnsubscribeValue(value)
+		userID, err := common.ParseID(userIDstr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = channelsService.DisableEmail(r.Context(), channels.DisableEmailReq{
+			UserID:  userID,
+			Address: address,
+		})
+		if err != nil && !errors.Is(err, channels.ErrNotFound) {
+			logger.ErrorContext(r.Context(), "error unsubscribing", "err", err.Error(), "user", userIDstr)
+			http.Error(w, "Error unsubscribing", http.StatusInternalServerError)
+			return
+		}
+
+		logger.InfoContext(r.Context(), "user unsubscribed from emails", "user", userIDstr)
+		c := Layout(Unsubscribed(), "Domainator | Unsubscribed")
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/push"
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+// maxPushBody caps the JSON of a subscription sent by the browser.
+const maxPushBody = 4096
+
+// PushSubscriptionReq is the JSON of PushSubscription.toJSON() in the browser.
+type PushSubscriptionReq struct {
+	UserID    string `json:"-"`
+	UserAgent string `json:"-"`
+	Endpoint  string `json:"endpoint"`
+	Keys      struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r PushSubscriptionReq) Parse() (push.SubscribeReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return push.SubscribeReq{}, err
+	}
+
+	sub := webpush.Subscription{Endpoint: r.Endpoint, P256dh: r.Keys.P256dh, Auth: r.Keys.Auth}
+	err = sub.Validate()
+	if err != nil {
+		return push.SubscribeReq{}, err
+	}
+
+	return push.SubscribeReq{
+		UserID:       userID,
+		Subscription: sub,
+		UserAgent:    r.UserAgent,
+	}, nil
+}
+
+type PushUnsubscribeReq struct {
+	UserID   string `json:"-"`
+	Endpoint string `json:"endpoint"`
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r PushUnsubscribeReq) Parse() (push.UnsubscribeReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return push.UnsubscribeReq{}, err
+	}
+
+	return push.UnsubscribeReq{
+		UserID:   userID,
+		Endpoint: r.Endpoint,
+	}, nil
+}
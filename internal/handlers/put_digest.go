@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func SetDigest(usersService users.Service, channelsService channels.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := SetDigestReq{
+			UserID:    cntxt.GetUserID(r),
+			Frequency: r.FormValue("frequency"),
+			Weekday:   r.FormValue("weekday"),
+			Hour:      r.FormValue("hour"),
+			Days:      r.FormValue("days"),
+			ChannelID: r.FormValue("channel_id"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		chs, err := channelsService.GetAll(r.Context(), channels.GetAllReq{UserID: parsedReq.UserID})
+		if err != nil {
+			http.Error(w, "Error getting channels", http.StatusInternalServerError)
+			return
+		}
+
+		if channelID := parsedReq.Digest.ChannelID.String(); channelID != "" {
+			found := false
+			for _, ch := range chs {
+				found = found || ch.ID.String() == channelID
+			}
+			if !found {
+				c := ChannelError(channels.ErrNotFound.Error())
+				SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+				return
+			}
+		}
+
+		u, err := usersService.SetDigest(r.Context(), parsedReq)
+		if err != nil {
+			http.Error(w, "Error saving digest schedule", http.StatusInternalServerError)
+			return
+		}
+
+		c := DigestForm(digestToTransportAdapter(u.Digest, chs), true)
+		SendTempl(w, r, c)
+	}
+}
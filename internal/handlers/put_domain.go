@@ -7,9 +7,10 @@ import (
 
 	"github.com/germandv/domainator/internal/certs"
 	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
 )
 
-func UpdateDomain(logger *slog.Logger, certsService certs.Service) http.HandlerFunc {
+func UpdateDomain(logger *slog.Logger, certsService certs.Service, usersService users.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := cntxt.GetUserID(r)
 
@@ -36,8 +37,9 @@ func UpdateDomain(logger *slog.Logger, certsService certs.Service) http.HandlerF
 			return
 		}
 
-		logger.Info("refreshed domain", "domain", cert.Domain.String(), "user", userID)
-		c := CertRow(serviceToTransportAdapter(cert))
+		logger.InfoContext(r.Context(), "queued a check of domain", "domain", cert.Domain.String(), "user", userID)
+		loc := userLocation(r, usersService)
+		c := CertRow(serviceToTransportAdapter(cert, loc))
 		SendTempl(w, r, c)
 	}
 }
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func SetQuietHours(usersService users.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := SetQuietHoursReq{
+			UserID:  cntxt.GetUserID(r),
+			Enabled: r.FormValue("enabled"),
+			Start:   r.FormValue("start"),
+			End:     r.FormValue("end"),
+		}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		u, err := usersService.SetQuietHours(r.Context(), parsedReq)
+		if err != nil {
+			http.Error(w, "Error saving quiet hours", http.StatusInternalServerError)
+			return
+		}
+
+		c := QuietHoursForm(quietHoursToTransportAdapter(u.QuietHours), true)
+		SendTempl(w, r, c)
+	}
+}
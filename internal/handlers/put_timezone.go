@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/users"
+)
+
+func SetTimezone(usersService users.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := SetTimezoneReq{UserID: cntxt.GetUserID(r), Timezone: r.FormValue("timezone")}
+		parsedReq, err := req.Parse()
+		if err != nil {
+			c := ChannelError(err.Error())
+			SendTemplWithStatus(http.StatusBadRequest, w, r, c)
+			return
+		}
+
+		u, err := usersService.SetTimezone(r.Context(), parsedReq)
+		if err != nil {
+			http.Error(w, "Error saving timezone", http.StatusInternalServerError)
+			return
+		}
+
+		c := TimezoneForm(u.Timezone.String(), true)
+		SendTempl(w, r, c)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/certs"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/signer"
+	"github.com/germandv/domainator/internal/slackapp"
+)
+
+const (
+	// SlackLinkPurpose is the purpose of the signed links that link a Slack user to a Domainator account.
+	SlackLinkPurpose = "slack-link"
+	slackLinkTTL     = time.Hour
+	slackSnooze      = 7 * 24 * time.Hour
+	// slackWorkTimeout bounds the work done after acknowledging a Slack request, Slack expects an answer within 3 seconds.
+	slackWorkTimeout = 30 * time.Second
+	maxSlackBody     = 64 * 1024
+)
+
+const slackHelp = "Usage:\n" +
+	"`/domainator status` lists your domains\n" +
+	"`/domainator status example.com` shows the certificate of a domain\n" +
+	"`/domainator add example.com` starts monitoring a domain"
+
+// readSlackForm verifies the signature of a request sent by Slack and returns the form it carries.
+func readSlackForm(r *http.Request, slackCfg *slackapp.Config) (url.Values, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackBody))
+	if err != nil {
+		return nil, err
+	}
+
+	err = slackCfg.VerifySignature(r.Header, body, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return url.ParseQuery(string(body))
+}
+
+// sendSlackMessage replies to Slack within the request.
+func sendSlackMessage(w http.ResponseWriter, msg slackapp.Message) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err := json.NewEncoder(w).Encode(msg)
+	if err != nil {
+		http.Error(w, "Error encoding message", http.StatusInternalServerError)
+	}
+}
+
+// slackLinkValue is the value signed in account linking links.
+func slackLinkValue(teamID string, slackUserID string) string {
+	return teamID + ":" + slackUserID
+}
+
+func parseSlackLinkToken(linkSigner *signer.Signer, token string) (string, string, error) {
+	value, err := linkSigner.Verify(SlackLinkPurpose, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	teamID, slackUserID, found := strings.Cut(value, ":")
+	if !found || teamID == "" || slackUserID == "" {
+		return "", "", signer.ErrInvalidToken
+	}
+	return teamID, slackUserID, nil
+}
+
+// slackLinkMessage asks a Slack user without a linked account to link it.
+func slackLinkMessage(linkSigner *signer.Signer, appURL string, teamID string, slackUserID string) slackapp.Message {
+	token := linkSigner.Sign(SlackLinkPurpose, slackLinkValue(teamID, slackUserID), time.Now().Add(slackLinkTTL))
+	link := fmt.Sprintf("%s/slack/link?token=%s", appURL, url.QueryEscape(token))
+	return slackapp.NewMessage(fmt.Sprintf("Your Slack user isn't linked to a Domainator account yet, <%s|link it> (the link is valid for an hour) and try again.", link))
+}
+
+// slackUserID returns the Domainator user the Slack user acts as.
+func slackUserID(ctx context.Context, channelsService channels.Service, teamID string, slackUserID string) (common.ID, error) {
+	return channelsService.GetSlackUser(ctx, channels.SlackUserReq{TeamID: teamID, SlackUserID: slackUserID})
+}
+
+// slackCertBlock formats a cert as a Block Kit section, with its dates in the given location.
+func slackCertBlock(c certs.Cert, loc *time.Location) map[string]any {
+	t := serviceToTransportAdapter(c, loc)
+	lines := []string{fmt.Sprintf("*%s*: %s", t.Domain, t.Status)}
+	if !c.Pending() {
+		lines = append(lines, fmt.Sprintf("Issued by %s, expires %s, last checked %s", t.Issuer, t.ExpiresAt, t.LastUpdate))
+	}
+	if c.Snoozed(time.Now()) {
+		lines = append(lines, fmt.Sprintf("Notifications snoozed until %s", c.SnoozedUntil.In(loc).Format(time.DateTime)))
+	}
+	if len(t.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(t.Tags, ", "))
+	}
+	return map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": strings.Join(lines, "\n")}}
+}
+
+// slackCertMessage replies with the details of a cert, the first line is used in notifications.
+func slackCertMessage(text string, c certs.Cert, loc *time.Location) slackapp.Message {
+	return slackapp.NewMessage(text, map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": text}}, slackCertBlock(c, loc))
+}
+
+// slackCertsMessage replies with the certs of the user, the status of one of them if domain isn't empty.
+func slackCertsMessage(cs []certs.Cert, domain string, loc *time.Location) slackapp.Message {
+	blocks := []map[string]any{}
+	for _, c := range cs {
+		if domain == "" || c.Domain.String() == domain {
+			blocks = append(blocks, slackCertBlock(c, loc))
+		}
+	}
+
+	switch {
+	case len(blocks) == 0 && domain != "":
+		return slackapp.NewMessage(fmt.Sprintf("You're not monitoring %s, add it with `/domainator add %s`.", domain, domain))
+	case len(blocks) == 0:
+		return slackapp.NewMessage("You're not monitoring any domains yet, add one with `/domainator add example.com`.")
+	case len(blocks) > 40:
+		// Messages can have up to 50 blocks.
+		blocks = blocks[:40]
+	}
+
+	return slackapp.NewMessage("Status of your domains", blocks...)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/common"
+)
+
+type CreateTokenReq struct {
+	UserID string
+	Name   string
+	Scopes []string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r CreateTokenReq) Parse() (apitokens.CreateReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return apitokens.CreateReq{}, err
+	}
+
+	scopes, err := apitokens.ParseScopes(r.Scopes)
+	if err != nil {
+		return apitokens.CreateReq{}, err
+	}
+
+	return apitokens.CreateReq{
+		UserID: userID,
+		Name:   r.Name,
+		Scopes: scopes,
+	}, nil
+}
+
+type TokenReq struct {
+	ID     string
+	UserID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r TokenReq) Parse() (apitokens.RevokeReq, error) {
+	id, err := common.ParseID(r.ID)
+	if err != nil {
+		return apitokens.RevokeReq{}, err
+	}
+
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return apitokens.RevokeReq{}, err
+	}
+
+	return apitokens.RevokeReq{
+		ID:     id,
+		UserID: userID,
+	}, nil
+}
+
+// TransportToken represents a personal access Token in the Transport layer,
+// Secret is only set right after creating it.
+type TransportToken struct {
+	ID        string
+	Name      string
+	Prefix    string
+	Scopes    string
+	LastUsed  string
+	CreatedAt string
+	Secret    string
+}
+
+func tokenScopes() []TransportOption {
+	scopes := make([]TransportOption, len(apitokens.Scopes))
+	for i, s := range apitokens.Scopes {
+		scopes[i] = TransportOption{Value: s.String(), Label: s.Label()}
+	}
+	return scopes
+}
+
+// tokenToTransportAdapter transforms a Token from the Service layer to the Transport layer,
+// with its dates in the given location, the timezone of the user.
+func tokenToTransportAdapter(t apitokens.Token, loc *time.Location) TransportToken {
+	scopes := make([]string, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = s.String()
+	}
+
+	lastUsed := "never"
+	if !t.LastUsedAt.IsZero() {
+		lastUsed = t.LastUsedAt.In(loc).Format("2006-01-02 15:04")
+	}
+
+	return TransportToken{
+		ID:        t.ID.String(),
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    strings.Join(scopes, ", "),
+		LastUsed:  lastUsed,
+		CreatedAt: t.CreatedAt.In(loc).Format(time.DateOnly),
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/apitokens"
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/cntxt"
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/users"
+)
+
+type SetTimezoneReq struct {
+	UserID   string
+	Timezone string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SetTimezoneReq) Parse() (users.SetTimezoneReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return users.SetTimezoneReq{}, err
+	}
+
+	timezone, err := users.ParseTimezone(r.Timezone)
+	if err != nil {
+		return users.SetTimezoneReq{}, err
+	}
+
+	return users.SetTimezoneReq{
+		UserID:   userID,
+		Timezone: timezone,
+	}, nil
+}
+
+type SetDigestReq struct {
+	UserID    string
+	Frequency string
+	Weekday   string
+	Hour      string
+	Days      string
+	ChannelID string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SetDigestReq) Parse() (users.SetDigestReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return users.SetDigestReq{}, err
+	}
+
+	frequency, err := users.ParseFrequency(r.Frequency)
+	if err != nil {
+		return users.SetDigestReq{}, err
+	}
+
+	weekday, err := strconv.Atoi(r.Weekday)
+	if err != nil {
+		return users.SetDigestReq{}, users.ErrInvalidDigest
+	}
+
+	hour, err := strconv.Atoi(r.Hour)
+	if err != nil {
+		return users.SetDigestReq{}, users.ErrInvalidDigest
+	}
+
+	days, err := strconv.Atoi(strings.TrimSpace(r.Days))
+	if err != nil {
+		return users.SetDigestReq{}, users.ErrInvalidDigest
+	}
+
+	channelID := common.ID{}
+	if r.ChannelID != "" {
+		channelID, err = common.ParseID(r.ChannelID)
+		if err != nil {
+			return users.SetDigestReq{}, err
+		}
+	}
+
+	digest, err := users.NewDigest(frequency, weekday, hour, days, channelID)
+	if err != nil {
+		return users.SetDigestReq{}, err
+	}
+
+	return users.SetDigestReq{
+		UserID: userID,
+		Digest: digest,
+	}, nil
+}
+
+type SetQuietHoursReq struct {
+	UserID  string
+	Enabled string
+	Start   string
+	End     string
+}
+
+// Parse converts it from the Transport layer to the Service layer.
+func (r SetQuietHoursReq) Parse() (users.SetQuietHoursReq, error) {
+	userID, err := common.ParseID(r.UserID)
+	if err != nil {
+		return users.SetQuietHoursReq{}, err
+	}
+
+	quietHours, err := users.ParseQuietHours(r.Enabled == "true", r.Start, r.End)
+	if err != nil {
+		return users.SetQuietHoursReq{}, err
+	}
+
+	return users.SetQuietHoursReq{
+		UserID:     userID,
+		QuietHours: quietHours,
+	}, nil
+}
+
+// TransportSettings represents a User's settings in the Transport layer.
+type TransportSettings struct {
+	Email      string
+	Timezone   string
+	QuietHours TransportQuietHours
+	Channels   []TransportChannel
+	Digest     TransportDigest
+	// SlackApp tells whether the Slack app can be installed.
+	SlackApp bool
+	// VAPIDKey is the key browsers subscribe to push notifications with, empty when Web Push is disabled.
+	VAPIDKey string
+	Feeds    TransportFeeds
+	Tokens   []TransportToken
+}
+
+// TransportQuietHours represents the QuietHours of a User in the Transport layer.
+type TransportQuietHours struct {
+	Enabled bool
+	Start   string
+	End     string
+}
+
+// TransportDigest represents the digest schedule of a User in the Transport layer,
+// along with the channels it can be delivered through.
+type TransportDigest struct {
+	Frequency string
+	Weekday   string
+	Hour      string
+	Days      string
+	ChannelID string
+	Channels  []TransportOption
+}
+
+// userToSettingsAdapter transforms a User and its Channels from the Service layer to Settings in the Transport layer.
+func userToSettingsAdapter(u users.User, chs []channels.Channel, slackApp bool, vapidKey string, appURL string, tokens []apitokens.Token) TransportSettings {
+	loc := u.Timezone.Location()
+	transportTokens := make([]TransportToken, len(tokens))
+	for i, t := range tokens {
+		transportTokens[i] = tokenToTransportAdapter(t, loc)
+	}
+
+	transportChannels := make([]TransportChannel, len(chs))
+	for i, ch := range chs {
+		transportChannels[i] = channelToTransportAdapter(ch)
+	}
+
+	return TransportSettings{
+		Email:      u.Email.String(),
+		Timezone:   u.Timezone.String(),
+		QuietHours: quietHoursToTransportAdapter(u.QuietHours),
+		Channels:   transportChannels,
+		Digest:     digestToTransportAdapter(u.Digest, chs),
+		SlackApp:   slackApp,
+		VAPIDKey:   vapidKey,
+		Feeds:      feedsToTransportAdapter(u, appURL),
+		Tokens:     transportTokens,
+	}
+}
+
+// quietHoursToTransportAdapter transforms QuietHours from the Service layer to the Transport layer,
+// suggesting a night window when they are disabled.
+func quietHoursToTransportAdapter(q users.QuietHours) TransportQuietHours {
+	if !q.Enabled {
+		return TransportQuietHours{Enabled: false, Start: "22:00", End: "07:00"}
+	}
+	return TransportQuietHours{Enabled: true, Start: q.StartString(), End: q.EndString()}
+}
+
+// digestToTransportAdapter transforms a Digest from the Service layer to the Transport layer,
+// incident channels aren't offered, they are for alerts only.
+func digestToTransportAdapter(d users.Digest, chs []channels.Channel) TransportDigest {
+	options := []TransportOption{{Value: "", Label: "All channels"}}
+	for _, c := range chs {
+		if !c.Kind.IsIncident() {
+			options = append(options, TransportOption{Value: c.ID.String(), Label: c.Name})
+		}
+	}
+
+	return TransportDigest{
+		Frequency: string(d.Frequency),
+		Weekday:   strconv.Itoa(int(d.Weekday)),
+		Hour:      strconv.Itoa(d.Hour),
+		Days:      strconv.Itoa(d.Days),
+		ChannelID: d.ChannelID.String(),
+		Channels:  options,
+	}
+}
+
+func weekdays() []TransportOption {
+	options := make([]TransportOption, 7)
+	for i := range options {
+		// Starting on Monday.
+		day := time.Weekday((i + 1) % 7)
+		options[i] = TransportOption{Value: strconv.Itoa(int(day)), Label: day.String()}
+	}
+	return options
+}
+
+func hours() []TransportOption {
+	options := make([]TransportOption, 24)
+	for i := range options {
+		options[i] = TransportOption{Value: strconv.Itoa(i), Label: time.Date(0, 1, 1, i, 0, 0, 0, time.UTC).Format("15:04")}
+	}
+	return options
+}
+
+// userLocation returns the timezone of the user making the request, UTC if it cannot be found.
+func userLocation(r *http.Request, usersService users.Service) *time.Location {
+	userID, err := common.ParseID(cntxt.GetUserID(r))
+	if err != nil {
+		return time.UTC
+	}
+
+	return userIDLocation(r.Context(), usersService, userID)
+}
+
+// userIDLocation returns the timezone of the user, UTC if it cannot be found.
+func userIDLocation(ctx context.Context, usersService users.Service, userID common.ID) *time.Location {
+	u, err := usersService.GetByID(ctx, users.GetByIDReq{UserID: userID})
+	if err != nil {
+		return time.UTC
+	}
+
+	return u.Timezone.Location()
+}
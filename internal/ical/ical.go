@@ -0,0 +1,147 @@
+// Package ical writes calendars in the iCalendar format (RFC 5545),
+// to be subscribed to from calendar clients.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxLineLength is the limit of octets of a content line, longer lines are folded.
+	maxLineLength = 75
+	dateFormat    = "20060102"
+	utcFormat     = "20060102T150405Z"
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Calendar is a feed of all-day events.
+type Calendar struct {
+	// ProdID identifies the product that wrote the calendar.
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event is an all-day event on the date of Date in its location.
+type Event struct {
+	// UID must stay the same for the event across refreshes, so clients update it instead of adding a new one.
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Date        time.Time
+	Modified    time.Time
+	// Alarms are reminders shown the given duration before the event.
+	Alarms []time.Duration
+}
+
+// Write writes the calendar, stamped with the given time.
+func (c Calendar) Write(w io.Writer, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	stamp := now.UTC().Format(utcFormat)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + c.ProdID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + Escape(c.Name),
+	}
+	for _, l := range lines {
+		writeLine(bw, l)
+	}
+
+	for _, e := range c.Events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+Escape(e.UID))
+		writeLine(bw, "DTSTAMP:"+stamp)
+		if !e.Modified.IsZero() {
+			writeLine(bw, "LAST-MODIFIED:"+e.Modified.UTC().Format(utcFormat))
+		}
+		writeLine(bw, "DTSTART;VALUE=DATE:"+e.Date.Format(dateFormat))
+		writeLine(bw, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format(dateFormat))
+		writeLine(bw, "SUMMARY:"+Escape(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+Escape(e.Description))
+		}
+		if e.URL != "" {
+			writeLine(bw, "URL:"+e.URL)
+		}
+		writeLine(bw, "TRANSP:TRANSPARENT")
+
+		for _, a := range e.Alarms {
+			writeLine(bw, "BEGIN:VALARM")
+			writeLine(bw, "ACTION:DISPLAY")
+			writeLine(bw, "DESCRIPTION:"+Escape(e.Summary))
+			writeLine(bw, "TRIGGER:"+Duration(-a))
+			writeLine(bw, "END:VALARM")
+		}
+
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// Escape escapes a TEXT value.
+func Escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// Duration formats d as a DURATION value, e.g. -P7D or PT12H.
+func Duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+
+	s := sign + "P"
+	if days > 0 {
+		s += fmt.Sprintf("%dD", days)
+	}
+	if hours > 0 || minutes > 0 || days == 0 {
+		s += "T"
+		if hours > 0 {
+			s += fmt.Sprintf("%dH", hours)
+		}
+		if minutes > 0 || hours == 0 {
+			s += fmt.Sprintf("%dM", minutes)
+		}
+	}
+
+	return s
+}
+
+// writeLine writes a content line ending in CRLF, folding it so no line is longer than 75 octets
+// without splitting multi-byte characters.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of continuation lines counts towards their length.
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,90 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{-7 * 24 * time.Hour, "-P7D"},
+		{24 * time.Hour, "P1D"},
+		{-36 * time.Hour, "-P1DT12H"},
+		{90 * time.Minute, "PT1H30M"},
+		{0, "PT0M"},
+	}
+
+	for _, tc := range tests {
+		if got := Duration(tc.duration); got != tc.expected {
+			t.Errorf("Expected %q for %s, got %q", tc.expected, tc.duration, got)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+
+	got := Escape("a,b;c\\d\ne")
+	expected := `a\,b\;c\\d\ne`
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cal := Calendar{
+		ProdID: "-//Domainator//EN",
+		Name:   "Certificates",
+		Events: []Event{{
+			UID:         "1@domainator",
+			Summary:     "example.com expires",
+			Description: strings.Repeat("ñ", 60),
+			Date:        time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+			Alarms:      []time.Duration{7 * 24 * time.Hour},
+		}},
+	}
+
+	var sb strings.Builder
+	err := cal.Write(&sb, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20240501T100000Z\r\n",
+		"DTSTART;VALUE=DATE:20240630\r\n",
+		"DTEND;VALUE=DATE:20240701\r\n",
+		"TRIGGER:-P7D\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected output to contain %q", e)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("Expected lines of at most %d octets, got %d: %q", maxLineLength, len(line), line)
+		}
+		if !strings.HasPrefix(line, " ") && strings.ContainsRune(line, '\n') {
+			t.Errorf("Expected no bare line feeds, got %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("ñ", 60)) {
+		t.Error("Expected the folded description to unfold to the original one")
+	}
+}
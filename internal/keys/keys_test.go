@@ -34,3 +34,26 @@ func TestKeypair(t *testing.T) {
 		}
 	})
 }
+
+func TestVAPID(t *testing.T) {
+	t.Parallel()
+
+	priv, publ, err := NewVAPID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privKey, err := DecodeVAPID(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := VAPIDPublic(privKey); got != publ {
+		t.Errorf("Expected public key %q, got %q", publ, got)
+	}
+
+	_, err = DecodeVAPID("not a key")
+	if err != ErrInvalidVAPIDKey {
+		t.Errorf("Expected ErrInvalidVAPIDKey, got %v", err)
+	}
+}
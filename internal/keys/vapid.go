@@ -0,0 +1,60 @@
+package keys
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidVAPIDKey = errors.New("invalid VAPID key, it must be a base64url encoded P-256 private key")
+
+// NewVAPID generates the key-pair that identifies the server to Web Push services (RFC 8292).
+// Both keys are base64url encoded without padding: the private key is the raw 32 bytes scalar
+// and the public key the uncompressed point, the format browsers take as applicationServerKey.
+func NewVAPID() (string, string, error) {
+	privateKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	priv := base64.RawURLEncoding.EncodeToString(privateKey.Bytes())
+	publ := base64.RawURLEncoding.EncodeToString(privateKey.PublicKey().Bytes())
+	return priv, publ, nil
+}
+
+// DecodeVAPID decodes a private key generated by NewVAPID, to sign VAPID tokens.
+func DecodeVAPID(private string) (*ecdsa.PrivateKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(private)
+	if err != nil {
+		return nil, ErrInvalidVAPIDKey
+	}
+
+	ecdhKey, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, ErrInvalidVAPIDKey
+	}
+
+	// The uncompressed point is 0x04 followed by X and Y.
+	point := ecdhKey.PublicKey().Bytes()
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(point[1:33]),
+			Y:     new(big.Int).SetBytes(point[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}, nil
+}
+
+// VAPIDPublic returns the public key of a VAPID private key, in the format of NewVAPID.
+func VAPIDPublic(privateKey *ecdsa.PrivateKey) string {
+	ecdhKey, err := privateKey.PublicKey.ECDH()
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(ecdhKey.Bytes())
+}
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ChatKind is the chat platform an incoming webhook belongs to.
+type ChatKind string
+
+const (
+	ChatKindAuto       ChatKind = ""
+	ChatKindSlack      ChatKind = "slack"
+	ChatKindDiscord    ChatKind = "discord"
+	ChatKindTeams      ChatKind = "teams"
+	ChatKindMattermost ChatKind = "mattermost"
+)
+
+var ErrInvalidChatKind = errors.New("invalid chat kind, use one of 'slack', 'discord', 'teams' or 'mattermost'")
+
+func ParseChatKind(kind string) (ChatKind, error) {
+	switch k := ChatKind(strings.TrimSpace(kind)); k {
+	case ChatKindAuto, ChatKindSlack, ChatKindDiscord, ChatKindTeams, ChatKindMattermost:
+		return k, nil
+	default:
+		return ChatKindAuto, ErrInvalidChatKind
+	}
+}
+
+// DetectChatKind guesses the chat platform from the webhook URL, defaulting to Slack.
+func DetectChatKind(webhookURL string) ChatKind {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return ChatKindSlack
+	}
+
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return ChatKindSlack
+	case (host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com")) &&
+		strings.HasPrefix(u.Path, "/api/webhooks/"):
+		return ChatKindDiscord
+	case host == "outlook.office.com",
+		strings.HasSuffix(host, ".webhook.office.com"),
+		strings.HasSuffix(host, ".logic.azure.com"),
+		strings.HasSuffix(host, ".powerplatform.com"),
+		strings.HasSuffix(host, ".powerautomate.com"):
+		return ChatKindTeams
+	case strings.HasPrefix(u.Path, "/hooks/"):
+		return ChatKindMattermost
+	default:
+		return ChatKindSlack
+	}
+}
+
+// NewChatNotifier returns the notifier for the given platform,
+// detecting it from the webhook URL when the kind is ChatKindAuto.
+func NewChatNotifier(kind ChatKind, webhookURL string, appURL string) Notifier {
+	if kind == ChatKindAuto {
+		kind = DetectChatKind(webhookURL)
+	}
+
+	switch kind {
+	case ChatKindDiscord:
+		return NewDiscorder(appURL)
+	case ChatKindTeams:
+		return NewTeamser(appURL)
+	case ChatKindMattermost:
+		return NewMattermoster(appURL)
+	default:
+		return NewSlacker()
+	}
+}
+
+// statusColor returns a color (as 0xRRGGBB) representing how bad the status is.
+func statusColor(status string) int {
+	switch status {
+	case "OK", StatusResolved:
+		return 0xA3BE8C
+	case "expires soon", "expires today":
+		return 0xD08770
+	default:
+		return 0xBF616A
+	}
+}
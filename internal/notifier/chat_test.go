@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectChatKind(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  ChatKind
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", ChatKindSlack},
+		{"https://discord.com/api/webhooks/123/abc", ChatKindDiscord},
+		{"https://discordapp.com/api/webhooks/123/abc", ChatKindDiscord},
+		{"https://ptb.discord.com/api/webhooks/123/abc", ChatKindDiscord},
+		{"https://acme.webhook.office.com/webhookb2/abc", ChatKindTeams},
+		{"https://outlook.office.com/webhook/abc", ChatKindTeams},
+		{"https://prod-01.westus.logic.azure.com:443/workflows/abc", ChatKindTeams},
+		{"https://chat.acme.dev/hooks/xi5fg3bnkjdc7ny7b8ykqt5mar", ChatKindMattermost},
+		{"https://example.com/webhook", ChatKindSlack},
+		{"", ChatKindSlack},
+	}
+
+	for _, tc := range tt {
+		got := DetectChatKind(tc.input)
+		if got != tc.want {
+			t.Errorf("%q: expected %q but got %q", tc.input, tc.want, got)
+		}
+	}
+}
+
+func TestParseChatKind(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "slack", "discord", "teams", "mattermost"} {
+		_, err := ParseChatKind(input)
+		if err != nil {
+			t.Errorf("%q: expected no error but got %q", input, err)
+		}
+	}
+
+	_, err := ParseChatKind("irc")
+	if err == nil {
+		t.Errorf("expected error for unknown kind")
+	}
+}
+
+func TestChatNotifiers(t *testing.T) {
+	t.Parallel()
+
+	notification := Notification{Domain: "example.com", Status: "expired", Issuer: "Let's Encrypt"}
+
+	tt := []struct {
+		kind  ChatKind
+		check func(t *testing.T, payload map[string]any)
+	}{
+		{ChatKindSlack, func(t *testing.T, payload map[string]any) {
+			if payload["text"] == nil {
+				t.Errorf("slack: expected text in %v", payload)
+			}
+		}},
+		{ChatKindDiscord, func(t *testing.T, payload map[string]any) {
+			embeds, ok := payload["embeds"].([]any)
+			if !ok || len(embeds) != 1 {
+				t.Fatalf("discord: expected one embed in %v", payload)
+			}
+			if embeds[0].(map[string]any)["title"] != "example.com" {
+				t.Errorf("discord: unexpected embed %v", embeds[0])
+			}
+		}},
+		{ChatKindTeams, func(t *testing.T, payload map[string]any) {
+			attachments, ok := payload["attachments"].([]any)
+			if !ok || len(attachments) != 1 {
+				t.Fatalf("teams: expected one attachment in %v", payload)
+			}
+			if attachments[0].(map[string]any)["contentType"] != "application/vnd.microsoft.card.adaptive" {
+				t.Errorf("teams: unexpected attachment %v", attachments[0])
+			}
+		}},
+		{ChatKindMattermost, func(t *testing.T, payload map[string]any) {
+			attachments, ok := payload["attachments"].([]any)
+			if !ok || len(attachments) != 1 {
+				t.Fatalf("mattermost: expected one attachment in %v", payload)
+			}
+			if attachments[0].(map[string]any)["title"] != "example.com" {
+				t.Errorf("mattermost: unexpected attachment %v", attachments[0])
+			}
+		}},
+	}
+
+	for _, tc := range tt {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			payload := map[string]any{}
+			err := json.Unmarshal(body, &payload)
+			if err != nil {
+				t.Errorf("%s: invalid JSON payload: %v", tc.kind, err)
+			}
+			tc.check(t, payload)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		err := NewChatNotifier(tc.kind, ts.URL, "http://localhost:4000").Notify(ts.URL, notification)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", tc.kind, err)
+		}
+		ts.Close()
+	}
+}
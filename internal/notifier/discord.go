@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DiscordNotifier struct {
+	Timeout time.Duration
+	appURL  string
+}
+
+func NewDiscorder(appURL string) Notifier {
+	return &DiscordNotifier{
+		Timeout: 5 * time.Second,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type DiscordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []DiscordEmbed `json:"embeds"`
+}
+
+type DiscordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []DiscordEmbedField `json:"fields"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+func (dn *DiscordNotifier) Notify(to string, notification Notification) error {
+	embed := DiscordEmbed{
+		Title:       notification.Domain,
+		Description: notification.Message,
+		URL:         dn.appURL + "/dashboard",
+		Color:       statusColor(notification.Status),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Fields: []DiscordEmbedField{
+			{Name: "Status", Value: notification.Status, Inline: true},
+			{Name: "Hours", Value: strconv.Itoa(notification.Hours), Inline: true},
+		},
+	}
+	if notification.Issuer != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{Name: "Issuer", Value: notification.Issuer, Inline: true})
+	}
+	if notification.AckURL != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{Name: "Incident", Value: "[Acknowledge or snooze](" + notification.AckURL + ")"})
+	}
+
+	payload := DiscordMessage{Username: "Domainator", Embeds: []DiscordEmbed{embed}}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(dn.Timeout, to, body, nil, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending discord msg: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/signer"
+)
+
+// UnsubscribePurpose is the purpose used to sign unsubscribe tokens.
+const UnsubscribePurpose = "unsubscribe"
+
+// ConfirmPurpose is the purpose used to sign the tokens that confirm an email channel.
+const ConfirmPurpose = "confirm-email"
+
+// confirmTTL is how long the link to confirm an email channel works.
+const confirmTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidTLSMode = errors.New("invalid SMTP TLS mode, use one of 'starttls', 'tls' or 'none'")
+	ErrInvalidFrom    = errors.New("invalid SMTP from address, use e.g. 'Domainator <noreply@example.com>'")
+)
+
+//go:embed email.html
+var emailHTML string
+
+//go:embed email.txt
+var emailText string
+
+var (
+	emailHTMLTmpl = htmltemplate.Must(htmltemplate.New("email.html").Parse(emailHTML))
+	emailTextTmpl = template.Must(template.New("email.txt").Parse(emailText))
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender, with an optional display name, e.g. "Domainator <noreply@example.com>".
+	From string
+	// TLSMode is one of "starttls", "tls" (implicit TLS) or "none".
+	TLSMode string
+}
+
+type EmailNotifier struct {
+	Timeout time.Duration
+	config  SMTPConfig
+	from    *mail.Address
+	appURL  string
+	signer  *signer.Signer
+}
+
+func NewEmailer(config SMTPConfig, appURL string, s *signer.Signer) (*EmailNotifier, error) {
+	switch config.TLSMode {
+	case "starttls", "tls", "none":
+	default:
+		return nil, ErrInvalidTLSMode
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, ErrInvalidFrom
+	}
+
+	return &EmailNotifier{
+		Timeout: 10 * time.Second,
+		config:  config,
+		from:    from,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+		signer:  s,
+	}, nil
+}
+
+type emailData struct {
+	Domain         string
+	Status         string
+	Hours          int
+	Message        string
+	Details        bool
+	AckURL         string
+	DashboardURL   string
+	UnsubscribeURL string
+}
+
+func (en *EmailNotifier) Notify(to string, notification Notification) error {
+	msg, err := en.buildMessage(to, notification)
+	if err != nil {
+		return err
+	}
+	return en.send(to, msg)
+}
+
+// UnsubscribeURL returns a signed link that disables the user's email notifications to the address.
+func (en *EmailNotifier) UnsubscribeURL(userID string, address string) string {
+	token := en.signer.Sign(UnsubscribePurpose, UnsubscribeValue(userID, address), time.Time{})
+	return en.appURL + "/unsubscribe?token=" + token
+}
+
+// UnsubscribeValue is the value signed in unsubscribe tokens.
+func UnsubscribeValue(userID string, address string) string {
+	return userID + ":" + address
+}
+
+// ParseUnsubscribeValue returns the user ID and the address of a verified unsubscribe token value.
+func ParseUnsubscribeValue(value string) (string, string) {
+	userID, address, _ := strings.Cut(value, ":")
+	return userID, address
+}
+
+// ConfirmValue is the value signed in the tokens that confirm an email channel.
+func ConfirmValue(userID string, channelID string) string {
+	return userID + ":" + channelID
+}
+
+// ParseConfirmValue returns the user ID and the channel ID of a verified confirmation token value.
+func ParseConfirmValue(value string) (string, string) {
+	userID, channelID, _ := strings.Cut(value, ":")
+	return userID, channelID
+}
+
+// SendConfirmation asks the address to confirm it wants the notifications of the channel,
+// nothing else is sent to it until the link is followed.
+func (en *EmailNotifier) SendConfirmation(to string, userID string, channelID string) error {
+	token := en.signer.Sign(ConfirmPurpose, ConfirmValue(userID, channelID), time.Now().Add(confirmTTL))
+	link := en.appURL + "/channel/confirm?token=" + token
+
+	body := fmt.Sprintf(
+		"A Domainator user wants to send the notifications about their TLS certificates to this address.\r\n\r\n"+
+			"Confirm that you want to receive them, the link expires in 7 days:\r\n%s\r\n\r\n"+
+			"If you don't know what this is about, ignore this email and nothing else will be sent to you.\r\n",
+		link,
+	)
+
+	headers := []struct{ key, value string }{
+		{"From", en.from.String()},
+		{"To", to},
+		{"Subject", "Domainator: confirm your email address"},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", common.NewID().String(), en.config.Host)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+	}
+
+	msg := new(bytes.Buffer)
+	for _, h := range headers {
+		fmt.Fprintf(msg, "%s: %s\r\n", h.key, h.value)
+	}
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	return en.send(to, msg.Bytes())
+}
+
+// buildMessage creates a multipart (plain text and HTML) message,
+// including the headers for one-click unsubscribe (RFC 8058).
+func (en *EmailNotifier) buildMessage(to string, notification Notification) ([]byte, error) {
+	data := emailData{
+		Domain:         notification.Domain,
+		Status:         notification.Status,
+		Hours:          notification.Hours,
+		Message:        notification.Message,
+		Details:        notification.Status != StatusDigest,
+		AckURL:         notification.AckURL,
+		DashboardURL:   en.appURL + "/dashboard",
+		UnsubscribeURL: en.UnsubscribeURL(notification.UserID, to),
+	}
+
+	textBody := new(bytes.Buffer)
+	err := emailTextTmpl.Execute(textBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	htmlBody := new(bytes.Buffer)
+	err = emailHTMLTmpl.Execute(htmlBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=utf-8", textBody.Bytes()},
+		{"text/html; charset=utf-8", htmlBody.Bytes()},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		_, err = qp.Write(part.content)
+		if err != nil {
+			return nil, err
+		}
+		err = qp.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = mw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf("Domainator: %s %s", notification.Domain, notification.Status)
+	if notification.Status == StatusDigest {
+		subject = "Domainator: " + notification.Domain
+	}
+	headers := []struct{ key, value string }{
+		{"From", en.from.String()},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", common.NewID().String(), en.config.Host)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+		{"List-Unsubscribe", "<" + data.UnsubscribeURL + ">"},
+		{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"},
+	}
+
+	msg := new(bytes.Buffer)
+	for _, h := range headers {
+		fmt.Fprintf(msg, "%s: %s\r\n", h.key, h.value)
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+func (en *EmailNotifier) send(to string, msg []byte) error {
+	addr := net.JoinHostPort(en.config.Host, strconv.Itoa(en.config.Port))
+	tlsConfig := &tls.Config{ServerName: en.config.Host}
+	dialer := &net.Dialer{Timeout: en.Timeout}
+
+	var conn net.Conn
+	var err error
+	if en.config.TLSMode == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = conn.SetDeadline(time.Now().Add(en.Timeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, en.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if en.config.TLSMode == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		err = c.StartTLS(tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	if en.config.Username != "" {
+		err = c.Auth(smtp.PlainAuth("", en.config.Username, en.config.Password, en.config.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	// The envelope takes the bare address, the display name is only in the From header.
+	err = c.Mail(en.from.Address)
+	if err != nil {
+		return err
+	}
+
+	err = c.Rcpt(to)
+	if err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,202 @@
+package notifier
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/germandv/domainator/internal/signer"
+)
+
+const secret = "4yxfIPahS5s15puGIDIDFqVSm09mKkyH"
+
+func TestEmailer(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go fakeSMTPServer(ln, received)
+
+	port, _ := strconv.Atoi(strings.Split(ln.Addr().String(), ":")[1])
+	s := signer.New([]byte(secret))
+	emailer, err := NewEmailer(SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "Domainator <noreply@domainator.dev>",
+		TLSMode: "none",
+	}, "http://localhost:4000/", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID := "018ec52b-dd69-7df4-b8e7-edcdc9a3a891"
+	err = emailer.Notify("user@example.com", Notification{
+		UserID: userID,
+		Domain: "example.com",
+		Status: "expires soon",
+		Hours:  48,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error sending email, got %v", err)
+	}
+
+	msg := <-received
+	for _, want := range []string{
+		"MAIL FROM:<noreply@domainator.dev>",
+		`From: "Domainator" <noreply@domainator.dev>`,
+		"To: user@example.com",
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+		"Domain: example.com",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+
+	i := strings.Index(msg, "List-Unsubscribe: <")
+	if i == -1 {
+		t.Fatalf("List-Unsubscribe header not found")
+	}
+	link := msg[i+len("List-Unsubscribe: <"):]
+	link = link[:strings.Index(link, ">")]
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "localhost:4000" || u.Path != "/unsubscribe" {
+		t.Errorf("Unexpected unsubscribe link %q", link)
+	}
+	got, err := s.Verify(UnsubscribePurpose, u.Query().Get("token"))
+	if err != nil || got != UnsubscribeValue(userID, "user@example.com") {
+		t.Errorf("Expected unsubscribe token for %q, got %q (err: %v)", userID, got, err)
+	}
+}
+
+func TestEmailerConfirmation(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go fakeSMTPServer(ln, received)
+
+	port, _ := strconv.Atoi(strings.Split(ln.Addr().String(), ":")[1])
+	s := signer.New([]byte(secret))
+	emailer, err := NewEmailer(SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    port,
+		From:    "noreply@domainator.dev",
+		TLSMode: "none",
+	}, "http://localhost:4000", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID := "018ec52b-dd69-7df4-b8e7-edcdc9a3a891"
+	channelID := "018ec52b-dd69-7df4-b8e7-edcdc9a3a892"
+	err = emailer.SendConfirmation("someone@example.com", userID, channelID)
+	if err != nil {
+		t.Fatalf("Expected no error sending email, got %v", err)
+	}
+
+	msg := <-received
+	if !strings.Contains(msg, "To: someone@example.com") {
+		t.Errorf("Expected message to someone@example.com, got:\n%s", msg)
+	}
+
+	prefix := "http://localhost:4000/channel/confirm?token="
+	i := strings.Index(msg, prefix)
+	if i == -1 {
+		t.Fatalf("Confirmation link not found in:\n%s", msg)
+	}
+	token := strings.Fields(msg[i+len(prefix):])[0]
+	got, err := s.Verify(ConfirmPurpose, token)
+	if err != nil || got != ConfirmValue(userID, channelID) {
+		t.Errorf("Expected confirmation token for %q, got %q (err: %v)", channelID, got, err)
+	}
+}
+
+func TestEmailerInvalidTLSMode(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEmailer(SMTPConfig{TLSMode: "ssl"}, "http://localhost", signer.New([]byte(secret)))
+	if !errors.Is(err, ErrInvalidTLSMode) {
+		t.Errorf("Expected ErrInvalidTLSMode, got %v", err)
+	}
+}
+
+func TestEmailerInvalidFrom(t *testing.T) {
+	t.Parallel()
+
+	for _, from := range []string{"", "Domainator", "Domainator <noreply>", "noreply@localhost>"} {
+		_, err := NewEmailer(SMTPConfig{TLSMode: "none", From: from}, "http://localhost", signer.New([]byte(secret)))
+		if !errors.Is(err, ErrInvalidFrom) {
+			t.Errorf("Expected ErrInvalidFrom for %q, got %v", from, err)
+		}
+	}
+}
+
+// fakeSMTPServer accepts a single connection, speaks just enough SMTP
+// to receive one message and sends the MAIL command and the DATA section to the channel.
+func fakeSMTPServer(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	write := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+	write("220 localhost ESMTP")
+	mailFrom := ""
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			write("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL"):
+			mailFrom = line
+			write("250 OK")
+		case strings.HasPrefix(cmd, "DATA"):
+			write("354 go ahead")
+			data := new(strings.Builder)
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			received <- mailFrom + data.String()
+			write("250 OK")
+		case strings.HasPrefix(cmd, "QUIT"):
+			write("221 bye")
+			return
+		default:
+			write("250 OK")
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Event groups notification statuses, so that users can customise the message of each group.
+type Event string
+
+const (
+	EventAny      Event = "any"
+	EventExpiring Event = "expiring"
+	EventExpired  Event = "expired"
+	EventError    Event = "error"
+	EventResolved Event = "resolved"
+)
+
+// Events lists every event, in the order they are offered to users.
+var Events = []Event{EventAny, EventExpiring, EventExpired, EventError, EventResolved}
+
+var ErrInvalidEvent = errors.New("invalid event, use one of 'any', 'expiring', 'expired', 'error' or 'resolved'")
+
+func ParseEvent(event string) (Event, error) {
+	e := Event(strings.TrimSpace(event))
+	for _, valid := range Events {
+		if e == valid {
+			return e, nil
+		}
+	}
+	return "", ErrInvalidEvent
+}
+
+// EventOf returns the event a notification status belongs to.
+func EventOf(status string) Event {
+	switch status {
+	case "expires soon", "expires today":
+		return EventExpiring
+	case "expired":
+		return EventExpired
+	case StatusResolved, "OK":
+		return EventResolved
+	default:
+		return EventError
+	}
+}
+
+// Label is the human friendly name of the event.
+func (e Event) Label() string {
+	switch e {
+	case EventExpiring:
+		return "Expiring soon"
+	case EventExpired:
+		return "Expired"
+	case EventError:
+		return "Connection error"
+	case EventResolved:
+		return "Resolved"
+	default:
+		return "Any event"
+	}
+}
+
+// SampleNotification returns an example notification for the event, used to preview messages.
+func SampleNotification(event Event) Notification {
+	n := Notification{
+		ID:        "018ec52b-dd69-7df4-b8e7-edcdc9a3a891",
+		Domain:    "example.com",
+		Status:    "expires soon",
+		Hours:     50,
+		Issuer:    "Let's Encrypt",
+		ExpiresAt: time.Now().Add(50 * time.Hour),
+		Tags:      []string{"prod"},
+	}
+
+	switch event {
+	case EventExpired:
+		n.Status = "expired"
+		n.Hours = -5
+		n.ExpiresAt = time.Now().Add(-5 * time.Hour)
+	case EventError:
+		n.Status = "Cannot Connect"
+		n.Hours = 0
+	case EventResolved:
+		n.Status = StatusResolved
+		n.Hours = 2160
+		n.ExpiresAt = time.Now().Add(2160 * time.Hour)
+	}
+
+	return n
+}
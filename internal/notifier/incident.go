@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// IncidentProvider is the incident management tool notifications are sent to.
+type IncidentProvider string
+
+const (
+	IncidentProviderNone      IncidentProvider = ""
+	IncidentProviderPagerDuty IncidentProvider = "pagerduty"
+	IncidentProviderOpsgenie  IncidentProvider = "opsgenie"
+)
+
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com"
+)
+
+var ErrInvalidIncidentProvider = errors.New("invalid incident provider, use one of 'pagerduty' or 'opsgenie'")
+
+func ParseIncidentProvider(provider string) (IncidentProvider, error) {
+	switch p := IncidentProvider(strings.TrimSpace(provider)); p {
+	case IncidentProviderNone, IncidentProviderPagerDuty, IncidentProviderOpsgenie:
+		return p, nil
+	default:
+		return IncidentProviderNone, ErrInvalidIncidentProvider
+	}
+}
+
+// IncidentConfig holds the base URLs of the incident management APIs,
+// they can be pointed to local stand-ins for testing.
+type IncidentConfig struct {
+	PagerDutyURL string
+	OpsgenieURL  string
+}
+
+// NewIncidentNotifier returns the notifier for the given provider,
+// the `to` argument of Notify is the routing key (PagerDuty) or API key (Opsgenie).
+func NewIncidentNotifier(provider IncidentProvider, config IncidentConfig, appURL string) Notifier {
+	if provider == IncidentProviderOpsgenie {
+		return NewOpsgenier(config.OpsgenieURL, appURL)
+	}
+	return NewPagerDutier(config.PagerDutyURL, appURL)
+}
+
+// dedupKey is stable for a given certificate, so that consecutive notifications
+// update the same incident and a resolution closes it.
+func dedupKey(notification Notification) string {
+	if notification.ID != "" {
+		return "domainator-" + notification.ID
+	}
+	return "domainator-" + notification.Domain
+}
@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordedRequest struct {
+	path    string
+	auth    string
+	payload map[string]any
+}
+
+func recorder(t *testing.T, requests chan<- recordedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]any{}
+		err := json.Unmarshal(body, &payload)
+		if err != nil {
+			t.Errorf("invalid JSON payload: %v", err)
+		}
+		requests <- recordedRequest{path: r.URL.RequestURI(), auth: r.Header.Get("Authorization"), payload: payload}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+func TestPagerDutier(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan recordedRequest, 2)
+	ts := recorder(t, requests)
+	defer ts.Close()
+
+	n := NewIncidentNotifier(IncidentProviderPagerDuty, IncidentConfig{PagerDutyURL: ts.URL}, "http://localhost:4000")
+	notification := Notification{ID: "018ec52b-dd69-7df4-b8e7-edcdc9a3a891", Domain: "example.com", Status: "expired"}
+
+	err := n.Notify("routing-key", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trigger := <-requests
+
+	notification.Status = StatusResolved
+	err = n.Notify("routing-key", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := <-requests
+
+	if trigger.path != "/v2/enqueue" || resolve.path != "/v2/enqueue" {
+		t.Errorf("Unexpected paths %q and %q", trigger.path, resolve.path)
+	}
+	if trigger.payload["event_action"] != "trigger" || resolve.payload["event_action"] != "resolve" {
+		t.Errorf("Unexpected actions %v and %v", trigger.payload["event_action"], resolve.payload["event_action"])
+	}
+	if trigger.payload["dedup_key"] != resolve.payload["dedup_key"] {
+		t.Errorf("Dedup keys should match, got %v and %v", trigger.payload["dedup_key"], resolve.payload["dedup_key"])
+	}
+	if trigger.payload["routing_key"] != "routing-key" {
+		t.Errorf("Unexpected routing key %v", trigger.payload["routing_key"])
+	}
+	if trigger.payload["payload"].(map[string]any)["severity"] != "critical" {
+		t.Errorf("Expected critical severity, got %v", trigger.payload["payload"])
+	}
+}
+
+func TestOpsgenier(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan recordedRequest, 2)
+	ts := recorder(t, requests)
+	defer ts.Close()
+
+	n := NewIncidentNotifier(IncidentProviderOpsgenie, IncidentConfig{OpsgenieURL: ts.URL}, "http://localhost:4000")
+	notification := Notification{ID: "018ec52b-dd69-7df4-b8e7-edcdc9a3a891", Domain: "example.com", Status: "expires soon"}
+
+	err := n.Notify("api-key", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := <-requests
+
+	notification.Status = StatusResolved
+	err = n.Notify("api-key", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closing := <-requests
+
+	if create.path != "/v2/alerts" || create.auth != "GenieKey api-key" {
+		t.Errorf("Unexpected create request %q (auth %q)", create.path, create.auth)
+	}
+	if create.payload["priority"] != "P3" {
+		t.Errorf("Expected P3 priority, got %v", create.payload["priority"])
+	}
+	wantClose := "/v2/alerts/" + create.payload["alias"].(string) + "/close?identifierType=alias"
+	if closing.path != wantClose {
+		t.Errorf("Expected close request to %q, got %q", wantClose, closing.path)
+	}
+}
@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+var (
+	ErrInvalidMatrixTarget = errors.New("matrix target must be the homeserver URL followed by the room ID, e.g. https://matrix.example.org/!abc123:example.org")
+	matrixRoomRegex        = regexp.MustCompile(`^![A-Za-z0-9._=/+-]+:[A-Za-z0-9.-]+(:[0-9]{1,5})?$`)
+)
+
+// ParseMatrixTarget splits a Matrix target, the homeserver URL followed by the room ID, into both parts.
+func ParseMatrixTarget(target string) (string, string, error) {
+	i := strings.LastIndex(target, "/!")
+	if i == -1 {
+		return "", "", ErrInvalidMatrixTarget
+	}
+
+	homeserver, err := common.ParseURL(target[:i])
+	if err != nil {
+		return "", "", ErrInvalidMatrixTarget
+	}
+
+	room := target[i+1:]
+	if !matrixRoomRegex.MatchString(room) {
+		return "", "", ErrInvalidMatrixTarget
+	}
+
+	return strings.TrimSuffix(homeserver.String(), "/"), room, nil
+}
+
+// MatrixNotifier posts to a Matrix room with the access token of a (bot) account that joined it.
+type MatrixNotifier struct {
+	Timeout time.Duration
+	token   string
+	appURL  string
+}
+
+func NewMatrixNotifier(token string, appURL string) *MatrixNotifier {
+	return &MatrixNotifier{
+		Timeout: 5 * time.Second,
+		token:   token,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+// MatrixMessage is an m.room.message event with an HTML body and its plain text fallback.
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// Notify sends the notification to the Matrix target, see ParseMatrixTarget.
+func (mn *MatrixNotifier) Notify(to string, notification Notification) error {
+	homeserver, room, err := ParseMatrixTarget(to)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(MatrixMessage{
+		// Notices are the kind of message meant for bots, clients don't answer to them.
+		MsgType:       "m.notice",
+		Body:          MatrixText(notification, mn.appURL),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: MatrixHTML(notification, mn.appURL),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The transaction ID makes retries of the same request idempotent, every notification is a new one.
+	txnID := fmt.Sprintf("domainator-%d", time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s", homeserver, url.PathEscape(room), txnID)
+	headers := map[string]string{"Authorization": "Bearer " + mn.token}
+
+	_, err = sendJSON("PUT", mn.Timeout, endpoint, body, headers, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending matrix msg: %w", err)
+	}
+
+	return nil
+}
+
+// MatrixText is the plain text version of the notification, shown by clients without HTML support.
+func MatrixText(n Notification, appURL string) string {
+	lines := []string{}
+	if n.Domain != "" {
+		lines = append(lines, n.Domain)
+	}
+	if n.Message != "" {
+		lines = append(lines, n.Message)
+	} else {
+		lines = append(lines, plainDetails(n)...)
+	}
+	lines = append(lines, "", "Dashboard: "+appURL+"/dashboard")
+	if n.AckURL != "" {
+		lines = append(lines, "Acknowledge or snooze: "+n.AckURL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MatrixHTML formats the notification with the subset of HTML supported by Matrix clients.
+func MatrixHTML(n Notification, appURL string) string {
+	b := strings.Builder{}
+	if n.Domain != "" {
+		b.WriteString("<strong>" + html.EscapeString(n.Domain) + "</strong><br>")
+	}
+
+	if n.Message != "" {
+		b.WriteString(strings.ReplaceAll(html.EscapeString(n.Message), "\n", "<br>"))
+	} else {
+		b.WriteString(strings.Join(escapeAll(plainDetails(n)), "<br>"))
+	}
+
+	b.WriteString(`<br><br><a href="` + html.EscapeString(appURL+"/dashboard") + `">Open dashboard</a>`)
+	if n.AckURL != "" {
+		b.WriteString(` · <a href="` + html.EscapeString(n.AckURL) + `">Acknowledge or snooze</a>`)
+	}
+	return b.String()
+}
+
+func escapeAll(lines []string) []string {
+	escaped := make([]string, len(lines))
+	for i, l := range lines {
+		escaped[i] = html.EscapeString(l)
+	}
+	return escaped
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMatrixTarget(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input      string
+		homeserver string
+		room       string
+		err        error
+	}{
+		{"https://matrix.example.org/!abc123:example.org", "https://matrix.example.org", "!abc123:example.org", nil},
+		{"https://example.org/matrix/!abc:example.org:8448", "https://example.org/matrix", "!abc:example.org:8448", nil},
+		{"https://matrix.example.org/#alias:example.org", "", "", ErrInvalidMatrixTarget},
+		{"http://matrix.example.org/!abc123:example.org", "", "", ErrInvalidMatrixTarget},
+		{"https://matrix.example.org/!abc123", "", "", ErrInvalidMatrixTarget},
+	}
+
+	for _, tc := range tt {
+		homeserver, room, err := ParseMatrixTarget(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if homeserver != tc.homeserver || room != tc.room {
+			t.Errorf("Expected %q and %q, got %q and %q", tc.homeserver, tc.room, homeserver, room)
+		}
+	}
+}
+
+func TestMatrixHTML(t *testing.T) {
+	t.Parallel()
+
+	n := Notification{ID: "1", Domain: "example.com", Status: "expired", Message: "<b>Renew</b>\nnow"}
+	got := MatrixHTML(n, "https://app.example.com")
+
+	want := `<strong>example.com</strong><br>&lt;b&gt;Renew&lt;/b&gt;<br>now<br><br><a href="https://app.example.com/dashboard">Open dashboard</a>`
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if text := MatrixText(n, "https://app.example.com"); !strings.HasPrefix(text, "example.com\n<b>Renew</b>\nnow") {
+		t.Errorf("Unexpected plain text %q", text)
+	}
+}
@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MattermostNotifier struct {
+	Timeout time.Duration
+	appURL  string
+}
+
+func NewMattermoster(appURL string) Notifier {
+	return &MattermostNotifier{
+		Timeout: 5 * time.Second,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type MattermostMessage struct {
+	Username    string                 `json:"username,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments"`
+}
+
+type MattermostAttachment struct {
+	Fallback  string            `json:"fallback"`
+	Color     string            `json:"color"`
+	Title     string            `json:"title"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []MattermostField `json:"fields"`
+}
+
+type MattermostField struct {
+	Short bool   `json:"short"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+func (mn *MattermostNotifier) Notify(to string, notification Notification) error {
+	attachment := MattermostAttachment{
+		Fallback:  fmt.Sprintf("%s: %s", notification.Domain, notification.Status),
+		Color:     fmt.Sprintf("#%06X", statusColor(notification.Status)),
+		Title:     notification.Domain,
+		TitleLink: mn.appURL + "/dashboard",
+		Text:      notification.Message,
+		Fields: []MattermostField{
+			{Short: true, Title: "Status", Value: notification.Status},
+			{Short: true, Title: "Hours", Value: strconv.Itoa(notification.Hours)},
+		},
+	}
+	if notification.Issuer != "" {
+		attachment.Fields = append(attachment.Fields, MattermostField{Short: true, Title: "Issuer", Value: notification.Issuer})
+	}
+	if notification.AckURL != "" {
+		attachment.Fields = append(attachment.Fields, MattermostField{Title: "Incident", Value: "[Acknowledge or snooze](" + notification.AckURL + ")"})
+	}
+
+	payload := MattermostMessage{Username: "Domainator", Attachments: []MattermostAttachment{attachment}}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(mn.Timeout, to, body, nil, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending mattermost msg: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	maxTemplateLength = 2000
+	maxMessageLength  = 4000
+)
+
+var (
+	ErrInvalidTemplate = errors.New("invalid message template")
+	ErrMessageTooLong  = fmt.Errorf("message cannot be longer than %d characters", maxMessageLength)
+)
+
+// MessageData is the set of variables available to message templates.
+type MessageData struct {
+	Domain       string
+	Status       string
+	Event        string
+	Severity     string
+	DaysLeft     int
+	HoursLeft    int
+	Issuer       string
+	ExpiresAt    string
+	DashboardURL string
+	AckURL       string
+	Tags         []string
+}
+
+// MessageVariables documents the variables of MessageData for users writing templates.
+var MessageVariables = []struct {
+	Name        string
+	Description string
+}{
+	{".Domain", "the monitored domain, e.g. example.com"},
+	{".Status", "the status, e.g. expires soon, expired, Cannot Connect, resolved"},
+	{".Event", "one of expiring, expired, error or resolved"},
+	{".Severity", "one of info, warning or critical"},
+	{".DaysLeft", "whole days until the certificate expires, negative once expired"},
+	{".HoursLeft", "hours until the certificate expires"},
+	{".Issuer", "the certificate issuer"},
+	{".ExpiresAt", "the expiration date, e.g. 2024-05-01"},
+	{".DashboardURL", "the link to your Domainator dashboard"},
+	{".AckURL", "the link to acknowledge or snooze an escalating incident, empty otherwise"},
+	{".Tags", "the tags of the domain, use {{join .Tags \", \"}}"},
+}
+
+// messageFuncs are the only functions available to templates besides the text/template builtins,
+// printf is replaced so that huge widths or precisions cannot be used to allocate memory.
+var messageFuncs = template.FuncMap{
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"join":   strings.Join,
+	"printf": safePrintf,
+}
+
+var longNumberRegex = regexp.MustCompile(`%[^a-zA-Z%]*[0-9]{4,}`)
+
+func safePrintf(format string, args ...any) (string, error) {
+	if longNumberRegex.MatchString(format) {
+		return "", errors.New("printf widths and precisions are limited to 3 digits")
+	}
+	return fmt.Sprintf(format, args...), nil
+}
+
+func NewMessageData(n Notification, appURL string) MessageData {
+	data := MessageData{
+		Domain:       n.Domain,
+		Status:       n.Status,
+		Event:        string(EventOf(n.Status)),
+		Severity:     string(SeverityOf(n.Status)),
+		DaysLeft:     n.Hours / 24,
+		HoursLeft:    n.Hours,
+		Issuer:       n.Issuer,
+		DashboardURL: strings.TrimSuffix(appURL, "/") + "/dashboard",
+		AckURL:       n.AckURL,
+		Tags:         n.Tags,
+	}
+	if !n.ExpiresAt.IsZero() {
+		data.ExpiresAt = n.ExpiresAt.UTC().Format(time.DateOnly)
+	}
+	if data.Tags == nil {
+		data.Tags = []string{}
+	}
+	return data
+}
+
+// ParseMessageTemplate parses a user provided template, rejecting the constructs that
+// could be abused: defining or invoking other templates and ranging over anything but .Tags.
+func ParseMessageTemplate(body string) (*template.Template, error) {
+	if len(body) > maxTemplateLength {
+		return nil, fmt.Errorf("%w: cannot be longer than %d characters", ErrInvalidTemplate, maxTemplateLength)
+	}
+
+	tmpl, err := template.New("message").Funcs(messageFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("%w: defining templates is not allowed", ErrInvalidTemplate)
+	}
+
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return tmpl, nil
+	}
+
+	err = checkNode(tmpl.Tree.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			err := checkNode(child)
+			if err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		return fmt.Errorf("%w: invoking templates is not allowed", ErrInvalidTemplate)
+	case *parse.RangeNode:
+		if !rangesOverTags(n.Pipe) {
+			return fmt.Errorf("%w: only .Tags can be ranged over", ErrInvalidTemplate)
+		}
+		return checkBranch(&n.BranchNode)
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	}
+	return nil
+}
+
+func checkBranch(b *parse.BranchNode) error {
+	err := checkNode(b.List)
+	if err != nil {
+		return err
+	}
+	if b.ElseList != nil {
+		return checkNode(b.ElseList)
+	}
+	return nil
+}
+
+func rangesOverTags(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	field, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	return ok && len(field.Ident) == 1 && field.Ident[0] == "Tags"
+}
+
+// limitedBuffer fails writes once the limit is reached, which aborts the template execution.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, ErrMessageTooLong
+	}
+	return b.Buffer.Write(p)
+}
+
+// RenderMessage renders a user provided template for the notification.
+func RenderMessage(body string, n Notification, appURL string) (string, error) {
+	tmpl, err := ParseMessageTemplate(body)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &limitedBuffer{limit: maxMessageLength}
+	err = tmpl.Execute(buf, NewMessageData(n, appURL))
+	if err != nil {
+		if errors.Is(err, ErrMessageTooLong) {
+			return "", ErrMessageTooLong
+		}
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
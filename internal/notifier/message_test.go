@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMessage(t *testing.T) {
+	t.Parallel()
+
+	n := Notification{
+		Domain:    "example.com",
+		Status:    "expires soon",
+		Hours:     50,
+		Issuer:    "Let's Encrypt",
+		ExpiresAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Tags:      []string{"prod", "eu"},
+	}
+
+	tt := []struct {
+		name string
+		body string
+		want string
+		err  error
+	}{
+		{"variables", "{{.Domain}} expires in {{.DaysLeft}} days ({{.ExpiresAt}}, {{.Issuer}})", "example.com expires in 2 days (2024-05-01, Let's Encrypt)", nil},
+		{"dashboard", "{{.DashboardURL}}", "http://localhost:4000/dashboard", nil},
+		{"funcs", `{{upper .Severity}} {{join .Tags ", "}}`, "WARNING prod, eu", nil},
+		{"range tags", "{{range .Tags}}#{{.}} {{end}}", "#prod #eu", nil},
+		{"conditionals", `{{if eq .Event "expiring"}}soon{{else}}other{{end}}`, "soon", nil},
+		{"printf", `{{printf "%3d" .DaysLeft}}`, "2", nil},
+		{"unknown variable", "{{.Secret}}", "", ErrInvalidTemplate},
+		{"syntax error", "{{.Domain", "", ErrInvalidTemplate},
+		{"define", `{{define "x"}}x{{end}}`, "", ErrInvalidTemplate},
+		{"template", `{{template "message"}}`, "", ErrInvalidTemplate},
+		{"range int", "{{range 1000000000}}{{end}}", "", ErrInvalidTemplate},
+		{"huge printf", `{{printf "%999999999d" 1}}`, "", ErrInvalidTemplate},
+		{"too long template", strings.Repeat("a", maxTemplateLength+1), "", ErrInvalidTemplate},
+		{"too long message", `{{range .Tags}}{{printf "%999s" .}}{{printf "%999s" .}}{{printf "%999s" .}}{{end}}`, "", ErrMessageTooLong},
+	}
+
+	for _, tc := range tt {
+		got, err := RenderMessage(tc.body, n, "http://localhost:4000/")
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected error %v, got %v", tc.name, tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestEventOf(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		status string
+		want   Event
+	}{
+		{"expires soon", EventExpiring},
+		{"expires today", EventExpiring},
+		{"expired", EventExpired},
+		{StatusResolved, EventResolved},
+		{"Hostname Mismatch", EventError},
+	}
+
+	for _, tc := range tt {
+		got := EventOf(tc.status)
+		if got != tc.want {
+			t.Errorf("Expected %q for status %q, got %q", tc.want, tc.status, got)
+		}
+	}
+}
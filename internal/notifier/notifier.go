@@ -1,13 +1,107 @@
 package notifier
 
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StatusResolved is the status of notifications sent when
+// a certificate that had problems is healthy again.
+const StatusResolved = "resolved"
+
 type Notification struct {
-	ID     string
-	UserID string
-	Domain string
-	Status string
-	Hours  int
+	ID        string
+	UserID    string
+	Domain    string
+	Status    string
+	Hours     int
+	Issuer    string
+	ExpiresAt time.Time
+	Tags      []string
+	// Message, rendered from a user template, replaces the default text when set.
+	Message string
+	// AckURL, set for notifications of an escalating incident, acknowledges or snoozes it.
+	AckURL string
+	// Receipt, when set, is filled with the response of the receiver.
+	Receipt *Receipt `json:"-"`
+}
+
+// Receipt is the response to a notification delivered over HTTP.
+type Receipt struct {
+	StatusCode int
+	Body       string
 }
 
 type Notifier interface {
 	Notify(to string, notification Notification) error
 }
+
+// StatusDigest is the status of digest reports, which summarise the state of all the certs of a user.
+const StatusDigest = "digest"
+
+// maxReceiptLength caps the response body kept in a Receipt.
+const maxReceiptLength = 1000
+
+// StatusChannelDisabled is the status of notifications telling the user
+// that one of their channels was disabled because deliveries kept failing.
+const StatusChannelDisabled = "channel disabled"
+
+// postJSON sends the body to the given URL and returns an error for non 2xx responses.
+// When receipt is not nil, it's filled with the status code and body of the response.
+func postJSON(timeout time.Duration, url string, body []byte, headers map[string]string, receipt *Receipt) error {
+	_, err := postJSONResponse(timeout, url, body, headers, receipt)
+	return err
+}
+
+// postJSONResponse is postJSON returning the whole body of the response, for APIs that answer with data.
+func postJSONResponse(timeout time.Duration, url string, body []byte, headers map[string]string, receipt *Receipt) ([]byte, error) {
+	return sendJSON(http.MethodPost, timeout, url, body, headers, receipt)
+}
+
+// sendJSON sends the body with the given method, for APIs that don't take a POST.
+func sendJSON(method string, timeout time.Duration, url string, body []byte, headers map[string]string, receipt *Receipt) ([]byte, error) {
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	buf := new(bytes.Buffer)
+	_, err = buf.ReadFrom(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt != nil {
+		receipt.StatusCode = resp.StatusCode
+		receipt.Body = truncate(buf.String(), maxReceiptLength)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("error (%d) sending request: %s", resp.StatusCode, buf.String())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// truncate shortens s to at most n bytes, for APIs that limit field lengths.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
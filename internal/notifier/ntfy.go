@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+// maxNtfyLength is the default limit of the message of a ntfy notification.
+const maxNtfyLength = 4096
+
+var (
+	ErrInvalidNtfyTarget = errors.New("ntfy target must be the URL of a topic, e.g. https://ntfy.sh/my-alerts")
+	ntfyTopicRegex       = regexp.MustCompile(`^[-_A-Za-z0-9]{1,64}$`)
+	// ntfyEscaper escapes the characters that would turn a domain or a message into markdown.
+	ntfyEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`)
+)
+
+// ParseNtfyTarget splits the URL of a ntfy topic into the URL of the server and the topic.
+func ParseNtfyTarget(target string) (string, string, error) {
+	parsed, err := common.ParseURL(target)
+	if err != nil {
+		return "", "", ErrInvalidNtfyTarget
+	}
+
+	u, err := url.Parse(parsed.String())
+	if err != nil || u.Host == "" {
+		return "", "", ErrInvalidNtfyTarget
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	topic := path[i+1:]
+	if !ntfyTopicRegex.MatchString(topic) || u.RawQuery != "" {
+		return "", "", ErrInvalidNtfyTarget
+	}
+
+	return u.Scheme + "://" + u.Host + path[:i], topic, nil
+}
+
+// NtfyNotifier publishes to a topic of a ntfy server (https://ntfy.sh or self-hosted).
+type NtfyNotifier struct {
+	Timeout time.Duration
+	// token is the access token of protected topics, empty for public ones.
+	token  string
+	appURL string
+}
+
+func NewNtfyNotifier(token string, appURL string) *NtfyNotifier {
+	return &NtfyNotifier{
+		Timeout: 5 * time.Second,
+		token:   token,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type NtfyMessage struct {
+	Topic    string       `json:"topic"`
+	Title    string       `json:"title"`
+	Message  string       `json:"message"`
+	Markdown bool         `json:"markdown"`
+	Priority int          `json:"priority"`
+	Tags     []string     `json:"tags"`
+	Click    string       `json:"click"`
+	Actions  []NtfyAction `json:"actions,omitempty"`
+}
+
+type NtfyAction struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+	URL    string `json:"url"`
+}
+
+// Notify publishes the notification to the topic URL.
+func (nn *NtfyNotifier) Notify(to string, notification Notification) error {
+	server, topic, err := ParseNtfyTarget(to)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(NtfyPayload(topic, notification, nn.appURL))
+	if err != nil {
+		return err
+	}
+
+	var headers map[string]string
+	if nn.token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + nn.token}
+	}
+
+	// Publishing JSON is done to the root of the server, the topic is in the body.
+	err = postJSON(nn.Timeout, server+"/", body, headers, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending ntfy msg: %w", err)
+	}
+
+	return nil
+}
+
+// NtfyPayload formats the notification for ntfy, with markdown and a priority and emoji tag matching its severity.
+func NtfyPayload(topic string, n Notification, appURL string) NtfyMessage {
+	title := "Domainator"
+	if n.Domain != "" {
+		title = n.Domain + ": " + n.Status
+	}
+
+	message := ntfyEscaper.Replace(n.Message)
+	if n.Message == "" {
+		details := plainDetails(n)
+		for i, d := range details {
+			label, value, found := strings.Cut(d, ": ")
+			if found {
+				details[i] = "**" + label + ":** " + ntfyEscaper.Replace(value)
+			}
+		}
+		message = strings.Join(details, "  \n")
+	}
+
+	priority, tag := 3, "bell"
+	switch {
+	case EventOf(n.Status) == EventResolved:
+		priority, tag = 3, "white_check_mark"
+	case n.ID != "" && SeverityOf(n.Status) == SeverityCritical:
+		priority, tag = 5, "rotating_light"
+	case n.ID != "" && SeverityOf(n.Status) == SeverityWarning:
+		priority, tag = 4, "warning"
+	}
+
+	actions := []NtfyAction{}
+	if n.AckURL != "" {
+		actions = append(actions, NtfyAction{Action: "view", Label: "Acknowledge or snooze", URL: n.AckURL})
+	}
+
+	return NtfyMessage{
+		Topic:    topic,
+		Title:    title,
+		Message:  truncate(message, maxNtfyLength),
+		Markdown: true,
+		Priority: priority,
+		Tags:     []string{tag},
+		Click:    appURL + "/dashboard",
+		Actions:  actions,
+	}
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNtfyTarget(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input  string
+		server string
+		topic  string
+		err    error
+	}{
+		{"https://ntfy.sh/my-alerts", "https://ntfy.sh", "my-alerts", nil},
+		{"https://example.com/ntfy/my_alerts/", "https://example.com/ntfy", "my_alerts", nil},
+		{"https://ntfy.sh/", "", "", ErrInvalidNtfyTarget},
+		{"https://ntfy.sh/alerts?auth=x", "", "", ErrInvalidNtfyTarget},
+		{"ntfy.sh/alerts", "", "", ErrInvalidNtfyTarget},
+	}
+
+	for _, tc := range tt {
+		server, topic, err := ParseNtfyTarget(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if server != tc.server || topic != tc.topic {
+			t.Errorf("Expected %q and %q, got %q and %q", tc.server, tc.topic, server, topic)
+		}
+	}
+}
+
+func TestNtfyPayload(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		name     string
+		n        Notification
+		priority int
+		tag      string
+	}{
+		{"expired", Notification{ID: "1", Domain: "example.com", Status: "expired"}, 5, "rotating_light"},
+		{"expiring", Notification{ID: "1", Domain: "example.com", Status: "expires soon"}, 4, "warning"},
+		{"resolved", Notification{ID: "1", Domain: "example.com", Status: StatusResolved}, 3, "white_check_mark"},
+		{"digest", Notification{Status: StatusDigest, Message: "3 certificates"}, 3, "bell"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := NtfyPayload("alerts", tc.n, "https://app.example.com")
+			if got.Topic != "alerts" || !got.Markdown || got.Click != "https://app.example.com/dashboard" {
+				t.Errorf("Unexpected message %+v", got)
+			}
+			if got.Priority != tc.priority || got.Tags[0] != tc.tag {
+				t.Errorf("Expected priority %d with %q, got %d with %v", tc.priority, tc.tag, got.Priority, got.Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpsgenieNotifier speaks the Opsgenie Alerts API.
+type OpsgenieNotifier struct {
+	Timeout time.Duration
+	baseURL string
+	appURL  string
+}
+
+func NewOpsgenier(baseURL string, appURL string) Notifier {
+	return &OpsgenieNotifier{
+		Timeout: 5 * time.Second,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type OpsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity"`
+	Tags        []string          `json:"tags"`
+	Details     map[string]string `json:"details"`
+}
+
+type OpsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// Notify creates an alert, or closes it if the certificate is healthy again.
+func (on *OpsgenieNotifier) Notify(to string, notification Notification) error {
+	headers := map[string]string{"Authorization": "GenieKey " + to}
+	alias := dedupKey(notification)
+
+	if notification.Status == StatusResolved {
+		body, err := json.Marshal(OpsgenieClose{
+			Source: "Domainator",
+			Note:   fmt.Sprintf("TLS certificate for %s is healthy again", notification.Domain),
+		})
+		if err != nil {
+			return err
+		}
+
+		endpoint := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", on.baseURL, url.PathEscape(alias))
+		err = postJSON(on.Timeout, endpoint, body, headers, notification.Receipt)
+		if err != nil {
+			return fmt.Errorf("error closing opsgenie alert: %w", err)
+		}
+		return nil
+	}
+
+	priority := "P3"
+	if SeverityOf(notification.Status) == SeverityCritical {
+		priority = "P1"
+	}
+
+	details := map[string]string{
+		"status":     notification.Status,
+		"hours_left": strconv.Itoa(notification.Hours),
+		"issuer":     notification.Issuer,
+		"dashboard":  on.appURL + "/dashboard",
+	}
+	if !notification.ExpiresAt.IsZero() {
+		details["expires_at"] = notification.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	description := fmt.Sprintf("Domainator detected that the TLS certificate for %s has status %q.", notification.Domain, notification.Status)
+	if notification.Message != "" {
+		description = notification.Message
+	}
+
+	body, err := json.Marshal(OpsgenieAlert{
+		Message:     fmt.Sprintf("TLS certificate for %s: %s", notification.Domain, notification.Status),
+		Alias:       alias,
+		Description: description,
+		Priority:    priority,
+		Source:      "Domainator",
+		Entity:      notification.Domain,
+		Tags:        []string{"tls", "domainator"},
+		Details:     details,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(on.Timeout, on.baseURL+"/v2/alerts", body, headers, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error creating opsgenie alert: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PagerDutyNotifier speaks the PagerDuty Events API v2.
+type PagerDutyNotifier struct {
+	Timeout time.Duration
+	baseURL string
+	appURL  string
+}
+
+func NewPagerDutier(baseURL string, appURL string) Notifier {
+	return &PagerDutyNotifier{
+		Timeout: 5 * time.Second,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type PagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *PagerDutyPayload `json:"payload,omitempty"`
+	Links       []PagerDutyLink   `json:"links,omitempty"`
+}
+
+type PagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Component     string         `json:"component"`
+	CustomDetails map[string]any `json:"custom_details"`
+}
+
+type PagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Notify triggers an incident, or resolves it if the certificate is healthy again.
+func (pn *PagerDutyNotifier) Notify(to string, notification Notification) error {
+	event := PagerDutyEvent{
+		RoutingKey:  to,
+		EventAction: "resolve",
+		DedupKey:    dedupKey(notification),
+	}
+
+	if notification.Status != StatusResolved {
+		severity := "warning"
+		if SeverityOf(notification.Status) == SeverityCritical {
+			severity = "critical"
+		}
+
+		details := map[string]any{
+			"status":     notification.Status,
+			"hours_left": notification.Hours,
+			"issuer":     notification.Issuer,
+		}
+		if !notification.ExpiresAt.IsZero() {
+			details["expires_at"] = notification.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+
+		summary := fmt.Sprintf("TLS certificate for %s: %s", notification.Domain, notification.Status)
+		if notification.Message != "" {
+			summary = truncate(notification.Message, 1024)
+		}
+
+		event.EventAction = "trigger"
+		event.Payload = &PagerDutyPayload{
+			Summary:       summary,
+			Source:        notification.Domain,
+			Severity:      severity,
+			Component:     "tls-certificate",
+			CustomDetails: details,
+		}
+		event.Links = []PagerDutyLink{{Href: pn.appURL + "/dashboard", Text: "Domainator Dashboard"}}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(pn.Timeout, pn.baseURL+"/v2/enqueue", body, nil, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending pagerduty event: %w", err)
+	}
+
+	return nil
+}
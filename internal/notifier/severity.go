@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+)
+
+// Severity ranks notifications so that they can be routed,
+// e.g. sending only critical ones to a paging tool.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+var ErrInvalidSeverity = errors.New("invalid severity, use one of 'info', 'warning' or 'critical'")
+
+func ParseSeverity(severity string) (Severity, error) {
+	switch s := Severity(strings.TrimSpace(severity)); s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return s, nil
+	default:
+		return SeverityInfo, ErrInvalidSeverity
+	}
+}
+
+// SeverityOf returns the severity of a notification status:
+// expired certs and connection errors are critical, expiring ones are warnings.
+func SeverityOf(status string) Severity {
+	switch status {
+	case "OK", StatusResolved, StatusDigest:
+		return SeverityInfo
+	case "expires soon", "expires today":
+		return SeverityWarning
+	default:
+		return SeverityCritical
+	}
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() >= min.rank()
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
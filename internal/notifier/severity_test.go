@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSeverityOf(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		status string
+		want   Severity
+	}{
+		{"OK", SeverityInfo},
+		{StatusResolved, SeverityInfo},
+		{StatusDigest, SeverityInfo},
+		{"expires soon", SeverityWarning},
+		{"expires today", SeverityWarning},
+		{"expired", SeverityCritical},
+		{"Cannot Connect", SeverityCritical},
+	}
+
+	for _, tc := range tt {
+		got := SeverityOf(tc.status)
+		if got != tc.want {
+			t.Errorf("Expected %q for status %q, got %q", tc.want, tc.status, got)
+		}
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		s    Severity
+		min  Severity
+		want bool
+	}{
+		{SeverityCritical, SeverityInfo, true},
+		{SeverityCritical, SeverityCritical, true},
+		{SeverityWarning, SeverityCritical, false},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityWarning, SeverityWarning, true},
+	}
+
+	for _, tc := range tt {
+		got := tc.s.AtLeast(tc.min)
+		if got != tc.want {
+			t.Errorf("Expected %q.AtLeast(%q) to be %t", tc.s, tc.min, tc.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	tt := []struct {
+		input string
+		want  Severity
+		err   error
+	}{
+		{"info", SeverityInfo, nil},
+		{" warning ", SeverityWarning, nil},
+		{"critical", SeverityCritical, nil},
+		{"urgent", SeverityInfo, ErrInvalidSeverity},
+	}
+
+	for _, tc := range tt {
+		got, err := ParseSeverity(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
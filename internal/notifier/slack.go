@@ -1,10 +1,8 @@
 package notifier
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 )
 
@@ -30,34 +28,22 @@ func (sn *SlackNotifier) Notify(to string, notification Notification) error {
 		notification.Status,
 		notification.Hours,
 	)
-
-	payload := SlackMessage{Text: text}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
+	if notification.Message != "" {
+		text = notification.Message
 	}
-
-	req, err := http.NewRequest(http.MethodPost, to, bytes.NewBuffer(body))
-	if err != nil {
-		return err
+	if notification.AckURL != "" {
+		text += fmt.Sprintf("\n<%s|Acknowledge or snooze>", notification.AckURL)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{Timeout: sn.Timeout}
-	resp, err := client.Do(req)
+	payload := SlackMessage{Text: text}
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(resp.Body)
+	err = postJSON(sn.Timeout, to, body, nil, notification.Receipt)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("error (%d) sending slack msg: %s", resp.StatusCode, buf.String())
+		return fmt.Errorf("error sending slack msg: %w", err)
 	}
 
 	return nil
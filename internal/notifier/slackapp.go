@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlackAPIURL is the base URL of the Slack Web API.
+const SlackAPIURL = "https://slack.com/api"
+
+// Action IDs of the buttons of certificate alerts, handled by the interactivity endpoint of the app.
+const (
+	SlackActionRecheck = "recheck"
+	SlackActionSnooze  = "snooze"
+)
+
+// ThreadStore remembers the message that started the thread about a domain in a Slack channel,
+// so that follow-ups are posted as replies to it.
+type ThreadStore interface {
+	GetThread(key string) (string, error)
+	SetThread(key string, ts string) error
+	DeleteThread(key string) error
+}
+
+// SlackAppNotifier posts Block Kit messages with the bot token of the installed Slack app.
+type SlackAppNotifier struct {
+	Timeout time.Duration
+	APIURL  string
+	token   string
+	appURL  string
+	threads ThreadStore
+}
+
+func NewSlackAppNotifier(token string, appURL string, threads ThreadStore) *SlackAppNotifier {
+	return &SlackAppNotifier{
+		Timeout: 5 * time.Second,
+		APIURL:  SlackAPIURL,
+		token:   token,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+		threads: threads,
+	}
+}
+
+type SlackPostMessage struct {
+	Channel  string           `json:"channel"`
+	Text     string           `json:"text"`
+	Blocks   []map[string]any `json:"blocks"`
+	ThreadTS string           `json:"thread_ts,omitempty"`
+	// ReplyBroadcast also shows the reply in the channel, used when a problem is resolved.
+	ReplyBroadcast bool `json:"reply_broadcast,omitempty"`
+}
+
+type slackPostMessageResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+// Notify posts to the Slack channel with the given ID. The first message about a certificate starts a thread,
+// follow-ups are replies to it until the certificate is healthy again. Reports and notices are not threaded.
+func (sn *SlackAppNotifier) Notify(to string, notification Notification) error {
+	threaded := sn.threads != nil && notification.ID != ""
+	key := to + ":" + notification.Domain
+	threadTS := ""
+	if threaded {
+		ts, err := sn.threads.GetThread(key)
+		if err != nil {
+			return err
+		}
+		threadTS = ts
+	}
+
+	text := fmt.Sprintf("%s: %s", notification.Domain, notification.Status)
+	if notification.Domain == "" {
+		text = truncate(notification.Message, 3000)
+	}
+
+	resolved := EventOf(notification.Status) == EventResolved
+	payload := SlackPostMessage{
+		Channel:        to,
+		Text:           text,
+		Blocks:         SlackBlocks(notification, sn.appURL),
+		ThreadTS:       threadTS,
+		ReplyBroadcast: threadTS != "" && resolved,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Authorization": "Bearer " + sn.token}
+	resBody, err := postJSONResponse(sn.Timeout, sn.APIURL+"/chat.postMessage", body, headers, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending slack msg: %w", err)
+	}
+
+	// The Web API answers 200 even when the message is rejected.
+	res := slackPostMessageResponse{}
+	err = json.Unmarshal(resBody, &res)
+	if err != nil {
+		return fmt.Errorf("error reading slack response: %w", err)
+	}
+	if !res.OK {
+		return fmt.Errorf("error sending slack msg: %s", res.Error)
+	}
+
+	if !threaded {
+		return nil
+	}
+
+	switch {
+	case resolved && threadTS != "":
+		return sn.threads.DeleteThread(key)
+	case !resolved && threadTS == "":
+		return sn.threads.SetThread(key, res.TS)
+	}
+	return nil
+}
+
+// SlackBlocks formats the notification with Block Kit,
+// the details of the certificate are left out of reports and notices.
+func SlackBlocks(n Notification, appURL string) []map[string]any {
+	blocks := []map[string]any{}
+	if n.Domain != "" {
+		blocks = append(blocks, map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": truncate(n.Domain, 150)}})
+	}
+	if n.Message != "" {
+		blocks = append(blocks, map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": truncate(n.Message, 3000)}})
+	}
+
+	fields := []map[string]any{
+		{"type": "mrkdwn", "text": "*Status*\n" + n.Status},
+		{"type": "mrkdwn", "text": "*Hours*\n" + strconv.Itoa(n.Hours)},
+	}
+	if n.Issuer != "" {
+		fields = append(fields, map[string]any{"type": "mrkdwn", "text": "*Issuer*\n" + n.Issuer})
+	}
+	if !n.ExpiresAt.IsZero() {
+		fields = append(fields, map[string]any{"type": "mrkdwn", "text": "*Expires*\n" + n.ExpiresAt.UTC().Format(time.DateOnly)})
+	}
+	if n.ID != "" {
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fields})
+	}
+
+	buttons := []map[string]any{
+		{"type": "button", "text": map[string]any{"type": "plain_text", "text": "Open Dashboard"}, "url": appURL + "/dashboard"},
+	}
+	if n.ID != "" && EventOf(n.Status) != EventResolved {
+		buttons = append(buttons,
+			map[string]any{"type": "button", "text": map[string]any{"type": "plain_text", "text": "Recheck"}, "action_id": SlackActionRecheck, "value": n.ID},
+			map[string]any{"type": "button", "text": map[string]any{"type": "plain_text", "text": "Snooze 7d"}, "action_id": SlackActionSnooze, "value": n.ID},
+		)
+	}
+	if n.AckURL != "" {
+		buttons = append(buttons, map[string]any{"type": "button", "text": map[string]any{"type": "plain_text", "text": "Acknowledge Incident"}, "url": n.AckURL})
+	}
+	blocks = append(blocks, map[string]any{"type": "actions", "elements": buttons})
+
+	return blocks
+}
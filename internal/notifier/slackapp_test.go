@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type memoryThreads map[string]string
+
+func (m memoryThreads) GetThread(key string) (string, error) { return m[key], nil }
+func (m memoryThreads) SetThread(key string, ts string) error {
+	m[key] = ts
+	return nil
+}
+func (m memoryThreads) DeleteThread(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func TestSlackAppNotifierThreads(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan recordedRequest, 3)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]any{}
+		err := json.Unmarshal(body, &payload)
+		if err != nil {
+			t.Errorf("invalid JSON payload: %v", err)
+		}
+		requests <- recordedRequest{path: r.URL.RequestURI(), auth: r.Header.Get("Authorization"), payload: payload}
+		w.Write([]byte(`{"ok": true, "ts": "1700000000.000100"}`))
+	}))
+	defer ts.Close()
+
+	threads := memoryThreads{}
+	n := NewSlackAppNotifier("xoxb-token", "http://localhost:4000", threads)
+	n.APIURL = ts.URL
+	notification := Notification{ID: "018ec52b-dd69-7df4-b8e7-edcdc9a3a891", Domain: "example.com", Status: "expires soon"}
+
+	err := n.Notify("C0123456", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := <-requests
+	if first.path != "/chat.postMessage" || first.auth != "Bearer xoxb-token" {
+		t.Errorf("Unexpected request to %q with %q", first.path, first.auth)
+	}
+	if _, ok := first.payload["thread_ts"]; ok {
+		t.Errorf("Expected the first message to start a thread, got thread_ts %v", first.payload["thread_ts"])
+	}
+	if threads["C0123456:example.com"] != "1700000000.000100" {
+		t.Fatalf("Expected the thread to be saved, got %v", threads)
+	}
+
+	notification.Status = "expired"
+	err = n.Notify("C0123456", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	followUp := <-requests
+	if followUp.payload["thread_ts"] != "1700000000.000100" || followUp.payload["reply_broadcast"] != nil {
+		t.Errorf("Expected a reply in the thread, got %v", followUp.payload)
+	}
+
+	notification.Status = StatusResolved
+	err = n.Notify("C0123456", notification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved := <-requests
+	if resolved.payload["thread_ts"] != "1700000000.000100" || resolved.payload["reply_broadcast"] != true {
+		t.Errorf("Expected a broadcast reply, got %v", resolved.payload)
+	}
+	if len(threads) != 0 {
+		t.Errorf("Expected the thread to be forgotten, got %v", threads)
+	}
+}
+
+func TestSlackAppNotifierError(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	}))
+	defer ts.Close()
+
+	n := NewSlackAppNotifier("xoxb-token", "http://localhost:4000", memoryThreads{})
+	n.APIURL = ts.URL
+
+	err := n.Notify("C0123456", Notification{Message: "Test message"})
+	if err == nil {
+		t.Error("Expected an error when Slack rejects the message")
+	}
+}
@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TeamsNotifier struct {
+	Timeout time.Duration
+	appURL  string
+}
+
+func NewTeamser(appURL string) Notifier {
+	return &TeamsNotifier{
+		Timeout: 5 * time.Second,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+// TeamsMessage wraps an Adaptive Card, the format accepted by both
+// Teams incoming webhooks and Power Automate workflows.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+type AdaptiveCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+	Actions []map[string]any `json:"actions,omitempty"`
+}
+
+func (tn *TeamsNotifier) Notify(to string, notification Notification) error {
+	facts := []map[string]string{
+		{"title": "Status", "value": notification.Status},
+		{"title": "Hours", "value": strconv.Itoa(notification.Hours)},
+	}
+	if notification.Issuer != "" {
+		facts = append(facts, map[string]string{"title": "Issuer", "value": notification.Issuer})
+	}
+
+	color := "Attention"
+	switch notification.Status {
+	case "OK", StatusResolved:
+		color = "Good"
+	case "expires soon", "expires today":
+		color = "Warning"
+	}
+
+	card := AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]any{
+			{"type": "TextBlock", "text": notification.Domain, "size": "Large", "weight": "Bolder", "color": color},
+		},
+		Actions: []map[string]any{
+			{"type": "Action.OpenUrl", "title": "Open Dashboard", "url": tn.appURL + "/dashboard"},
+		},
+	}
+
+	if notification.AckURL != "" {
+		card.Actions = append(card.Actions, map[string]any{"type": "Action.OpenUrl", "title": "Acknowledge or Snooze", "url": notification.AckURL})
+	}
+
+	if notification.Message != "" {
+		card.Body = append(card.Body, map[string]any{"type": "TextBlock", "text": notification.Message, "wrap": true})
+	}
+	card.Body = append(card.Body, map[string]any{"type": "FactSet", "facts": facts})
+
+	payload := TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(tn.Timeout, to, body, nil, notification.Receipt)
+	if err != nil {
+		return fmt.Errorf("error sending teams msg: %w", err)
+	}
+
+	return nil
+}
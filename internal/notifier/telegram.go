@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TelegramAPIURL is the base URL of the Telegram Bot API.
+const TelegramAPIURL = "https://api.telegram.org"
+
+// maxTelegramLength is the limit of the text of a Telegram message.
+const maxTelegramLength = 4096
+
+var (
+	telegramChatRegex  = regexp.MustCompile(`^(-?[0-9]{1,20}|@[A-Za-z][A-Za-z0-9_]{4,31})$`)
+	telegramTokenRegex = regexp.MustCompile(`^[0-9]{1,20}:[A-Za-z0-9_-]{30,}$`)
+	// telegramEscaper escapes the characters reserved by MarkdownV2.
+	telegramEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+		">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	// telegramURLEscaper escapes the characters reserved inside the URL of a MarkdownV2 link.
+	telegramURLEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+// ValidTelegramChat reports whether chat is the ID of a Telegram chat or the @username of a public channel.
+func ValidTelegramChat(chat string) bool {
+	return telegramChatRegex.MatchString(chat)
+}
+
+// ValidTelegramToken reports whether token looks like the token BotFather gives to bots.
+func ValidTelegramToken(token string) bool {
+	return telegramTokenRegex.MatchString(token)
+}
+
+// TelegramNotifier sends messages through a Telegram bot.
+type TelegramNotifier struct {
+	Timeout time.Duration
+	APIURL  string
+	token   string
+	appURL  string
+}
+
+func NewTelegramNotifier(token string, appURL string) *TelegramNotifier {
+	return &TelegramNotifier{
+		Timeout: 5 * time.Second,
+		APIURL:  TelegramAPIURL,
+		token:   token,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type TelegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Notify sends the notification to the chat with the given ID.
+func (tn *TelegramNotifier) Notify(to string, notification Notification) error {
+	body, err := json.Marshal(TelegramMessage{
+		ChatID:                to,
+		Text:                  TelegramText(notification, tn.appURL),
+		ParseMode:             "MarkdownV2",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = postJSON(tn.Timeout, tn.APIURL+"/bot"+tn.token+"/sendMessage", body, nil, notification.Receipt)
+	if err != nil {
+		// The token is part of the URL, keep it out of the logs and the delivery log.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("error sending telegram msg: %w", err)
+	}
+
+	return nil
+}
+
+// TelegramText formats the notification with Telegram's MarkdownV2.
+func TelegramText(n Notification, appURL string) string {
+	lines := []string{}
+	if n.Domain != "" {
+		lines = append(lines, "*"+telegramEscaper.Replace(n.Domain)+"*")
+	}
+
+	if n.Message != "" {
+		lines = append(lines, telegramEscaper.Replace(n.Message))
+	} else {
+		for _, d := range plainDetails(n) {
+			lines = append(lines, telegramEscaper.Replace(d))
+		}
+	}
+
+	links := "[Open dashboard](" + telegramURLEscaper.Replace(appURL+"/dashboard") + ")"
+	if n.AckURL != "" {
+		links += telegramEscaper.Replace(" · ") + "[Acknowledge or snooze](" + telegramURLEscaper.Replace(n.AckURL) + ")"
+	}
+	lines = append(lines, "", links)
+
+	return truncate(strings.Join(lines, "\n"), maxTelegramLength)
+}
+
+// plainDetails describes the certificate of the notification, one detail per line.
+func plainDetails(n Notification) []string {
+	details := []string{"Status: " + n.Status}
+	if n.ID == "" {
+		return details
+	}
+	if !n.ExpiresAt.IsZero() {
+		details = append(details, fmt.Sprintf("Expires: %s (%d hours left)", n.ExpiresAt.UTC().Format(time.DateOnly), n.Hours))
+	}
+	if n.Issuer != "" {
+		details = append(details, "Issuer: "+n.Issuer)
+	}
+	if len(n.Tags) > 0 {
+		details = append(details, "Tags: "+strings.Join(n.Tags, ", "))
+	}
+	return details
+}
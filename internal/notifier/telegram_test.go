@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan recordedRequest, 1)
+	ts := recorder(t, requests)
+	defer ts.Close()
+
+	n := NewTelegramNotifier("123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", "http://localhost:4000")
+	n.APIURL = ts.URL
+
+	err := n.Notify("-100123", Notification{ID: "1", Domain: "example.com", Status: "expires soon", Hours: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+
+	if req.path != "/bot123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw/sendMessage" {
+		t.Errorf("Unexpected path %q", req.path)
+	}
+	if req.payload["chat_id"] != "-100123" || req.payload["parse_mode"] != "MarkdownV2" {
+		t.Errorf("Unexpected payload %v", req.payload)
+	}
+}
+
+func TestTelegramText(t *testing.T) {
+	t.Parallel()
+
+	n := Notification{
+		ID:        "1",
+		Domain:    "my-site.example.com",
+		Status:    "expires soon",
+		Hours:     50,
+		ExpiresAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		AckURL:    "https://app.example.com/incidents/ack?token=a(b)",
+	}
+
+	got := TelegramText(n, "https://app.example.com")
+	for _, want := range []string{
+		`*my\-site\.example\.com*`,
+		`Expires: 2024\-05\-01 \(50 hours left\)`,
+		`[Open dashboard](https://app.example.com/dashboard)`,
+		`[Acknowledge or snooze](https://app.example.com/incidents/ack?token=a(b\))`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in %q", want, got)
+		}
+	}
+
+	n.Message = "Renew *now*!"
+	got = TelegramText(n, "https://app.example.com")
+	if !strings.Contains(got, `Renew \*now\*\!`) || strings.Contains(got, "Status:") {
+		t.Errorf("Expected the escaped custom message instead of the details, got %q", got)
+	}
+}
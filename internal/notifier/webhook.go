@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+const (
+	WebhookVersion         = "1"
+	WebhookTimestampHeader = "X-Domainator-Timestamp"
+	WebhookSignatureHeader = "X-Domainator-Signature"
+	WebhookDeliveryHeader  = "X-Domainator-Delivery"
+	WebhookVersionHeader   = "X-Domainator-Version"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookNotifier POSTs a versioned JSON document to an arbitrary URL.
+// Requests are signed with an HMAC of the timestamp and body,
+// so receivers can verify their authenticity and reject replays.
+type WebhookNotifier struct {
+	Timeout time.Duration
+	secret  string
+	appURL  string
+}
+
+func NewWebhooker(secret string, appURL string) Notifier {
+	return &WebhookNotifier{
+		Timeout: 5 * time.Second,
+		secret:  secret,
+		appURL:  strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+type WebhookPayload struct {
+	Version       string     `json:"version"`
+	CertificateID string     `json:"certificate_id"`
+	Domain        string     `json:"domain"`
+	Status        string     `json:"status"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	Issuer        string     `json:"issuer"`
+	HoursLeft     int        `json:"hours_left"`
+	DashboardURL  string     `json:"dashboard_url"`
+	Message       string     `json:"message,omitempty"`
+	AckURL        string     `json:"ack_url,omitempty"`
+	SentAt        time.Time  `json:"sent_at"`
+}
+
+func (wn *WebhookNotifier) Notify(to string, notification Notification) error {
+	payload := WebhookPayload{
+		Version:       WebhookVersion,
+		CertificateID: notification.ID,
+		Domain:        notification.Domain,
+		Status:        notification.Status,
+		Issuer:        notification.Issuer,
+		HoursLeft:     notification.Hours,
+		DashboardURL:  wn.appURL + "/dashboard",
+		Message:       notification.Message,
+		AckURL:        notification.AckURL,
+		SentAt:        time.Now().UTC(),
+	}
+	if !notification.ExpiresAt.IsZero() {
+		exp := notification.ExpiresAt.UTC()
+		payload.ExpiresAt = &exp
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		WebhookTimestampHeader: timestamp,
+		WebhookSignatureHeader: SignWebhook(wn.secret, timestamp, body),
+		WebhookDeliveryHeader:  common.NewID().String(),
+		WebhookVersionHeader:   WebhookVersion,
+	}
+
+	return postJSON(wn.Timeout, to, body, headers, notification.Receipt)
+}
+
+// SignWebhook returns the signature header value for the given timestamp and body,
+// it is `sha256=` followed by the hex-encoded HMAC-SHA256 of "{timestamp}.{body}".
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the signature and rejects requests whose timestamp
+// is further away than the tolerance, to prevent replay attacks.
+func VerifyWebhook(secret string, timestamp string, body []byte, signature string, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	age := time.Since(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := SignWebhook(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhooker(t *testing.T) {
+	t.Parallel()
+
+	const webhookSecret = "whsec_test"
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = VerifyWebhook(
+			webhookSecret,
+			r.Header.Get(WebhookTimestampHeader),
+			body,
+			r.Header.Get(WebhookSignatureHeader),
+			5*time.Minute,
+		)
+		if err != nil {
+			t.Errorf("Expected valid signature, got %v", err)
+		}
+
+		payload := WebhookPayload{}
+		err = json.Unmarshal(body, &payload)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if payload.Version != WebhookVersion {
+			t.Errorf("Expected version %q, got %q", WebhookVersion, payload.Version)
+		}
+		if payload.Domain != "example.com" || payload.Status != "expires soon" || payload.Issuer != "Let's Encrypt" {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+		if payload.ExpiresAt == nil || !payload.ExpiresAt.Equal(expiry) {
+			t.Errorf("Expected expires_at %s, got %v", expiry, payload.ExpiresAt)
+		}
+		if payload.HoursLeft != 48 || payload.DashboardURL != "http://localhost:4000/dashboard" {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	webhooker := NewWebhooker(webhookSecret, "http://localhost:4000")
+	err := webhooker.Notify(ts.URL, Notification{
+		ID:        "018ec52b-dd69-7df4-b8e7-edcdc9a3a891",
+		Domain:    "example.com",
+		Status:    "expires soon",
+		Hours:     48,
+		Issuer:    "Let's Encrypt",
+		ExpiresAt: expiry,
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"version":"1"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	tt := []struct {
+		name      string
+		timestamp string
+		signature string
+		err       error
+	}{
+		{"valid", now, SignWebhook("secret", now, body), nil},
+		{"wrong_secret", now, SignWebhook("other", now, body), ErrInvalidSignature},
+		{"replayed", old, SignWebhook("secret", old, body), ErrInvalidSignature},
+		{"bad_timestamp", "yesterday", SignWebhook("secret", "yesterday", body), ErrInvalidSignature},
+	}
+
+	for _, tc := range tt {
+		err := VerifyWebhook("secret", tc.timestamp, body, tc.signature, 5*time.Minute)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected error %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestWebhookerReceipt(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		_, _ = w.Write([]byte("endpoint removed"))
+	}))
+	defer ts.Close()
+
+	receipt := &Receipt{}
+	webhooker := NewWebhooker("whsec_test", "http://localhost:4000")
+	err := webhooker.Notify(ts.URL, Notification{Domain: "example.com", Status: "expired", Receipt: receipt})
+	if err == nil {
+		t.Error("Expected an error, got nil")
+	}
+
+	if receipt.StatusCode != http.StatusGone {
+		t.Errorf("Expected status code %d, got %d", http.StatusGone, receipt.StatusCode)
+	}
+	if receipt.Body != "endpoint removed" {
+		t.Errorf("Expected body %q, got %q", "endpoint removed", receipt.Body)
+	}
+}
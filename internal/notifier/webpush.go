@@ -0,0 +1,143 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+const (
+	// maxWebPushBody keeps the body short, browsers cut long notifications anyway.
+	maxWebPushBody = 1000
+	// webPushTTL is how long push services keep the message while the browser is offline.
+	webPushTTL = 24 * time.Hour
+)
+
+// SubscriptionStore keeps the browsers subscribed to the push notifications of each user.
+type SubscriptionStore interface {
+	GetSubscriptions(userID string) ([]webpush.Subscription, error)
+	DeleteSubscription(endpoint string) error
+}
+
+// WebPushNotifier shows the notification in every browser the user subscribed,
+// subscriptions the push service reports as gone are deleted.
+type WebPushNotifier struct {
+	Client *http.Client
+	vapid  *webpush.VAPID
+	subs   SubscriptionStore
+	appURL string
+}
+
+func NewWebPushNotifier(vapid *webpush.VAPID, subs SubscriptionStore, appURL string) *WebPushNotifier {
+	return &WebPushNotifier{
+		Client: &http.Client{Timeout: 5 * time.Second},
+		vapid:  vapid,
+		subs:   subs,
+		appURL: strings.TrimSuffix(appURL, "/"),
+	}
+}
+
+// WebPushMessage is what the service worker receives to show the notification.
+type WebPushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	// Tag makes a notification about a domain replace the previous one.
+	Tag string `json:"tag"`
+}
+
+// Notify sends the notification to the browsers of the user, to is the ID of the user.
+func (wn *WebPushNotifier) Notify(to string, notification Notification) error {
+	if !wn.vapid.Enabled() {
+		return errors.New("web push is not configured")
+	}
+
+	subs, err := wn.subs.GetSubscriptions(to)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(WebPushPayload(notification, wn.appURL))
+	if err != nil {
+		return err
+	}
+
+	msg := webpush.Message{
+		Payload: payload,
+		TTL:     webPushTTL,
+		Urgency: "normal",
+		Topic:   webPushTopic(notification),
+	}
+	if notification.ID != "" && SeverityOf(notification.Status) == SeverityCritical {
+		msg.Urgency = "high"
+	}
+
+	errs := []error{}
+	for _, sub := range subs {
+		status, err := wn.vapid.Send(context.Background(), wn.Client, sub, msg)
+		if notification.Receipt != nil && status != 0 {
+			notification.Receipt.StatusCode = status
+		}
+		if errors.Is(err, webpush.ErrGone) {
+			err = wn.subs.DeleteSubscription(sub.Endpoint)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Delivering to some of the browsers is enough.
+	if len(errs) > 0 && len(errs) == len(subs) {
+		return fmt.Errorf("error sending push msg: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// WebPushPayload formats the notification for the service worker.
+func WebPushPayload(n Notification, appURL string) WebPushMessage {
+	title := "Domainator"
+	if n.Domain != "" {
+		title = n.Domain + ": " + n.Status
+	}
+
+	body := n.Message
+	if body == "" {
+		body = strings.Join(plainDetails(n), "\n")
+	}
+
+	url := appURL + "/dashboard"
+	if n.AckURL != "" {
+		url = n.AckURL
+	}
+
+	tag := "domainator"
+	if n.Domain != "" {
+		tag = n.Domain
+	}
+
+	return WebPushMessage{
+		Title: title,
+		Body:  truncate(body, maxWebPushBody),
+		URL:   url,
+		Tag:   tag,
+	}
+}
+
+// webPushTopic lets a message about a domain replace one still waiting to be delivered,
+// topics are limited to 32 characters of the base64url alphabet.
+func webPushTopic(n Notification) string {
+	if n.Domain == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(n.Domain))
+	return base64.RawURLEncoding.EncodeToString(sum[:])[:32]
+}
@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/germandv/domainator/internal/keys"
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+type memorySubscriptions struct {
+	subs    []webpush.Subscription
+	deleted []string
+}
+
+func (m *memorySubscriptions) GetSubscriptions(userID string) ([]webpush.Subscription, error) {
+	return m.subs, nil
+}
+
+func (m *memorySubscriptions) DeleteSubscription(endpoint string) error {
+	m.deleted = append(m.deleted, endpoint)
+	return nil
+}
+
+func pushSubscription(t *testing.T, endpoint string) webpush.Subscription {
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+
+	return webpush.Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(private.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}
+}
+
+func TestWebPushNotifier(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	private, _, err := keys.NewVAPID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vapid, err := webpush.NewVAPID(private, "mailto:admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		paths     []string
+		deleted   int
+		expectErr bool
+	}{
+		{"no subscriptions", []string{}, 0, false},
+		{"delivered", []string{"/ok"}, 0, false},
+		{"gone subscriptions are deleted", []string{"/ok", "/gone"}, 1, false},
+		{"delivered to some browsers", []string{"/ok", "/broken"}, 0, false},
+		{"not delivered", []string{"/broken"}, 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &memorySubscriptions{}
+			for _, p := range tc.paths {
+				store.subs = append(store.subs, pushSubscription(t, server.URL+p))
+			}
+
+			wn := NewWebPushNotifier(vapid, store, "https://domainator.example.com")
+			wn.Client = server.Client()
+
+			err := wn.Notify("user-id", Notification{Domain: "example.com", Status: StatusResolved})
+			if tc.expectErr && err == nil {
+				t.Error("Expected an error, got nil")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("Expected no error, got %s", err)
+			}
+			if len(store.deleted) != tc.deleted {
+				t.Errorf("Expected %d deleted subscriptions, got %d", tc.deleted, len(store.deleted))
+			}
+		})
+	}
+}
+
+func TestWebPushNotifierDisabled(t *testing.T) {
+	t.Parallel()
+
+	wn := NewWebPushNotifier(nil, &memorySubscriptions{}, "https://domainator.example.com")
+	err := wn.Notify("user-id", Notification{Domain: "example.com"})
+	if err == nil {
+		t.Error("Expected an error when VAPID keys are not configured, got nil")
+	}
+}
+
+func TestWebPushPayload(t *testing.T) {
+	t.Parallel()
+
+	n := Notification{ID: "id", Domain: "example.com", Status: "expired", AckURL: "https://domainator.example.com/ack/x"}
+	p := WebPushPayload(n, "https://domainator.example.com")
+
+	if p.Title != "example.com: expired" {
+		t.Errorf("Expected title %q, got %q", "example.com: expired", p.Title)
+	}
+	if p.URL != n.AckURL {
+		t.Errorf("Expected URL %q, got %q", n.AckURL, p.URL)
+	}
+	if p.Tag != "example.com" {
+		t.Errorf("Expected tag %q, got %q", "example.com", p.Tag)
+	}
+	if len(webPushTopic(n)) != 32 {
+		t.Errorf("Expected a topic of 32 characters, got %q", webPushTopic(n))
+	}
+}
@@ -0,0 +1,321 @@
+// Package openapi publishes the OpenAPI document of the JSON API and validates
+// requests and responses against it, so the handlers and the spec can't drift.
+//
+// The validator covers the subset of JSON Schema the document uses: type (nullable with
+// a list of types), enum, required, properties, additionalProperties false, items,
+// minLength/maxLength, minItems/maxItems, minimum/maximum and the uuid and date-time formats.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+var (
+	ErrInvalidRef        = errors.New("invalid $ref")
+	ErrUndocumented      = errors.New("undocumented")
+	ErrInvalidBody       = errors.New("invalid JSON body")
+	ErrMissingBody       = errors.New("missing body")
+	ErrInvalidMediaType  = errors.New("invalid media type")
+	ErrUnexpectedContent = errors.New("unexpected content")
+)
+
+const (
+	schemasPrefix   = "#/components/schemas/"
+	responsesPrefix = "#/components/responses/"
+)
+
+// Spec returns the OpenAPI document as JSON.
+func Spec() []byte {
+	return spec
+}
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Servers    []Server             `json:"servers"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type PathItem struct {
+	Get    *Operation `json:"get"`
+	Put    *Operation `json:"put"`
+	Post   *Operation `json:"post"`
+	Delete *Operation `json:"delete"`
+	Patch  *Operation `json:"patch"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses"`
+}
+
+// Load parses the embedded document and checks that every $ref points to a component.
+func Load() (*Document, error) {
+	doc := &Document{}
+	err := json.Unmarshal(spec, doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+
+	for p, item := range doc.Paths {
+		for method, op := range item.operations() {
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					err = doc.checkSchemaRefs(mt.Schema)
+					if err != nil {
+						return nil, fmt.Errorf("%s %s request: %w", method, p, err)
+					}
+				}
+			}
+			for status, resp := range op.Responses {
+				resp, err = doc.response(resp)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s %s response: %w", method, p, status, err)
+				}
+				for _, mt := range resp.Content {
+					err = doc.checkSchemaRefs(mt.Schema)
+					if err != nil {
+						return nil, fmt.Errorf("%s %s %s response: %w", method, p, status, err)
+					}
+				}
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// BasePath is the path the API is served under.
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(d.Servers[0].URL, "/")
+}
+
+// Find returns the operation of the method on the path, which includes the base path.
+// Templated segments like {id} match any non-empty segment.
+func (d *Document) Find(method string, path string) (*Operation, bool) {
+	rel, found := strings.CutPrefix(path, d.BasePath())
+	if !found {
+		return nil, false
+	}
+
+	for tmpl, item := range d.Paths {
+		if !matchPath(tmpl, rel) {
+			continue
+		}
+		op := item.operations()[method]
+		return op, op != nil
+	}
+
+	return nil, false
+}
+
+// ValidateRequest checks the body of a request to the operation.
+func (d *Document) ValidateRequest(op *Operation, contentType string, body []byte) error {
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			return ErrMissingBody
+		}
+		return nil
+	}
+
+	mt, err := mediaType(op.RequestBody.Content, contentType)
+	if err != nil {
+		return err
+	}
+
+	return d.validateBody(mt.Schema, body)
+}
+
+// ValidateResponse checks that the status code is documented for the operation
+// and the body matches its schema.
+func (d *Document) ValidateResponse(op *Operation, status int, contentType string, body []byte) error {
+	resp, found := op.Responses[strconv.Itoa(status)]
+	if !found {
+		resp, found = op.Responses["default"]
+	}
+	if !found {
+		return fmt.Errorf("status %d of %s: %w", status, op.OperationID, ErrUndocumented)
+	}
+
+	resp, err := d.response(resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("status %d of %s has no content: %w", status, op.OperationID, ErrUnexpectedContent)
+		}
+		return nil
+	}
+
+	mt, err := mediaType(resp.Content, contentType)
+	if err != nil {
+		return err
+	}
+
+	return d.validateBody(mt.Schema, body)
+}
+
+func (d *Document) validateBody(s *Schema, body []byte) error {
+	if s == nil {
+		return nil
+	}
+
+	var v any
+	err := json.Unmarshal(body, &v)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidBody, err.Error())
+	}
+
+	return d.Validate(s, v)
+}
+
+// response resolves a Response that is a $ref to the components.
+func (d *Document) response(r *Response) (*Response, error) {
+	if r.Ref == "" {
+		return r, nil
+	}
+
+	name, found := strings.CutPrefix(r.Ref, responsesPrefix)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRef, r.Ref)
+	}
+	resp, found := d.Components.Responses[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRef, r.Ref)
+	}
+
+	return resp, nil
+}
+
+// schema resolves a Schema that is a $ref to the components.
+func (d *Document) schema(s *Schema) (*Schema, error) {
+	if s.Ref == "" {
+		return s, nil
+	}
+
+	name, found := strings.CutPrefix(s.Ref, schemasPrefix)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRef, s.Ref)
+	}
+	resolved, found := d.Components.Schemas[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRef, s.Ref)
+	}
+
+	return resolved, nil
+}
+
+func (d *Document) checkSchemaRefs(s *Schema) error {
+	if s == nil {
+		return nil
+	}
+
+	s, err := d.schema(s)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range s.Properties {
+		err = d.checkSchemaRefs(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.checkSchemaRefs(s.Items)
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+		http.MethodPatch:  p.Patch,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+func matchPath(tmpl string, path string) bool {
+	tmplSegments := strings.Split(tmpl, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(tmplSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, s := range tmplSegments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if s != pathSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mediaType(content map[string]MediaType, contentType string) (MediaType, error) {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return MediaType{}, fmt.Errorf("%w: %q", ErrInvalidMediaType, contentType)
+	}
+
+	mt, found := content[parsed]
+	if !found {
+		return MediaType{}, fmt.Errorf("%w: %q", ErrInvalidMediaType, parsed)
+	}
+
+	return mt, nil
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load()
+	if err != nil {
+		t.Fatalf("Expected the document to load, got %s", err)
+	}
+
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %s", doc.OpenAPI)
+	}
+	if doc.BasePath() != "/api/v1" {
+		t.Errorf("Expected base path /api/v1, got %s", doc.BasePath())
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		operationID string
+	}{
+		{"list", "GET", "/api/v1/domains", "listDomains"},
+		{"create", "POST", "/api/v1/domains", "createDomain"},
+		{"templated", "DELETE", "/api/v1/domains/018ec52b-dd69-7df4-b8e7-edcdc9a3a891", "deleteDomain"},
+		{"templated_suffix", "POST", "/api/v1/domains/018ec52b-dd69-7df4-b8e7-edcdc9a3a891/refresh", "refreshDomain"},
+		{"wrong_method", "PUT", "/api/v1/domains", ""},
+		{"empty_param", "DELETE", "/api/v1/domains/", ""},
+		{"no_base_path", "GET", "/domains", ""},
+		{"unknown", "GET", "/api/v1/unknown", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			op, found := doc.Find(tc.method, tc.path)
+			if tc.operationID == "" {
+				if found {
+					t.Errorf("Expected no operation, got %s", op.OperationID)
+				}
+				return
+			}
+			if !found {
+				t.Fatalf("Expected operation %s, got none", tc.operationID)
+			}
+			if op.OperationID != tc.operationID {
+				t.Errorf("Expected operation %s, got %s", tc.operationID, op.OperationID)
+			}
+		})
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, _ := doc.Find("POST", "/api/v1/domains")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		valid       bool
+	}{
+		{"valid", "application/json", `{"domain": "example.com", "tags": ["prod"]}`, true},
+		{"charset", "application/json; charset=utf-8", `{"domain": "example.com"}`, true},
+		{"missing_body", "application/json", ``, false},
+		{"missing_domain", "application/json", `{"tags": ["prod"]}`, false},
+		{"empty_domain", "application/json", `{"domain": ""}`, false},
+		{"wrong_type", "application/json", `{"domain": 42}`, false},
+		{"unknown_property", "application/json", `{"domain": "example.com", "port": 443}`, false},
+		{"too_many_tags", "application/json", `{"domain": "example.com", "tags": ["a","b","c","d","e","f","g","h","i","j","k"]}`, false},
+		{"invalid_json", "application/json", `{"domain": `, false},
+		{"form", "application/x-www-form-urlencoded", `domain=example.com`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := doc.ValidateRequest(op, tc.contentType, []byte(tc.body))
+			if tc.valid && err != nil {
+				t.Errorf("Expected a valid request, got %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("Expected an invalid request, got none")
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain := `{"id": "018ec52b-dd69-7df4-b8e7-edcdc9a3a891", "domain": "example.com", "status": "ok", "issuer": "R3",
+		"expires_at": "2026-01-02T03:04:05Z", "error": "", "checking": false, "tags": [], "created_at": "2025-01-02T03:04:05Z",
+		"checked_at": null, "snoozed_until": null}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		valid  bool
+	}{
+		{"domain", "POST", "/api/v1/domains/018ec52b-dd69-7df4-b8e7-edcdc9a3a891/refresh", 202, domain, true},
+		{"domains", "GET", "/api/v1/domains", 200, `{"domains": [` + domain + `]}`, true},
+		{"error", "GET", "/api/v1/domains", 401, `{"error": "missing access token"}`, true},
+		{"no_content", "DELETE", "/api/v1/domains/018ec52b-dd69-7df4-b8e7-edcdc9a3a891", 204, ``, true},
+		{"undocumented_status", "GET", "/api/v1/domains", 418, `{"error": "teapot"}`, false},
+		{"unexpected_content", "DELETE", "/api/v1/domains/018ec52b-dd69-7df4-b8e7-edcdc9a3a891", 204, `{}`, false},
+		{"missing_property", "GET", "/api/v1/domains", 200, `{}`, false},
+		{"null_list", "GET", "/api/v1/domains", 200, `{"domains": null}`, false},
+		{"invalid_item", "GET", "/api/v1/domains", 200, `{"domains": [{"id": "1"}]}`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			op, found := doc.Find(tc.method, tc.path)
+			if !found {
+				t.Fatalf("Expected an operation for %s %s", tc.method, tc.path)
+			}
+
+			err := doc.ValidateResponse(op, tc.status, "application/json; charset=utf-8", []byte(tc.body))
+			if tc.valid && err != nil {
+				t.Errorf("Expected a valid response, got %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Errorf("Expected an invalid response, got none")
+			}
+		})
+	}
+
+	t.Run("undocumented_is_wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		op, _ := doc.Find("GET", "/api/v1/domains")
+		err := doc.ValidateResponse(op, 418, "application/json", nil)
+		if !errors.Is(err, ErrUndocumented) {
+			t.Errorf("Expected ErrUndocumented, got %v", err)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := doc.Components.Schemas["Digest"]
+
+	tests := []struct {
+		name    string
+		value   string
+		pointer string
+	}{
+		{"valid", `{"frequency": "weekly", "weekday": "monday", "hour": 8, "days": 14, "channel_id": ""}`, ""},
+		{"enum", `{"frequency": "hourly", "weekday": "monday", "hour": 8, "days": 14, "channel_id": ""}`, "/frequency"},
+		{"integer", `{"frequency": "daily", "weekday": "monday", "hour": 8.5, "days": 14, "channel_id": ""}`, "/hour"},
+		{"maximum", `{"frequency": "daily", "weekday": "monday", "hour": 24, "days": 14, "channel_id": ""}`, "/hour"},
+		{"minimum", `{"frequency": "daily", "weekday": "monday", "hour": 8, "days": 0, "channel_id": ""}`, "/days"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var v any
+			err := json.Unmarshal([]byte(tc.value), &v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = doc.Validate(digest, v)
+			if tc.pointer == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %s", err)
+				}
+				return
+			}
+
+			var vErr ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if vErr.Pointer != tc.pointer {
+				t.Errorf("Expected error at %q, got %q", tc.pointer, vErr.Pointer)
+			}
+		})
+	}
+}
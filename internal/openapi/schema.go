@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema, with the keywords the validator supports.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 Types              `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+// Types are the types a value can have, "type" is a string or a list of them.
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	err := json.Unmarshal(data, &one)
+	if err == nil {
+		*t = Types{one}
+		return nil
+	}
+
+	var many []string
+	err = json.Unmarshal(data, &many)
+	if err != nil {
+		return fmt.Errorf("type must be a string or a list of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+// ValidationError is a value that doesn't match its schema.
+type ValidationError struct {
+	// Pointer is the JSON pointer to the value, empty for the root.
+	Pointer string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// Validate checks v, a value decoded from JSON into any, against the schema.
+func (d *Document) Validate(s *Schema, v any) error {
+	return d.validate(s, v, "")
+}
+
+func (d *Document) validate(s *Schema, v any, ptr string) error {
+	s, err := d.schema(s)
+	if err != nil {
+		return err
+	}
+
+	typ := jsonType(v)
+	if len(s.Type) > 0 && !slices.Contains(s.Type, typ) && !(typ == "integer" && slices.Contains(s.Type, "number")) {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), typ)}
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("%v is not one of %v", v, s.Enum)}
+	}
+
+	switch val := v.(type) {
+	case string:
+		return validateString(s, val, ptr)
+	case float64:
+		return validateNumber(s, val, ptr)
+	case []any:
+		return d.validateArray(s, val, ptr)
+	case map[string]any:
+		return d.validateObject(s, val, ptr)
+	}
+
+	return nil
+}
+
+func validateString(s *Schema, v string, ptr string) error {
+	length := utf8.RuneCountInString(v)
+	if s.MinLength != nil && length < *s.MinLength {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must be at least %d characters long", *s.MinLength)}
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must be at most %d characters long", *s.MaxLength)}
+	}
+
+	switch s.Format {
+	case "uuid":
+		_, err := uuid.Parse(v)
+		if err != nil {
+			return ValidationError{Pointer: ptr, Message: fmt.Sprintf("%q is not a uuid", v)}
+		}
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return ValidationError{Pointer: ptr, Message: fmt.Sprintf("%q is not a date-time", v)}
+		}
+	}
+
+	return nil
+}
+
+func validateNumber(s *Schema, v float64, ptr string) error {
+	if s.Minimum != nil && v < *s.Minimum {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must be at least %v", *s.Minimum)}
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must be at most %v", *s.Maximum)}
+	}
+	return nil
+}
+
+func (d *Document) validateArray(s *Schema, v []any, ptr string) error {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must have at least %d items", *s.MinItems)}
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		return ValidationError{Pointer: ptr, Message: fmt.Sprintf("must have at most %d items", *s.MaxItems)}
+	}
+
+	if s.Items == nil {
+		return nil
+	}
+	for i, item := range v {
+		err := d.validate(s.Items, item, fmt.Sprintf("%s/%d", ptr, i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) validateObject(s *Schema, v map[string]any, ptr string) error {
+	for _, name := range s.Required {
+		if _, found := v[name]; !found {
+			return ValidationError{Pointer: ptr, Message: fmt.Sprintf("missing property %q", name)}
+		}
+	}
+
+	// Sorted so the first error is always the same one.
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, found := s.Properties[name]
+		if !found {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return ValidationError{Pointer: ptr, Message: fmt.Sprintf("unknown property %q", name)}
+			}
+			continue
+		}
+
+		err := d.validate(prop, v[name], ptr+"/"+escapePointer(name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonType returns the JSON Schema type of a value decoded from JSON.
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// escapePointer escapes a property name for a JSON pointer (RFC 6901).
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
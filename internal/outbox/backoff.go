@@ -0,0 +1,28 @@
+package outbox
+
+import "time"
+
+const (
+	// MaxAttempts is the number of deliveries attempted before a message is dead-lettered.
+	MaxAttempts = 8
+	baseBackoff = time.Minute
+	maxBackoff  = 6 * time.Hour
+)
+
+// Backoff returns how long to wait before retrying a message after the given failed attempt,
+// it doubles with every attempt, starting at one minute and capped at six hours.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return baseBackoff
+	}
+
+	backoff := baseBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return backoff
+}
@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 0, expected: time.Minute},
+		{attempt: 1, expected: time.Minute},
+		{attempt: 2, expected: 2 * time.Minute},
+		{attempt: 3, expected: 4 * time.Minute},
+		{attempt: 8, expected: 128 * time.Minute},
+		{attempt: 9, expected: 256 * time.Minute},
+		{attempt: 10, expected: 6 * time.Hour},
+		{attempt: 100, expected: 6 * time.Hour},
+	}
+
+	for _, test := range tests {
+		got := Backoff(test.attempt)
+		if got != test.expected {
+			t.Errorf("Expected backoff %s for attempt %d, got %s", test.expected, test.attempt, got)
+		}
+	}
+}
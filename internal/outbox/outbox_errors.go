@@ -0,0 +1,8 @@
+package outbox
+
+import "errors"
+
+var (
+	ErrInvalidState = errors.New("invalid outbox message state")
+	ErrNotFound     = errors.New("outbox message not found")
+)
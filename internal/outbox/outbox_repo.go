@@ -0,0 +1,193 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const QueryTimeout = 5 * time.Second
+
+type Repo interface {
+	Save(ctx context.Context, message repoMessage) error
+	Claim(ctx context.Context, size int, lease time.Duration) ([]repoMessage, error)
+	Update(ctx context.Context, id common.ID, state string, attempts int, nextAttemptAt time.Time, updatedAt time.Time) error
+	Requeue(ctx context.Context, userID common.ID, id common.ID, updatedAt time.Time) error
+	SaveDelivery(ctx context.Context, delivery repoDelivery) error
+	GetDeliveries(ctx context.Context, userID common.ID, limit int) ([]repoDelivery, error)
+}
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db}
+}
+
+func (r *OutboxRepo) Save(ctx context.Context, message repoMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `insert into notification_outbox (id, user_id, channel_id, payload, state, attempts, next_attempt_at, created_at, updated_at)
+    values ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.Exec(
+		ctx,
+		q,
+		message.ID,
+		message.UserID,
+		message.ChannelID,
+		message.Payload,
+		message.State,
+		message.Attempts,
+		message.NextAttemptAt,
+		message.CreatedAt,
+		message.UpdatedAt,
+	)
+
+	return err
+}
+
+// Claim returns up to size pending messages that are due, postponing their next attempt by lease
+// so other dispatchers skip them while they are being delivered.
+// If the dispatcher dies before updating them, they are retried once the lease expires.
+func (r *OutboxRepo) Claim(ctx context.Context, size int, lease time.Duration) ([]repoMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var messages []repoMessage
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		q := `
+      select
+        id, user_id, channel_id, payload, state, attempts, next_attempt_at, created_at, updated_at
+      from
+        notification_outbox
+      where
+        state = 'pending' and next_attempt_at <= (now() at time zone 'utc')
+      order by next_attempt_at
+      limit $1
+      for update skip locked`
+
+		rows, _ := tx.Query(ctx, q, size)
+		var err error
+		messages, err = pgx.CollectRows(rows, pgx.RowToStructByName[repoMessage])
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, len(messages))
+		for i, m := range messages {
+			ids[i] = m.ID
+		}
+
+		q = `update notification_outbox set next_attempt_at = $2 where id = any($1)`
+		_, err = tx.Exec(ctx, q, ids, time.Now().UTC().Add(lease))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) Update(
+	ctx context.Context,
+	id common.ID,
+	state string,
+	attempts int,
+	nextAttemptAt time.Time,
+	updatedAt time.Time,
+) error {
+	q := `
+    update
+      notification_outbox
+    set
+      state = $2,
+      attempts = $3,
+      next_attempt_at = $4,
+      updated_at = $5
+    where
+      id = $1`
+	return r.update(ctx, q, id, state, attempts, nextAttemptAt, updatedAt)
+}
+
+// Requeue moves a dead message of the user back to pending, to be retried from scratch.
+func (r *OutboxRepo) Requeue(ctx context.Context, userID common.ID, id common.ID, updatedAt time.Time) error {
+	q := `
+    update
+      notification_outbox
+    set
+      state = 'pending',
+      attempts = 0,
+      next_attempt_at = $3,
+      updated_at = $3
+    where
+      id = $1 and user_id = $2 and state = 'dead'`
+	return r.update(ctx, q, id, userID, updatedAt)
+}
+
+func (r *OutboxRepo) SaveDelivery(ctx context.Context, delivery repoDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `insert into notification_deliveries (id, message_id, channel_id, attempt, ok, status_code, response, error, created_at)
+    values ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.Exec(
+		ctx,
+		q,
+		delivery.ID,
+		delivery.MessageID,
+		delivery.ChannelID,
+		delivery.Attempt,
+		delivery.OK,
+		delivery.StatusCode,
+		delivery.Response,
+		delivery.Error,
+		delivery.CreatedAt,
+	)
+
+	return err
+}
+
+// GetDeliveries returns the latest delivery attempts of the user, newest first.
+func (r *OutboxRepo) GetDeliveries(ctx context.Context, userID common.ID, limit int) ([]repoDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      d.id, d.message_id, d.channel_id, c.name as channel_name, coalesce(m.payload ->> 'Domain', '') as domain,
+      m.state, d.attempt, d.ok, d.status_code, d.response, d.error, d.created_at
+    from
+      notification_deliveries d
+      join notification_outbox m on m.id = d.message_id
+      join notification_channels c on c.id = d.channel_id
+    where
+      m.user_id = $1
+    order by d.created_at desc, d.attempt desc
+    limit $2`
+
+	rows, _ := r.db.Query(ctx, q, userID, limit)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoDelivery])
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import "time"
+
+// repoMessage represents a Message in the Repository layer.
+type repoMessage struct {
+	ID            string    `db:"id"`
+	UserID        string    `db:"user_id"`
+	ChannelID     string    `db:"channel_id"`
+	Payload       []byte    `db:"payload"`
+	State         string    `db:"state"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// repoDelivery represents a Delivery in the Repository layer,
+// ChannelName, Domain and State are read from the channel and message it belongs to.
+type repoDelivery struct {
+	ID          string    `db:"id"`
+	MessageID   string    `db:"message_id"`
+	ChannelID   string    `db:"channel_id"`
+	ChannelName string    `db:"channel_name"`
+	Domain      string    `db:"domain"`
+	State       string    `db:"state"`
+	Attempt     int       `db:"attempt"`
+	OK          bool      `db:"ok"`
+	StatusCode  int       `db:"status_code"`
+	Response    string    `db:"response"`
+	Error       string    `db:"error"`
+	CreatedAt   time.Time `db:"created_at"`
+}
@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/germandv/domainator/internal/channels"
+	"github.com/germandv/domainator/internal/notifier"
+	"github.com/germandv/domainator/internal/users"
+)
+
+const (
+	// claimLease is how long claimed messages are hidden from other dispatchers.
+	claimLease       = 5 * time.Minute
+	maxDeliveriesLog = 100
+)
+
+type Service interface {
+	Enqueue(ctx context.Context, req EnqueueReq) error
+	Dispatch(ctx context.Context, req DispatchReq, logger *slog.Logger) (int, error)
+	GetDeliveries(ctx context.Context, req GetDeliveriesReq) ([]Delivery, error)
+	Retry(ctx context.Context, req RetryReq) error
+}
+
+type OutboxService struct {
+	repo      Repo
+	channels  channels.Service
+	users     users.Service
+	notifiers channels.Notifiers
+}
+
+func NewService(repo Repo, channelsService channels.Service, usersService users.Service, notifiers channels.Notifiers) *OutboxService {
+	return &OutboxService{
+		repo:      repo,
+		channels:  channelsService,
+		users:     usersService,
+		notifiers: notifiers,
+	}
+}
+
+// Enqueue writes the notification to the outbox, to be delivered through the channel by Dispatch.
+// Non-critical notifications are held back until the end of the user's quiet hours.
+func (s *OutboxService) Enqueue(ctx context.Context, req EnqueueReq) error {
+	message := New(req.UserID, req.ChannelID, req.Notification)
+
+	if notifier.SeverityOf(req.Notification.Status) != notifier.SeverityCritical {
+		u, err := s.users.GetByID(ctx, users.GetByIDReq{UserID: req.UserID})
+		if err != nil {
+			return err
+		}
+		if until, quiet := u.QuietHours.Until(message.CreatedAt, u.Timezone); quiet {
+			message.NextAttemptAt = until.UTC()
+		}
+	}
+
+	m, err := serviceToRepoAdapter(message)
+	if err != nil {
+		return err
+	}
+	return s.repo.Save(ctx, m)
+}
+
+// Dispatch claims a batch of due messages and delivers them, returning how many were claimed.
+// Failed deliveries are retried with exponential backoff and dead-lettered after MaxAttempts.
+func (s *OutboxService) Dispatch(ctx context.Context, req DispatchReq, logger *slog.Logger) (int, error) {
+	messages, err := s.repo.Claim(ctx, req.Size, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(messages))
+	for _, m := range messages {
+		go func(m repoMessage) {
+			defer wg.Done()
+			message, err := repoToServiceAdapter(m)
+			if err != nil {
+				logger.Error("failed to parse outbox message", "id", m.ID, "error", err.Error())
+				return
+			}
+			err = s.deliver(ctx, message, logger)
+			if err != nil {
+				logger.Error("failed to deliver outbox message", "id", m.ID, "error", err.Error())
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	return len(messages), nil
+}
+
+func (s *OutboxService) deliver(ctx context.Context, message Message, logger *slog.Logger) error {
+	channel, err := s.channels.Get(ctx, channels.GetReq{ID: message.ChannelID, UserID: message.UserID})
+	if err != nil && !errors.Is(err, channels.ErrNotFound) {
+		return err
+	}
+
+	// Messages for channels that were deleted or disabled in the meantime are dead-lettered right away,
+	// they don't count towards disabling the channel either.
+	reachable := err == nil && channel.Enabled
+	attempt := message.Attempts + 1
+	receipt := notifier.Receipt{}
+
+	switch {
+	case err != nil:
+		err = errors.New("channel was deleted")
+	case !channel.Enabled:
+		err = errors.New("channel is disabled")
+	default:
+		n := message.Notification
+		n.Receipt = &receipt
+		err = s.notifiers.Send(channel, n)
+		if errors.Is(err, channels.ErrTemplate) {
+			logger.Warn("sent default message", "id", message.ID.String(), "channel", channel.ID.String(), "error", err.Error())
+			err = nil
+		}
+	}
+
+	now := time.Now().UTC()
+	message.State, message.NextAttemptAt = StateSent, now
+	if err != nil {
+		message.State, message.NextAttemptAt = StatePending, now.Add(Backoff(attempt))
+		if attempt >= MaxAttempts || !reachable {
+			message.State = StateDead
+		}
+	}
+
+	e := s.repo.SaveDelivery(ctx, deliveryToRepoAdapter(NewDelivery(message, attempt, receipt, err)))
+	if e != nil {
+		return e
+	}
+
+	e = s.repo.Update(ctx, message.ID, string(message.State), attempt, message.NextAttemptAt, now)
+	if e != nil {
+		return e
+	}
+
+	if !reachable {
+		return nil
+	}
+
+	updated, disabled, e := s.channels.ReportDelivery(ctx, channels.ReportDeliveryReq{
+		ID:     channel.ID,
+		UserID: channel.UserID,
+		OK:     err == nil,
+	})
+	if e != nil {
+		return e
+	}
+
+	if disabled {
+		logger.Info("disabled failing channel", "id", channel.ID.String(), "user", channel.UserID.String())
+		return s.alertDisabled(ctx, updated)
+	}
+
+	return nil
+}
+
+// alertDisabled tells the user that the channel was disabled through their other enabled channels,
+// or by email to the address of their account if there's none.
+func (s *OutboxService) alertDisabled(ctx context.Context, disabled channels.Channel) error {
+	n := notifier.Notification{
+		UserID: disabled.UserID.String(),
+		Domain: disabled.Name,
+		Status: notifier.StatusChannelDisabled,
+		Message: fmt.Sprintf(
+			"The notification channel %q (%s) was disabled after %d consecutive failed deliveries. "+
+				"Check the delivery log and enable it again at %s/settings",
+			disabled.Name,
+			disabled.Kind.Label(),
+			disabled.Failures,
+			strings.TrimSuffix(s.notifiers.AppURL, "/"),
+		),
+	}
+
+	all, err := s.channels.GetAll(ctx, channels.GetAllReq{UserID: disabled.UserID})
+	if err != nil {
+		return err
+	}
+
+	alerted := false
+	for _, c := range all {
+		if !c.Enabled || c.ID.String() == disabled.ID.String() {
+			continue
+		}
+		err = s.Enqueue(ctx, EnqueueReq{UserID: c.UserID, ChannelID: c.ID, Notification: n})
+		if err != nil {
+			return err
+		}
+		alerted = true
+	}
+
+	if alerted {
+		return nil
+	}
+
+	user, err := s.users.GetByID(ctx, users.GetByIDReq{UserID: disabled.UserID})
+	if err != nil {
+		return err
+	}
+
+	return s.notifiers.Emailer.Notify(user.Email.String(), n)
+}
+
+// GetDeliveries returns the latest delivery attempts of the user.
+func (s *OutboxService) GetDeliveries(ctx context.Context, req GetDeliveriesReq) ([]Delivery, error) {
+	ds, err := s.repo.GetDeliveries(ctx, req.UserID, maxDeliveriesLog)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, len(ds))
+	for i, d := range ds {
+		delivery, err := repoToDeliveryAdapter(d)
+		if err != nil {
+			return nil, err
+		}
+		deliveries[i] = delivery
+	}
+
+	return deliveries, nil
+}
+
+// Retry moves a dead-lettered message back to the outbox, to be delivered by the next Dispatch.
+func (s *OutboxService) Retry(ctx context.Context, req RetryReq) error {
+	return s.repo.Requeue(ctx, req.UserID, req.ID, time.Now().UTC())
+}
@@ -0,0 +1,227 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/notifier"
+)
+
+// State of a Message in the outbox.
+type State string
+
+const (
+	StatePending State = "pending"
+	StateSent    State = "sent"
+	StateDead    State = "dead"
+)
+
+func ParseState(s string) (State, error) {
+	switch State(s) {
+	case StatePending, StateSent, StateDead:
+		return State(s), nil
+	default:
+		return "", ErrInvalidState
+	}
+}
+
+type EnqueueReq struct {
+	UserID       common.ID
+	ChannelID    common.ID
+	Notification notifier.Notification
+}
+
+type DispatchReq struct {
+	Size int
+}
+
+type GetDeliveriesReq struct {
+	UserID common.ID
+}
+
+type RetryReq struct {
+	ID     common.ID
+	UserID common.ID
+}
+
+// Message is a notification waiting in the outbox to be delivered through a channel.
+type Message struct {
+	ID            common.ID
+	UserID        common.ID
+	ChannelID     common.ID
+	Notification  notifier.Notification
+	State         State
+	Attempts      int
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Delivery is an attempt to deliver a Message.
+type Delivery struct {
+	ID          common.ID
+	MessageID   common.ID
+	ChannelID   common.ID
+	ChannelName string
+	Domain      string
+	State       State
+	Attempt     int
+	OK          bool
+	StatusCode  int
+	Response    string
+	Error       string
+	CreatedAt   time.Time
+}
+
+func New(userID common.ID, channelID common.ID, notification notifier.Notification) Message {
+	now := time.Now().UTC()
+	return Message{
+		ID:            common.NewID(),
+		UserID:        userID,
+		ChannelID:     channelID,
+		Notification:  notification,
+		State:         StatePending,
+		Attempts:      0,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// NewDelivery records the result of the attempt to deliver the message, err being the error of the notifier.
+func NewDelivery(message Message, attempt int, receipt notifier.Receipt, err error) Delivery {
+	delivery := Delivery{
+		ID:         common.NewID(),
+		MessageID:  message.ID,
+		ChannelID:  message.ChannelID,
+		Domain:     message.Notification.Domain,
+		State:      message.State,
+		Attempt:    attempt,
+		OK:         err == nil,
+		StatusCode: receipt.StatusCode,
+		Response:   receipt.Body,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	return delivery
+}
+
+// serviceToRepoAdapter transforms a Message from the Service layer to the Repository layer.
+func serviceToRepoAdapter(m Message) (repoMessage, error) {
+	payload, err := json.Marshal(m.Notification)
+	if err != nil {
+		return repoMessage{}, err
+	}
+
+	return repoMessage{
+		ID:            m.ID.String(),
+		UserID:        m.UserID.String(),
+		ChannelID:     m.ChannelID.String(),
+		Payload:       payload,
+		State:         string(m.State),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}, nil
+}
+
+// repoToServiceAdapter transforms a Message from the Repository layer to the Service layer.
+func repoToServiceAdapter(m repoMessage) (Message, error) {
+	parsedID, err := common.ParseID(m.ID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	parsedUserID, err := common.ParseID(m.UserID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	parsedChannelID, err := common.ParseID(m.ChannelID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	parsedState, err := ParseState(m.State)
+	if err != nil {
+		return Message{}, err
+	}
+
+	notification := notifier.Notification{}
+	err = json.Unmarshal(m.Payload, &notification)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		ID:            parsedID,
+		UserID:        parsedUserID,
+		ChannelID:     parsedChannelID,
+		Notification:  notification,
+		State:         parsedState,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}, nil
+}
+
+// deliveryToRepoAdapter transforms a Delivery from the Service layer to the Repository layer.
+func deliveryToRepoAdapter(d Delivery) repoDelivery {
+	return repoDelivery{
+		ID:          d.ID.String(),
+		MessageID:   d.MessageID.String(),
+		ChannelID:   d.ChannelID.String(),
+		ChannelName: d.ChannelName,
+		Domain:      d.Domain,
+		State:       string(d.State),
+		Attempt:     d.Attempt,
+		OK:          d.OK,
+		StatusCode:  d.StatusCode,
+		Response:    d.Response,
+		Error:       d.Error,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+// repoToDeliveryAdapter transforms a Delivery from the Repository layer to the Service layer.
+func repoToDeliveryAdapter(d repoDelivery) (Delivery, error) {
+	parsedID, err := common.ParseID(d.ID)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	parsedMessageID, err := common.ParseID(d.MessageID)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	parsedChannelID, err := common.ParseID(d.ChannelID)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	parsedState, err := ParseState(d.State)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return Delivery{
+		ID:          parsedID,
+		MessageID:   parsedMessageID,
+		ChannelID:   parsedChannelID,
+		ChannelName: d.ChannelName,
+		Domain:      d.Domain,
+		State:       parsedState,
+		Attempt:     d.Attempt,
+		OK:          d.OK,
+		StatusCode:  d.StatusCode,
+		Response:    d.Response,
+		Error:       d.Error,
+		CreatedAt:   d.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,8 @@
+package push
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("push subscription not found")
+	ErrTooMany  = errors.New("too many push subscriptions")
+)
@@ -0,0 +1,94 @@
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const QueryTimeout = 5 * time.Second
+
+type Repo interface {
+	Save(ctx context.Context, sub repoSubscription) error
+	Count(ctx context.Context, userID common.ID) (int, error)
+	GetAll(ctx context.Context, userID common.ID) ([]repoSubscription, error)
+	Delete(ctx context.Context, userID common.ID, endpoint string) error
+	DeleteByEndpoint(ctx context.Context, endpoint string) error
+}
+
+type PushRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *PushRepo {
+	return &PushRepo{db}
+}
+
+func (r *PushRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Save stores the subscription, a browser subscribing again with the same endpoint
+// replaces its keys and moves it to the user that subscribed last.
+func (r *PushRepo) Save(ctx context.Context, sub repoSubscription) error {
+	q := `
+    insert into push_subscriptions (id, user_id, endpoint, p256dh, auth, user_agent, created_at)
+    values ($1, $2, $3, $4, $5, $6, $7)
+    on conflict (endpoint) do update set
+      user_id = excluded.user_id,
+      p256dh = excluded.p256dh,
+      auth = excluded.auth,
+      user_agent = excluded.user_agent`
+	return r.update(ctx, q, sub.ID, sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent, sub.CreatedAt)
+}
+
+func (r *PushRepo) Count(ctx context.Context, userID common.ID) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	var count int
+	q := `select count(*) from push_subscriptions where user_id = $1`
+	err := r.db.QueryRow(ctx, q, userID).Scan(&count)
+	return count, err
+}
+
+func (r *PushRepo) GetAll(ctx context.Context, userID common.ID) ([]repoSubscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := `
+    select
+      id, user_id, endpoint, p256dh, auth, user_agent, created_at
+    from
+      push_subscriptions
+    where
+      user_id = $1
+    order by created_at`
+
+	rows, _ := r.db.Query(ctx, q, userID)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoSubscription])
+}
+
+func (r *PushRepo) Delete(ctx context.Context, userID common.ID, endpoint string) error {
+	q := `delete from push_subscriptions where user_id = $1 and endpoint = $2`
+	return r.update(ctx, q, userID, endpoint)
+}
+
+func (r *PushRepo) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	q := `delete from push_subscriptions where endpoint = $1`
+	return r.update(ctx, q, endpoint)
+}
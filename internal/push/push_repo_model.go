@@ -0,0 +1,14 @@
+package push
+
+import "time"
+
+// repoSubscription represents a Subscription in the Repository layer.
+type repoSubscription struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	Endpoint  string    `db:"endpoint"`
+	P256dh    string    `db:"p256dh"`
+	Auth      string    `db:"auth"`
+	UserAgent string    `db:"user_agent"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,125 @@
+package push
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+const maxUserAgentLength = 256
+
+type Service interface {
+	Subscribe(ctx context.Context, req SubscribeReq) (Subscription, error)
+	GetAll(ctx context.Context, req GetAllReq) ([]Subscription, error)
+	Unsubscribe(ctx context.Context, req UnsubscribeReq) error
+}
+
+type PushService struct {
+	repo                    Repo
+	maxSubscriptionsPerUser int
+}
+
+func NewService(repo Repo, maxSubscriptionsPerUser int) *PushService {
+	return &PushService{
+		repo:                    repo,
+		maxSubscriptionsPerUser: maxSubscriptionsPerUser,
+	}
+}
+
+// Subscribe saves the subscription of a browser, subscribing again from the same browser updates it.
+func (s *PushService) Subscribe(ctx context.Context, req SubscribeReq) (Subscription, error) {
+	err := req.Subscription.Validate()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	subs, err := s.repo.GetAll(ctx, req.UserID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	known := false
+	for _, sub := range subs {
+		if sub.Endpoint == req.Subscription.Endpoint {
+			known = true
+			break
+		}
+	}
+
+	if !known && len(subs) >= s.maxSubscriptionsPerUser {
+		return Subscription{}, fmt.Errorf("cannot have more than %d browsers subscribed: %w", s.maxSubscriptionsPerUser, ErrTooMany)
+	}
+
+	userAgent := req.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	sub := New(req.UserID, req.Subscription, userAgent)
+	err = s.repo.Save(ctx, serviceToRepoAdapter(sub))
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+func (s *PushService) GetAll(ctx context.Context, req GetAllReq) ([]Subscription, error) {
+	subs, err := s.repo.GetAll(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Subscription, len(subs))
+	for i, sub := range subs {
+		result[i], err = repoToServiceAdapter(sub)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (s *PushService) Unsubscribe(ctx context.Context, req UnsubscribeReq) error {
+	return s.repo.Delete(ctx, req.UserID, req.Endpoint)
+}
+
+// Store gives the Web Push notifier access to the subscriptions of users.
+type Store struct {
+	repo Repo
+}
+
+func NewStore(repo Repo) Store {
+	return Store{repo: repo}
+}
+
+func (s Store) GetSubscriptions(userID string) ([]webpush.Subscription, error) {
+	id, err := common.ParseID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+
+	subs, err := s.repo.GetAll(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]webpush.Subscription, len(subs))
+	for i, sub := range subs {
+		result[i] = webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}
+	}
+
+	return result, nil
+}
+
+func (s Store) DeleteSubscription(endpoint string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	defer cancel()
+	return s.repo.DeleteByEndpoint(ctx, endpoint)
+}
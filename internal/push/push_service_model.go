@@ -0,0 +1,87 @@
+package push
+
+import (
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+	"github.com/germandv/domainator/internal/webpush"
+)
+
+type SubscribeReq struct {
+	UserID       common.ID
+	Subscription webpush.Subscription
+	UserAgent    string
+}
+
+type GetAllReq struct {
+	UserID common.ID
+}
+
+type UnsubscribeReq struct {
+	UserID   common.ID
+	Endpoint string
+}
+
+// Subscription is a browser of the user that receives push notifications.
+type Subscription struct {
+	ID        common.ID
+	UserID    common.ID
+	Endpoint  string
+	P256dh    string
+	Auth      string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+func New(userID common.ID, sub webpush.Subscription, userAgent string) Subscription {
+	return Subscription{
+		ID:        common.NewID(),
+		UserID:    userID,
+		Endpoint:  sub.Endpoint,
+		P256dh:    sub.P256dh,
+		Auth:      sub.Auth,
+		UserAgent: userAgent,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// WebPush returns what's needed to send a push message to the subscription.
+func (s Subscription) WebPush() webpush.Subscription {
+	return webpush.Subscription{Endpoint: s.Endpoint, P256dh: s.P256dh, Auth: s.Auth}
+}
+
+// serviceToRepoAdapter transforms a Subscription from the Service layer to the Repository layer.
+func serviceToRepoAdapter(s Subscription) repoSubscription {
+	return repoSubscription{
+		ID:        s.ID.String(),
+		UserID:    s.UserID.String(),
+		Endpoint:  s.Endpoint,
+		P256dh:    s.P256dh,
+		Auth:      s.Auth,
+		UserAgent: s.UserAgent,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// repoToServiceAdapter transforms a Subscription from the Repository layer to the Service layer.
+func repoToServiceAdapter(s repoSubscription) (Subscription, error) {
+	id, err := common.ParseID(s.ID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	userID, err := common.ParseID(s.UserID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return Subscription{
+		ID:        id,
+		UserID:    userID,
+		Endpoint:  s.Endpoint,
+		P256dh:    s.P256dh,
+		Auth:      s.Auth,
+		UserAgent: s.UserAgent,
+		CreatedAt: s.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const secret = "4yxfIPahS5s15puGIDIDFqVSm09mKkyH"
+
+func TestSignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	s := New([]byte(secret))
+	value := "018ec52b-dd69-7df4-b8e7-edcdc9a3a891"
+
+	token := s.Sign("unsubscribe", value, time.Time{})
+	got, err := s.Verify("unsubscribe", token)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if got != value {
+		t.Errorf("Expected value %q, got %q", value, got)
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	t.Parallel()
+
+	s := New([]byte(secret))
+	token := s.Sign("unsubscribe", "value", time.Time{})
+
+	tt := []struct {
+		name    string
+		signer  *Signer
+		purpose string
+		token   string
+		err     error
+	}{
+		{"wrong_purpose", s, "acknowledge", token, ErrInvalidToken},
+		{"wrong_secret", New([]byte("another-secret")), "unsubscribe", token, ErrInvalidToken},
+		{"tampered", s, "unsubscribe", "x" + token, ErrInvalidToken},
+		{"malformed", s, "unsubscribe", "not-a-token", ErrInvalidToken},
+		{"empty", s, "unsubscribe", "", ErrInvalidToken},
+		{"expired", s, "unsubscribe", s.Sign("unsubscribe", "value", time.Now().Add(-time.Minute)), ErrExpiredToken},
+	}
+
+	for _, tc := range tt {
+		_, err := tc.signer.Verify(tc.purpose, tc.token)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected error %q but got %q", tc.name, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package slackapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Slack-Signature"
+	TimestampHeader = "X-Slack-Request-Timestamp"
+	// MaxRequestAge is how old a signed request can be before it's considered a replay.
+	MaxRequestAge = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid Slack signature")
+	ErrInvalidPayload   = errors.New("invalid Slack payload")
+)
+
+// VerifySignature checks that the request body was signed by Slack with the signing secret of the app
+// (https://api.slack.com/authentication/verifying-requests-from-slack) less than MaxRequestAge ago.
+func (c *Config) VerifySignature(header http.Header, body []byte, now time.Time) error {
+	if c == nil || c.SigningSecret == "" {
+		return ErrNotConfigured
+	}
+
+	timestamp := header.Get(TimestampHeader)
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(sec, 0))
+	if age > MaxRequestAge || age < -MaxRequestAge {
+		return ErrInvalidSignature
+	}
+
+	signature, found := strings.CutPrefix(header.Get(SignatureHeader), "v0=")
+	if !found {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(c.SigningSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Command is a slash command typed by a Slack user.
+type Command struct {
+	TeamID      string
+	UserID      string
+	Name        string
+	Text        string
+	ResponseURL string
+}
+
+// ParseCommand reads the form Slack posts when a slash command is used.
+func ParseCommand(form url.Values) (Command, error) {
+	cmd := Command{
+		TeamID:      form.Get("team_id"),
+		UserID:      form.Get("user_id"),
+		Name:        form.Get("command"),
+		Text:        strings.TrimSpace(form.Get("text")),
+		ResponseURL: form.Get("response_url"),
+	}
+	if cmd.TeamID == "" || cmd.UserID == "" {
+		return Command{}, ErrInvalidPayload
+	}
+	return cmd, nil
+}
+
+// Action is a button of a message pressed by a Slack user.
+type Action struct {
+	TeamID      string
+	UserID      string
+	ActionID    string
+	Value       string
+	ResponseURL string
+}
+
+type actionPayload struct {
+	Type string `json:"type"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// ParseAction reads the `payload` Slack posts when a button is pressed, only block actions are supported.
+func ParseAction(form url.Values) (Action, error) {
+	p := actionPayload{}
+	err := json.Unmarshal([]byte(form.Get("payload")), &p)
+	if err != nil || p.Type != "block_actions" || len(p.Actions) == 0 || p.Team.ID == "" || p.User.ID == "" {
+		return Action{}, ErrInvalidPayload
+	}
+
+	return Action{
+		TeamID:      p.Team.ID,
+		UserID:      p.User.ID,
+		ActionID:    p.Actions[0].ActionID,
+		Value:       p.Actions[0].Value,
+		ResponseURL: p.ResponseURL,
+	}, nil
+}
+
+// Message is a reply to a command or action, only visible to the user unless InChannel is set.
+type Message struct {
+	ResponseType string           `json:"response_type"`
+	Text         string           `json:"text"`
+	Blocks       []map[string]any `json:"blocks,omitempty"`
+	// ReplaceOriginal is false so that replies to buttons don't overwrite the alert.
+	ReplaceOriginal bool `json:"replace_original"`
+}
+
+func NewMessage(text string, blocks ...map[string]any) Message {
+	return Message{ResponseType: "ephemeral", Text: text, Blocks: blocks}
+}
+
+// Respond posts a delayed reply to the response URL of a command or action, which is valid for 30 minutes.
+func Respond(ctx context.Context, responseURL string, msg Message) error {
+	u, err := url.Parse(responseURL)
+	if err != nil || u.Scheme != "https" {
+		return fmt.Errorf("invalid response URL: %w", ErrInvalidPayload)
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error (%d) replying to Slack", resp.StatusCode)
+	}
+	return nil
+}
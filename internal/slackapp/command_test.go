@@ -0,0 +1,78 @@
+package slackapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewSlackConfig("id", "secret", "signing-secret", "http://localhost/slack/callback")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	old := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	body := "team_id=T1&user_id=U1&command=%2Fdomainator&text=status"
+
+	tt := []struct {
+		name      string
+		cfg       *Config
+		timestamp string
+		signature string
+		want      error
+	}{
+		{"valid", cfg, ts, sign("signing-secret", ts, body), nil},
+		{"wrong secret", cfg, ts, sign("other", ts, body), ErrInvalidSignature},
+		{"old request", cfg, old, sign("signing-secret", old, body), ErrInvalidSignature},
+		{"missing timestamp", cfg, "", sign("signing-secret", ts, body), ErrInvalidSignature},
+		{"missing version", cfg, ts, sign("signing-secret", ts, body)[3:], ErrInvalidSignature},
+		{"not configured", NewSlackConfig("id", "secret", " ", ""), ts, sign("", ts, body), ErrNotConfigured},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			header.Set(TimestampHeader, tc.timestamp)
+			header.Set(SignatureHeader, tc.signature)
+			err := tc.cfg.VerifySignature(header, []byte(body), now)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"type": "block_actions", "team": {"id": "T1"}, "user": {"id": "U1"}, "response_url": "https://hooks.slack.com/actions/1",
+    "actions": [{"action_id": "recheck", "value": "018ec52b-dd69-7df4-b8e7-edcdc9a3a891"}]}`
+
+	action, err := ParseAction(url.Values{"payload": {payload}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	want := Action{TeamID: "T1", UserID: "U1", ActionID: "recheck", Value: "018ec52b-dd69-7df4-b8e7-edcdc9a3a891", ResponseURL: "https://hooks.slack.com/actions/1"}
+	if action != want {
+		t.Errorf("Expected %+v, got %+v", want, action)
+	}
+
+	_, err = ParseAction(url.Values{"payload": {`{"type": "view_submission", "team": {"id": "T1"}, "user": {"id": "U1"}}`}})
+	if !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("Expected ErrInvalidPayload, got %v", err)
+	}
+}
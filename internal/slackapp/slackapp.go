@@ -0,0 +1,190 @@
+// Package slackapp installs the Domainator Slack app in a workspace (OAuth v2)
+// and wraps the few Web API methods needed to configure it.
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AuthorizeURL = "https://slack.com/oauth/v2/authorize"
+	APIURL       = "https://slack.com/api"
+)
+
+// Scopes are the bot scopes requested on installation:
+// posting to the channel picked during installation (or any public one), listing channels and slash commands.
+var Scopes = []string{"chat:write", "chat:write.public", "channels:read", "groups:read", "incoming-webhook", "commands"}
+
+var ErrNotConfigured = errors.New("the Slack app is not configured")
+
+var client = &http.Client{Timeout: 5 * time.Second}
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// SigningSecret verifies the slash commands and interactive actions sent by Slack.
+	SigningSecret string
+	RedirectURL   string
+	APIURL        string
+}
+
+func NewSlackConfig(clientID string, secret string, signingSecret string, redirectURL string) *Config {
+	return &Config{
+		ClientID:      strings.TrimSpace(clientID),
+		ClientSecret:  strings.TrimSpace(secret),
+		SigningSecret: strings.TrimSpace(signingSecret),
+		RedirectURL:   redirectURL,
+		APIURL:        APIURL,
+	}
+}
+
+// Enabled reports whether the app credentials are set, otherwise users can only paste webhooks.
+func (c *Config) Enabled() bool {
+	return c != nil && c.ClientID != "" && c.ClientSecret != ""
+}
+
+// AuthCodeURL returns the "Add to Slack" URL.
+func (c *Config) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", c.ClientID)
+	q.Set("scope", strings.Join(Scopes, ","))
+	q.Set("redirect_uri", c.RedirectURL)
+	q.Set("state", state)
+	return AuthorizeURL + "?" + q.Encode()
+}
+
+// Installation is the result of installing the app in a workspace.
+type Installation struct {
+	BotToken    string
+	BotUserID   string
+	TeamID      string
+	TeamName    string
+	SlackUserID string
+	ChannelID   string
+	ChannelName string
+}
+
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+type oauthResponse struct {
+	apiResponse
+	AccessToken string `json:"access_token"`
+	BotUserID   string `json:"bot_user_id"`
+	Team        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"team"`
+	AuthedUser struct {
+		ID string `json:"id"`
+	} `json:"authed_user"`
+	IncomingWebhook struct {
+		Channel   string `json:"channel"`
+		ChannelID string `json:"channel_id"`
+	} `json:"incoming_webhook"`
+}
+
+// Exchange trades the code Slack redirected the user with for the bot token of the installation.
+func (c *Config) Exchange(ctx context.Context, code string) (Installation, error) {
+	if !c.Enabled() {
+		return Installation{}, ErrNotConfigured
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+
+	res := oauthResponse{}
+	err := call(ctx, http.MethodPost, c.APIURL+"/oauth.v2.access", "", strings.NewReader(form.Encode()), &res)
+	if err != nil {
+		return Installation{}, err
+	}
+	if !res.OK {
+		return Installation{}, fmt.Errorf("error installing the Slack app: %s", res.Error)
+	}
+
+	return Installation{
+		BotToken:    res.AccessToken,
+		BotUserID:   res.BotUserID,
+		TeamID:      res.Team.ID,
+		TeamName:    res.Team.Name,
+		SlackUserID: res.AuthedUser.ID,
+		ChannelID:   res.IncomingWebhook.ChannelID,
+		ChannelName: strings.TrimPrefix(res.IncomingWebhook.Channel, "#"),
+	}, nil
+}
+
+// Conversation is a Slack channel the app can post to.
+type Conversation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type conversationsResponse struct {
+	apiResponse
+	Channels []Conversation `json:"channels"`
+}
+
+// ListChannels returns the public and private channels visible to the bot, sorted by name.
+func ListChannels(ctx context.Context, apiURL string, token string) ([]Conversation, error) {
+	q := url.Values{}
+	q.Set("types", "public_channel,private_channel")
+	q.Set("exclude_archived", "true")
+	q.Set("limit", "1000")
+
+	res := conversationsResponse{}
+	err := call(ctx, http.MethodGet, apiURL+"/conversations.list?"+q.Encode(), token, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !res.OK {
+		return nil, fmt.Errorf("error listing Slack channels: %s", res.Error)
+	}
+
+	sort.Slice(res.Channels, func(i, j int) bool { return res.Channels[i].Name < res.Channels[j].Name })
+	return res.Channels, nil
+}
+
+func call(ctx context.Context, method string, url string, token string, body *strings.Reader, out any) error {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequestWithContext(ctx, method, url, body)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error (%d) calling the Slack API", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
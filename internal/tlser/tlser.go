@@ -1,9 +1,17 @@
 package tlser
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"net"
+	"strings"
 	"time"
+
+	"github.com/germandv/domainator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CertStatus string
@@ -16,47 +24,124 @@ const (
 	StatusIssuerNotFound   CertStatus = "IssuerNotFound"
 )
 
+// DefaultPort is where certificates are checked when the domain doesn't have a port.
+const DefaultPort = "443"
+
 type CertData struct {
 	Status CertStatus
 	Expiry time.Time
 	Issuer string
+	// Chain is the chain the server sent, leaf first, when it could connect.
+	Chain []Certificate
+	// Err is what went wrong when the status is CannotConnect, HostnameMismatch or IssuerNotFound.
+	Err error
+}
+
+// Certificate is a certificate of the chain sent by the server.
+type Certificate struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	DNSNames  []string
 }
 
 type Client interface {
-	GetCertData(domain string) CertData
+	GetCertData(ctx context.Context, domain string) CertData
 }
 
 type TLSer struct {
 	timeout time.Duration
+	// roots are the trusted CAs, nil for those of the system.
+	roots *x509.CertPool
 }
 
 func New(timeout time.Duration) *TLSer {
-	return &TLSer{timeout}
+	return &TLSer{timeout: timeout}
 }
 
-func (t TLSer) GetCertData(domain string) CertData {
-	dialer := net.Dialer{Timeout: t.timeout}
-	conn, err := tls.DialWithDialer(&dialer, "tcp", domain+":443", nil)
+// GetCertData checks the certificate of the domain, on port 443 unless it's given as "example.com:8443".
+func (t TLSer) GetCertData(ctx context.Context, domain string) CertData {
+	ctx, span := tracing.Start(ctx, "tlser.probe", trace.SpanKindClient, attribute.String("domain", domain))
+	data := t.probe(ctx, domain)
+
+	span.SetAttributes(attribute.String("tls.status", string(data.Status)))
+	tracing.End(span, data.Err)
+	return data
+}
+
+func (t TLSer) probe(ctx context.Context, domain string) CertData {
+	host, addr := SplitDomain(domain)
+
+	// The handshake accepts any certificate, it's verified below to tell what's wrong with it,
+	// otherwise every invalid certificate would be a handshake error like a network one.
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: t.timeout},
+		Config:    &tls.Config{ServerName: host, InsecureSkipVerify: true},
+	}
+	c, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
-		return CertData{Status: StatusCannotConnect}
+		return CertData{Status: StatusCannotConnect, Err: err}
 	}
+	conn := c.(*tls.Conn)
 	defer conn.Close()
 
-	err = conn.VerifyHostname(domain)
+	peerCerts := conn.ConnectionState().PeerCertificates
+	if len(peerCerts) == 0 {
+		return CertData{Status: StatusCannotConnect, Err: errors.New("the server sent no certificate")}
+	}
+	chain := chainOf(peerCerts)
+	leaf := peerCerts[0]
+
+	err = leaf.VerifyHostname(host)
 	if err != nil {
-		return CertData{Status: StatusHostnameMismatch}
+		return CertData{Status: StatusHostnameMismatch, Chain: chain, Err: err}
 	}
 
 	now := time.Now()
-	expiry := conn.ConnectionState().PeerCertificates[0].NotAfter
+	expiry := leaf.NotAfter
 	if expiry.Before(now) {
-		return CertData{Status: StatusExpired, Expiry: expiry}
+		return CertData{Status: StatusExpired, Expiry: expiry, Chain: chain}
 	}
 
-	cas := conn.ConnectionState().PeerCertificates[0].Issuer.Organization
+	intermediates := x509.NewCertPool()
+	for _, c := range peerCerts[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: t.roots, Intermediates: intermediates, CurrentTime: now})
+	if err != nil {
+		return CertData{Status: StatusIssuerNotFound, Chain: chain, Err: err}
+	}
+
+	cas := leaf.Issuer.Organization
 	if len(cas) == 0 {
-		return CertData{Status: StatusIssuerNotFound}
+		return CertData{Status: StatusIssuerNotFound, Chain: chain}
+	}
+
+	return CertData{Status: StatusOK, Expiry: expiry, Issuer: cas[0], Chain: chain}
+}
+
+// SplitDomain returns the host of the domain and the address to connect to,
+// with DefaultPort when it doesn't have one.
+func SplitDomain(domain string) (string, string) {
+	host, port, err := net.SplitHostPort(domain)
+	if err != nil || port == "" {
+		host = strings.TrimSuffix(domain, ":")
+		port = DefaultPort
 	}
+	return host, net.JoinHostPort(host, port)
+}
 
-	return CertData{Status: StatusOK, Expiry: expiry, Issuer: cas[0]}
+func chainOf(certs []*x509.Certificate) []Certificate {
+	chain := make([]Certificate, len(certs))
+	for i, c := range certs {
+		chain[i] = Certificate{
+			Subject:   c.Subject.String(),
+			Issuer:    c.Issuer.String(),
+			NotBefore: c.NotBefore,
+			NotAfter:  c.NotAfter,
+			DNSNames:  c.DNSNames,
+		}
+	}
+	return chain
 }
@@ -0,0 +1,93 @@
+package tlser
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/tlsertest"
+)
+
+func TestSplitDomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		domain string
+		host   string
+		addr   string
+	}{
+		{"example.com", "example.com", "example.com:443"},
+		{"example.com:8443", "example.com", "example.com:8443"},
+		{"example.com:", "example.com", "example.com:443"},
+		{"[::1]:8443", "::1", "[::1]:8443"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.domain, func(t *testing.T) {
+			t.Parallel()
+
+			host, addr := SplitDomain(tc.domain)
+			if host != tc.host {
+				t.Errorf("Expected host %s, got %s", tc.host, host)
+			}
+			if addr != tc.addr {
+				t.Errorf("Expected address %s, got %s", tc.addr, addr)
+			}
+		})
+	}
+}
+
+func TestGetCertData(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	valid, validCert := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+	expired, expiredCert := tlsertest.NewServer(t, []string{"127.0.0.1"}, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	mismatch, mismatchCert := tlsertest.NewServer(t, []string{"example.com"}, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+
+	roots := x509.NewCertPool()
+	for _, c := range []*x509.Certificate{validCert, expiredCert, mismatchCert} {
+		roots.AddCert(c)
+	}
+
+	// A listener closed right away leaves a port nothing listens on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name   string
+		domain string
+		roots  *x509.CertPool
+		status CertStatus
+	}{
+		{"ok", valid, roots, StatusOK},
+		{"untrusted", valid, nil, StatusIssuerNotFound},
+		{"expired", expired, nil, StatusExpired},
+		{"hostname_mismatch", mismatch, roots, StatusHostnameMismatch},
+		{"cannot_connect", closed, roots, StatusCannotConnect},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &TLSer{timeout: 5 * time.Second, roots: tc.roots}
+			data := client.GetCertData(context.Background(), tc.domain)
+			if data.Status != tc.status {
+				t.Errorf("Expected status %s, got %s (%v)", tc.status, data.Status, data.Err)
+			}
+			if tc.status == StatusOK && data.Issuer != tlsertest.Organization {
+				t.Errorf("Expected issuer %s, got %s", tlsertest.Organization, data.Issuer)
+			}
+			if tc.status == StatusExpired && !data.Expiry.Equal(expiredCert.NotAfter) {
+				t.Errorf("Expected expiry %s, got %s", expiredCert.NotAfter, data.Expiry)
+			}
+		})
+	}
+}
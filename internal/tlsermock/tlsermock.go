@@ -1,6 +1,7 @@
 package tlsermock
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -13,7 +14,7 @@ func New() *MockTLSer {
 	return &MockTLSer{}
 }
 
-func (m MockTLSer) GetCertData(domain string) tlser.CertData {
+func (m MockTLSer) GetCertData(_ context.Context, domain string) tlser.CertData {
 	if strings.Contains(domain, "expired") {
 		return tlser.CertData{
 			Status: tlser.StatusExpired,
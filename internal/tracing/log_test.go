@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/germandv/domainator/internal/cntxt"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogHandler(t *testing.T) {
+	t.Parallel()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r = cntxt.SetRequestID(r.WithContext(trace.ContextWithSpanContext(r.Context(), sc)), "req-1")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{"request", r.Context(), map[string]string{"trace_id": traceID.String(), "span_id": spanID.String(), "request_id": "req-1"}},
+		{"background", context.Background(), map[string]string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			logger := slog.New(NewLogHandler(slog.NewJSONHandler(buf, nil))).With("app", "test")
+			logger.InfoContext(tc.ctx, "hello")
+
+			var record map[string]any
+			err := json.Unmarshal(buf.Bytes(), &record)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if record["app"] != "test" {
+				t.Errorf("Expected the attributes of the logger to be kept, got %v", record)
+			}
+			for _, key := range []string{"trace_id", "span_id", "request_id"} {
+				want, found := tc.want[key]
+				if !found {
+					if _, got := record[key]; got {
+						t.Errorf("Expected no %s, got %v", key, record[key])
+					}
+					continue
+				}
+				if record[key] != want {
+					t.Errorf("Expected %s %q, got %v", key, want, record[key])
+				}
+			}
+		})
+	}
+}
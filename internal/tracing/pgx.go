@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLength caps the SQL kept in the spans.
+const maxStatementLength = 1000
+
+// QueryTracer is a pgx.QueryTracer with a span for each query.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	sql := strings.Join(strings.Fields(data.SQL), " ")
+	if len(sql) > maxStatementLength {
+		sql = sql[:maxStatementLength]
+	}
+
+	ctx, _ = Start(ctx, "postgres "+operation(sql), trace.SpanKindClient,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBStatement(sql),
+		attribute.Int("db.args", len(data.Args)),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+
+	err := data.Err
+	if err == pgx.ErrNoRows {
+		err = nil
+	}
+	End(span, err)
+}
+
+// operation is the first word of the statement, e.g. "select".
+func operation(sql string) string {
+	op, _, _ := strings.Cut(sql, " ")
+	return strings.ToLower(op)
+}
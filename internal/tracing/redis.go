@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook is a redis.Hook with a span for each command and pipeline.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := Start(ctx, "redis "+cmd.Name(), trace.SpanKindClient,
+			semconv.DBSystemRedis,
+			semconv.DBOperation(cmd.Name()),
+		)
+		err := next(ctx, cmd)
+		End(span, redisErr(err))
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := Start(ctx, "redis pipeline", trace.SpanKindClient,
+			semconv.DBSystemRedis,
+			attribute.Int("db.redis.commands", len(cmds)),
+		)
+		err := next(ctx, cmds)
+		End(span, redisErr(err))
+		return err
+	}
+}
+
+// redisErr ignores redis.Nil, a missing key isn't a failure.
+func redisErr(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+var _ redis.Hook = RedisHook{}
@@ -0,0 +1,142 @@
+// Package updates tells the open dashboards that a cert changed. Updates are published
+// to a Redis channel, by the worker or any web instance, and every web instance relays
+// them to the dashboards of the user connected to it.
+package updates
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/germandv/domainator/internal/cache"
+)
+
+// Channel is the Redis channel the updates are published to.
+const Channel = "domainator_cert_updates"
+
+// subscriberBuffer is how many updates a dashboard can fall behind before they are dropped.
+const subscriberBuffer = 16
+
+// retryInterval is how long Run waits to subscribe again when Redis is unavailable.
+const retryInterval = 5 * time.Second
+
+type Update struct {
+	UserID string `json:"user_id"`
+	CertID string `json:"cert_id"`
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, update Update) error
+}
+
+type Broker struct {
+	cacheClient cache.Client
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan Update]struct{}
+	closed      bool
+}
+
+func NewBroker(cacheClient cache.Client) *Broker {
+	return &Broker{
+		cacheClient: cacheClient,
+		subscribers: make(map[string]map[chan Update]struct{}),
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, update Update) error {
+	msg, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	return b.cacheClient.Publish(ctx, Channel, string(msg))
+}
+
+// Run relays the published updates to the subscribers until ctx is done, subscribing
+// again when Redis is unavailable. Then the channels of the subscribers are closed.
+func (b *Broker) Run(ctx context.Context, logger *slog.Logger) {
+	defer b.close()
+
+	for ctx.Err() == nil {
+		messages, err := b.cacheClient.Subscribe(ctx, Channel)
+		if err != nil {
+			logger.Error("failed to subscribe to cert updates", "error", err.Error())
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		for msg := range messages {
+			var update Update
+			err := json.Unmarshal([]byte(msg), &update)
+			if err != nil {
+				logger.Warn("invalid cert update", "message", msg, "error", err.Error())
+				continue
+			}
+			b.relay(update)
+		}
+	}
+}
+
+// Subscribe returns the updates of the certs of the user, until unsubscribe is called or the broker stops.
+func (b *Broker) Subscribe(userID string) (<-chan Update, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Update, subscriberBuffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan Update]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, found := b.subscribers[userID][ch]; !found {
+			return
+		}
+		delete(b.subscribers[userID], ch)
+		if len(b.subscribers[userID]) == 0 {
+			delete(b.subscribers, userID)
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// relay doesn't wait for subscribers that fell behind, they miss the update.
+func (b *Broker) relay(update Update) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[update.UserID] {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
+
+func (b *Broker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subs := range b.subscribers {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	b.subscribers = make(map[string]map[chan Update]struct{})
+	b.closed = true
+}
@@ -0,0 +1,88 @@
+package updates
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/cachemock"
+)
+
+func TestBroker(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(cachemock.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	alice, unsubscribeAlice := broker.Subscribe("alice")
+	defer unsubscribeAlice()
+	bob, unsubscribeBob := broker.Subscribe("bob")
+
+	done := make(chan struct{})
+	go func() {
+		broker.Run(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		close(done)
+	}()
+
+	// Updates published before Run subscribes are missed, so publish until one arrives.
+	var received Update
+	deadline := time.After(time.Second)
+	for received.CertID == "" {
+		err := broker.Publish(ctx, Update{UserID: "alice", CertID: "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case received = <-alice:
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("Expected an update for alice, got none")
+		}
+	}
+	if received.CertID != "1" {
+		t.Errorf("Expected cert 1, got %s", received.CertID)
+	}
+
+	select {
+	case u := <-bob:
+		t.Errorf("Expected no update for bob, got %v", u)
+	default:
+	}
+
+	unsubscribeBob()
+	if _, ok := <-bob; ok {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+	unsubscribeBob()
+
+	cancel()
+	<-done
+	if _, ok := <-alice; ok {
+		t.Error("Expected the channel to be closed when the broker stops")
+	}
+
+	late, _ := broker.Subscribe("alice")
+	if _, ok := <-late; ok {
+		t.Error("Expected a closed channel when subscribing to a stopped broker")
+	}
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(cachemock.New())
+	ch, unsubscribe := broker.Subscribe("alice")
+	defer unsubscribe()
+
+	for range subscriberBuffer + 5 {
+		broker.relay(Update{UserID: "alice", CertID: "1"})
+	}
+
+	if len(ch) != subscriberBuffer {
+		t.Errorf("Expected %d buffered updates, got %d", subscriberBuffer, len(ch))
+	}
+}
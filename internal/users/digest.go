@@ -0,0 +1,87 @@
+package users
+
+import (
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+const (
+	MaxDigestDays     = 90
+	DefaultDigestDays = 14
+)
+
+// Frequency of the digest reports sent to a user.
+type Frequency string
+
+const (
+	FrequencyOff    Frequency = "off"
+	FrequencyDaily  Frequency = "daily"
+	FrequencyWeekly Frequency = "weekly"
+)
+
+func ParseFrequency(frequency string) (Frequency, error) {
+	switch f := Frequency(strings.TrimSpace(frequency)); f {
+	case FrequencyOff, FrequencyDaily, FrequencyWeekly:
+		return f, nil
+	default:
+		return "", ErrInvalidFrequency
+	}
+}
+
+// Digest is the schedule of the digest reports of a user,
+// sent at Hour in the timezone of the user, on Weekday if weekly.
+type Digest struct {
+	Frequency Frequency
+	Weekday   time.Weekday
+	Hour      int
+	// Days is how far ahead to look for expiring certificates.
+	Days int
+	// ChannelID is the channel digests are delivered through, all of them if zero.
+	ChannelID common.ID
+	LastSent  time.Time
+}
+
+func NewDigest(frequency Frequency, weekday int, hour int, days int, channelID common.ID) (Digest, error) {
+	if weekday < 0 || weekday > 6 || hour < 0 || hour > 23 || days < 1 || days > MaxDigestDays {
+		return Digest{}, ErrInvalidDigest
+	}
+
+	return Digest{
+		Frequency: frequency,
+		Weekday:   time.Weekday(weekday),
+		Hour:      hour,
+		Days:      days,
+		ChannelID: channelID,
+	}, nil
+}
+
+// Due reports whether a digest has to be sent at now, that is if the last one
+// was sent before the most recent scheduled time, in the given timezone.
+func (d Digest) Due(now time.Time, tz Timezone) bool {
+	if d.Frequency != FrequencyDaily && d.Frequency != FrequencyWeekly {
+		return false
+	}
+
+	local := now.In(tz.Location())
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), d.Hour, 0, 0, 0, local.Location())
+	if scheduled.After(local) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+
+	if d.Frequency == FrequencyWeekly {
+		back := (int(scheduled.Weekday()) - int(d.Weekday) + 7) % 7
+		scheduled = scheduled.AddDate(0, 0, -back)
+	}
+
+	return d.LastSent.Before(scheduled)
+}
+
+// Period is the time span covered by a digest of the given frequency.
+func (f Frequency) Period() time.Duration {
+	if f == FrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,98 @@
+package users
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/common"
+)
+
+func TestDigestDue(t *testing.T) {
+	t.Parallel()
+
+	madrid, err := ParseTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday 2026-10-19 08:30 in Madrid (UTC+2).
+	now := time.Date(2026, 10, 19, 6, 30, 0, 0, time.UTC)
+
+	tt := []struct {
+		name     string
+		digest   Digest
+		tz       Timezone
+		expected bool
+	}{
+		{"off", Digest{Frequency: FrequencyOff, Hour: 8}, madrid, false},
+		{"daily never sent", Digest{Frequency: FrequencyDaily, Hour: 8}, madrid, true},
+		{"daily already sent", Digest{Frequency: FrequencyDaily, Hour: 8, LastSent: now.Add(-10 * time.Minute)}, madrid, false},
+		{"daily sent yesterday", Digest{Frequency: FrequencyDaily, Hour: 8, LastSent: now.Add(-24 * time.Hour)}, madrid, true},
+		{"daily later today", Digest{Frequency: FrequencyDaily, Hour: 9, LastSent: now.Add(-20 * time.Hour)}, madrid, false},
+		{"daily in UTC not yet", Digest{Frequency: FrequencyDaily, Hour: 8, LastSent: now.Add(-20 * time.Hour)}, Timezone{}, false},
+		{"weekly today", Digest{Frequency: FrequencyWeekly, Weekday: time.Monday, Hour: 8, LastSent: now.Add(-48 * time.Hour)}, madrid, true},
+		{"weekly other day", Digest{Frequency: FrequencyWeekly, Weekday: time.Friday, Hour: 8, LastSent: now.Add(-48 * time.Hour)}, madrid, false},
+		{"weekly missed", Digest{Frequency: FrequencyWeekly, Weekday: time.Friday, Hour: 8, LastSent: now.Add(-8 * 24 * time.Hour)}, madrid, true},
+	}
+
+	for _, tc := range tt {
+		got := tc.digest.Due(now, tc.tz)
+		if got != tc.expected {
+			t.Errorf("%s: expected due %t, got %t", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestNewDigest(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		weekday int
+		hour    int
+		days    int
+		err     error
+	}{
+		{1, 8, 14, nil},
+		{0, 0, 1, nil},
+		{6, 23, MaxDigestDays, nil},
+		{7, 8, 14, ErrInvalidDigest},
+		{1, 24, 14, ErrInvalidDigest},
+		{1, 8, 0, ErrInvalidDigest},
+		{1, 8, MaxDigestDays + 1, ErrInvalidDigest},
+	}
+
+	for _, tc := range tt {
+		_, err := NewDigest(FrequencyDaily, tc.weekday, tc.hour, tc.days, common.ID{})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v for %+v, got %v", tc.err, tc, err)
+		}
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{"Europe/Madrid", "Europe/Madrid", nil},
+		{" America/New_York ", "America/New_York", nil},
+		{"UTC", "UTC", nil},
+		{"", "", ErrInvalidTimezone},
+		{"Local", "", ErrInvalidTimezone},
+		{"Mars/Olympus", "", ErrInvalidTimezone},
+	}
+
+	for _, tc := range tt {
+		tz, err := ParseTimezone(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v for %q, got %v", tc.err, tc.input, err)
+		}
+		if err == nil && tz.String() != tc.expected {
+			t.Errorf("Expected timezone %q, got %q", tc.expected, tz.String())
+		}
+	}
+}
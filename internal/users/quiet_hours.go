@@ -0,0 +1,83 @@
+package users
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietHours is a daily window, in the timezone of the user, during which
+// non-critical notifications are held back. It wraps around midnight if End is before Start.
+type QuietHours struct {
+	Enabled bool
+	// Start and End are minutes since midnight.
+	Start int
+	End   int
+}
+
+// ParseQuietHours parses a window given as "HH:MM" start and end times.
+func ParseQuietHours(enabled bool, start string, end string) (QuietHours, error) {
+	if !enabled {
+		return QuietHours{}, nil
+	}
+
+	s, err := time.Parse("15:04", start)
+	if err != nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+
+	e, err := time.Parse("15:04", end)
+	if err != nil {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+
+	q := QuietHours{Enabled: true, Start: s.Hour()*60 + s.Minute(), End: e.Hour()*60 + e.Minute()}
+	if q.Start == q.End {
+		return QuietHours{}, ErrInvalidQuietHours
+	}
+
+	return q, nil
+}
+
+// Until returns when the window containing now ends, and false if now isn't within the window.
+func (q QuietHours) Until(now time.Time, tz Timezone) (time.Time, bool) {
+	if !q.Enabled || q.Start == q.End {
+		return time.Time{}, false
+	}
+
+	local := now.In(tz.Location())
+	minute := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	end := func(days int) time.Time {
+		return midnight.AddDate(0, 0, days).Add(time.Duration(q.End) * time.Minute)
+	}
+
+	if q.Start < q.End {
+		if minute >= q.Start && minute < q.End {
+			return end(0), true
+		}
+		return time.Time{}, false
+	}
+
+	// The window wraps around midnight, e.g. from 22:00 to 07:00.
+	if minute >= q.Start {
+		return end(1), true
+	}
+	if minute < q.End {
+		return end(0), true
+	}
+	return time.Time{}, false
+}
+
+// StartString returns the start of the window as "HH:MM".
+func (q QuietHours) StartString() string {
+	return formatMinutes(q.Start)
+}
+
+// EndString returns the end of the window as "HH:MM".
+func (q QuietHours) EndString() string {
+	return formatMinutes(q.End)
+}
+
+func formatMinutes(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
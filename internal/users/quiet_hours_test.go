@@ -0,0 +1,81 @@
+package users
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursUntil(t *testing.T) {
+	t.Parallel()
+
+	madrid, err := ParseTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	night, err := ParseQuietHours(true, "22:00", "07:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lunch, err := ParseQuietHours(true, "13:00", "15:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Times in UTC, Madrid is UTC+2 in October.
+	tt := []struct {
+		name     string
+		quiet    QuietHours
+		now      time.Time
+		expected time.Time
+		within   bool
+	}{
+		{"disabled", QuietHours{}, time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"before midnight", night, time.Date(2026, 10, 19, 21, 0, 0, 0, time.UTC), time.Date(2026, 10, 20, 5, 30, 0, 0, time.UTC), true},
+		{"after midnight", night, time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC), true},
+		{"outside night", night, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"end is excluded", night, time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC), time.Time{}, false},
+		{"within lunch", lunch, time.Date(2026, 10, 19, 11, 15, 0, 0, time.UTC), time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC), true},
+		{"outside lunch", lunch, time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC), time.Time{}, false},
+	}
+
+	for _, tc := range tt {
+		until, within := tc.quiet.Until(tc.now, madrid)
+		if within != tc.within {
+			t.Errorf("%s: expected within %t, got %t", tc.name, tc.within, within)
+		}
+		if !until.Equal(tc.expected) {
+			t.Errorf("%s: expected until %s, got %s", tc.name, tc.expected, until)
+		}
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		enabled bool
+		start   string
+		end     string
+		err     error
+	}{
+		{true, "22:00", "07:00", nil},
+		{true, "00:00", "23:59", nil},
+		{false, "", "", nil},
+		{true, "22:00", "22:00", ErrInvalidQuietHours},
+		{true, "24:00", "07:00", ErrInvalidQuietHours},
+		{true, "", "07:00", ErrInvalidQuietHours},
+	}
+
+	for _, tc := range tt {
+		q, err := ParseQuietHours(tc.enabled, tc.start, tc.end)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("Expected error %v for %+v, got %v", tc.err, tc, err)
+		}
+		if err == nil && tc.enabled && (q.StartString() != tc.start || q.EndString() != tc.end) {
+			t.Errorf("Expected %s-%s, got %s-%s", tc.start, tc.end, q.StartString(), q.EndString())
+		}
+	}
+}
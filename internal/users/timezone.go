@@ -0,0 +1,39 @@
+package users
+
+import (
+	"strings"
+	"time"
+	// Embedded so timezones work on systems without the IANA database, e.g. scratch containers.
+	_ "time/tzdata"
+)
+
+// Timezone is an IANA timezone, e.g. Europe/Madrid.
+type Timezone struct {
+	location *time.Location
+}
+
+func ParseTimezone(name string) (Timezone, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return Timezone{}, ErrInvalidTimezone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return Timezone{}, ErrInvalidTimezone
+	}
+
+	return Timezone{location: location}, nil
+}
+
+// Location returns the location of the timezone, UTC for the zero value.
+func (tz Timezone) Location() *time.Location {
+	if tz.location == nil {
+		return time.UTC
+	}
+	return tz.location
+}
+
+func (tz Timezone) String() string {
+	return tz.Location().String()
+}
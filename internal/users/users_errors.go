@@ -3,7 +3,11 @@ package users
 import "errors"
 
 var (
-	ErrInvalidEmail   = errors.New("email is required and must be a valid email address")
-	ErrDuplicateEmail = errors.New("email already exists")
-	ErrNotFound       = errors.New("user not found")
+	ErrInvalidEmail      = errors.New("email is required and must be a valid email address")
+	ErrDuplicateEmail    = errors.New("email already exists")
+	ErrNotFound          = errors.New("user not found")
+	ErrInvalidTimezone   = errors.New("invalid timezone, use an IANA name like Europe/Madrid")
+	ErrInvalidFrequency  = errors.New("invalid digest frequency, use one of 'off', 'daily' or 'weekly'")
+	ErrInvalidDigest     = errors.New("invalid digest schedule")
+	ErrInvalidQuietHours = errors.New("invalid quiet hours, use different HH:MM start and end times")
 )
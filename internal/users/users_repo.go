@@ -17,7 +17,13 @@ type Repo interface {
 	Save(ctx context.Context, user repoUser) error
 	GetByEmail(ctx context.Context, email Email) (repoUser, error)
 	GetByID(ctx context.Context, userID common.ID) (repoUser, error)
-	SetWebhookURL(ctx context.Context, userID common.ID, url common.URL) error
+	GetWithDigest(ctx context.Context) ([]repoUser, error)
+	SetTimezone(ctx context.Context, userID common.ID, timezone string) error
+	SetDigest(ctx context.Context, user repoUser) error
+	SetDigestSent(ctx context.Context, userID common.ID, sentAt time.Time) error
+	SetQuietHours(ctx context.Context, userID common.ID, enabled bool, start int, end int) error
+	GetByFeedToken(ctx context.Context, token string) (repoUser, error)
+	SetFeedToken(ctx context.Context, userID common.ID, token string) error
 }
 
 type UsersRepo struct {
@@ -49,24 +55,38 @@ func (r *UsersRepo) Save(ctx context.Context, user repoUser) error {
 	return err
 }
 
-func (r *UsersRepo) get(ctx context.Context, key string, value string) (repoUser, error) {
-	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
-	defer cancel()
-
-	q := fmt.Sprintf(`
-    select
+const userColumns = `
       id,
       name,
       email,
       created_at,
       identity_provider,
       identity_provider_id,
-      coalesce(webhook_url, '') as webhook_url,
-      coalesce(avatar_url, '') as avatar_url
+      coalesce(avatar_url, '') as avatar_url,
+      timezone,
+      digest_frequency,
+      digest_weekday,
+      digest_hour,
+      digest_days,
+      coalesce(digest_channel_id::text, '') as digest_channel_id,
+      coalesce(digest_sent_at, 'epoch'::timestamp) as digest_sent_at,
+      quiet_hours_enabled,
+      quiet_hours_start,
+      quiet_hours_end,
+      coalesce(feed_token, '') as feed_token`
+
+func (r *UsersRepo) get(ctx context.Context, key string, value string) (repoUser, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	q := fmt.Sprintf(`
+    select
+      %s
     from
       users
     where
       %s = $1`,
+		userColumns,
 		key,
 	)
 
@@ -90,11 +110,72 @@ func (r *UsersRepo) GetByID(ctx context.Context, userID common.ID) (repoUser, er
 	return r.get(ctx, "id", userID.String())
 }
 
-func (r *UsersRepo) SetWebhookURL(ctx context.Context, userID common.ID, url common.URL) error {
+func (r *UsersRepo) GetByFeedToken(ctx context.Context, token string) (repoUser, error) {
+	return r.get(ctx, "feed_token", token)
+}
+
+// GetWithDigest returns the users that get digest reports.
+func (r *UsersRepo) GetWithDigest(ctx context.Context) ([]repoUser, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
 	defer cancel()
 
-	q := `update users set webhook_url = $1 where id = $2`
-	_, err := r.db.Exec(ctx, q, url.String(), userID.String())
-	return err
+	q := fmt.Sprintf(`select %s from users where digest_frequency <> 'off' order by id`, userColumns)
+
+	rows, _ := r.db.Query(ctx, q)
+	return pgx.CollectRows(rows, pgx.RowToStructByName[repoUser])
+}
+
+func (r *UsersRepo) update(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout)
+	defer cancel()
+
+	res, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UsersRepo) SetTimezone(ctx context.Context, userID common.ID, timezone string) error {
+	q := `update users set timezone = $2 where id = $1`
+	return r.update(ctx, q, userID, timezone)
+}
+
+func (r *UsersRepo) SetDigest(ctx context.Context, user repoUser) error {
+	var channelID *string
+	if user.DigestChannelID != "" {
+		channelID = &user.DigestChannelID
+	}
+
+	q := `
+    update
+      users
+    set
+      digest_frequency = $2,
+      digest_weekday = $3,
+      digest_hour = $4,
+      digest_days = $5,
+      digest_channel_id = $6
+    where
+      id = $1`
+	return r.update(ctx, q, user.ID, user.DigestFrequency, user.DigestWeekday, user.DigestHour, user.DigestDays, channelID)
+}
+
+func (r *UsersRepo) SetDigestSent(ctx context.Context, userID common.ID, sentAt time.Time) error {
+	q := `update users set digest_sent_at = $2 where id = $1`
+	return r.update(ctx, q, userID, sentAt)
+}
+
+func (r *UsersRepo) SetQuietHours(ctx context.Context, userID common.ID, enabled bool, start int, end int) error {
+	q := `update users set quiet_hours_enabled = $2, quiet_hours_start = $3, quiet_hours_end = $4 where id = $1`
+	return r.update(ctx, q, userID, enabled, start, end)
+}
+
+func (r *UsersRepo) SetFeedToken(ctx context.Context, userID common.ID, token string) error {
+	q := `update users set feed_token = $2 where id = $1`
+	return r.update(ctx, q, userID, token)
 }
@@ -11,5 +11,15 @@ type repoUser struct {
 	CreatedAt          time.Time `db:"created_at"`
 	IdentityProvider   string    `db:"identity_provider"`
 	IdentityProviderID string    `db:"identity_provider_id"`
-	WebhookURL         string    `db:"webhook_url"`
+	Timezone           string    `db:"timezone"`
+	DigestFrequency    string    `db:"digest_frequency"`
+	DigestWeekday      int       `db:"digest_weekday"`
+	DigestHour         int       `db:"digest_hour"`
+	DigestDays         int       `db:"digest_days"`
+	DigestChannelID    string    `db:"digest_channel_id"`
+	DigestSentAt       time.Time `db:"digest_sent_at"`
+	QuietHoursEnabled  bool      `db:"quiet_hours_enabled"`
+	QuietHoursStart    int       `db:"quiet_hours_start"`
+	QuietHoursEnd      int       `db:"quiet_hours_end"`
+	FeedToken          string    `db:"feed_token"`
 }
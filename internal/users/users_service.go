@@ -2,13 +2,23 @@ package users
 
 import (
 	"context"
+
+	"github.com/germandv/domainator/internal/common"
 )
 
+const feedTokenLength = 32
+
 type Service interface {
 	Save(ctx context.Context, req SaveReq) (User, error)
 	GetByEmail(ctx context.Context, req GetByEmailReq) (User, error)
 	GetByID(ctx context.Context, req GetByIDReq) (User, error)
-	SetWebhookURL(ctx context.Context, req SetWebhookReq) error
+	GetWithDigest(ctx context.Context, req GetWithDigestReq) ([]User, error)
+	SetTimezone(ctx context.Context, req SetTimezoneReq) (User, error)
+	SetDigest(ctx context.Context, req SetDigestReq) (User, error)
+	SetDigestSent(ctx context.Context, req SetDigestSentReq) error
+	SetQuietHours(ctx context.Context, req SetQuietHoursReq) (User, error)
+	GetByFeedToken(ctx context.Context, req GetByFeedTokenReq) (User, error)
+	RotateFeedToken(ctx context.Context, req RotateFeedTokenReq) (User, error)
 }
 
 type UsersService struct {
@@ -58,6 +68,80 @@ func (s *UsersService) GetByID(ctx context.Context, req GetByIDReq) (User, error
 	return u, nil
 }
 
-func (s *UsersService) SetWebhookURL(ctx context.Context, req SetWebhookReq) error {
-	return s.repo.SetWebhookURL(ctx, req.UserID, req.URL)
+// GetWithDigest returns the users that get digest reports, whether they are due or not.
+func (s *UsersService) GetWithDigest(ctx context.Context, req GetWithDigestReq) ([]User, error) {
+	users, err := s.repo.GetWithDigest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	us := make([]User, len(users))
+	for i, user := range users {
+		u, err := repoToServiceAdapter(user)
+		if err != nil {
+			return nil, err
+		}
+		us[i] = u
+	}
+
+	return us, nil
+}
+
+func (s *UsersService) SetTimezone(ctx context.Context, req SetTimezoneReq) (User, error) {
+	err := s.repo.SetTimezone(ctx, req.UserID, req.Timezone.String())
+	if err != nil {
+		return User{}, err
+	}
+	return s.GetByID(ctx, GetByIDReq{UserID: req.UserID})
+}
+
+func (s *UsersService) SetDigest(ctx context.Context, req SetDigestReq) (User, error) {
+	err := s.repo.SetDigest(ctx, repoUser{
+		ID:              req.UserID.String(),
+		DigestFrequency: string(req.Digest.Frequency),
+		DigestWeekday:   int(req.Digest.Weekday),
+		DigestHour:      req.Digest.Hour,
+		DigestDays:      req.Digest.Days,
+		DigestChannelID: req.Digest.ChannelID.String(),
+	})
+	if err != nil {
+		return User{}, err
+	}
+	return s.GetByID(ctx, GetByIDReq{UserID: req.UserID})
+}
+
+func (s *UsersService) SetDigestSent(ctx context.Context, req SetDigestSentReq) error {
+	return s.repo.SetDigestSent(ctx, req.UserID, req.SentAt)
+}
+
+func (s *UsersService) SetQuietHours(ctx context.Context, req SetQuietHoursReq) (User, error) {
+	q := req.QuietHours
+	err := s.repo.SetQuietHours(ctx, req.UserID, q.Enabled, q.Start, q.End)
+	if err != nil {
+		return User{}, err
+	}
+	return s.GetByID(ctx, GetByIDReq{UserID: req.UserID})
+}
+
+// GetByFeedToken returns the user the token of a feed URL belongs to.
+func (s *UsersService) GetByFeedToken(ctx context.Context, req GetByFeedTokenReq) (User, error) {
+	if len(req.Token) != feedTokenLength {
+		return User{}, ErrNotFound
+	}
+
+	user, err := s.repo.GetByFeedToken(ctx, req.Token)
+	if err != nil {
+		return User{}, err
+	}
+
+	return repoToServiceAdapter(user)
+}
+
+// RotateFeedToken gives the user a new feed token, the URLs with the previous one stop working.
+func (s *UsersService) RotateFeedToken(ctx context.Context, req RotateFeedTokenReq) (User, error) {
+	err := s.repo.SetFeedToken(ctx, req.UserID, common.GenerateRandomString(feedTokenLength))
+	if err != nil {
+		return User{}, err
+	}
+	return s.GetByID(ctx, GetByIDReq{UserID: req.UserID})
 }
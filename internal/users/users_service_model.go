@@ -22,9 +22,34 @@ type GetByIDReq struct {
 	UserID common.ID
 }
 
-type SetWebhookReq struct {
+type GetWithDigestReq struct{}
+
+type SetTimezoneReq struct {
+	UserID   common.ID
+	Timezone Timezone
+}
+
+type SetDigestReq struct {
+	UserID common.ID
+	Digest Digest
+}
+
+type SetQuietHoursReq struct {
+	UserID     common.ID
+	QuietHours QuietHours
+}
+
+type GetByFeedTokenReq struct {
+	Token string
+}
+
+type RotateFeedTokenReq struct {
+	UserID common.ID
+}
+
+type SetDigestSentReq struct {
 	UserID common.ID
-	URL    common.URL
+	SentAt time.Time
 }
 
 type User struct {
@@ -35,7 +60,11 @@ type User struct {
 	IdentityProvider   string
 	IdentityProviderID string
 	CreatedAt          time.Time
-	WebhookURL         common.URL
+	Timezone           Timezone
+	QuietHours         QuietHours
+	Digest             Digest
+	// FeedToken is the secret in the URLs of the feeds of the user, empty until they ask for one.
+	FeedToken string
 }
 
 func New(name string, email Email, identityProvider string, identityProviderID string, avatar string) User {
@@ -47,6 +76,8 @@ func New(name string, email Email, identityProvider string, identityProviderID s
 		IdentityProvider:   identityProvider,
 		IdentityProviderID: identityProviderID,
 		CreatedAt:          time.Now(),
+		Timezone:           Timezone{},
+		Digest:             Digest{Frequency: FrequencyOff, Weekday: time.Monday, Hour: 8, Days: DefaultDigestDays},
 	}
 }
 
@@ -60,7 +91,17 @@ func serviceToRepoAdapter(user User) repoUser {
 		IdentityProvider:   user.IdentityProvider,
 		IdentityProviderID: user.IdentityProviderID,
 		CreatedAt:          user.CreatedAt,
-		WebhookURL:         user.WebhookURL.String(),
+		Timezone:           user.Timezone.String(),
+		DigestFrequency:    string(user.Digest.Frequency),
+		DigestWeekday:      int(user.Digest.Weekday),
+		DigestHour:         user.Digest.Hour,
+		DigestDays:         user.Digest.Days,
+		DigestChannelID:    user.Digest.ChannelID.String(),
+		DigestSentAt:       user.Digest.LastSent,
+		QuietHoursEnabled:  user.QuietHours.Enabled,
+		QuietHoursStart:    user.QuietHours.Start,
+		QuietHoursEnd:      user.QuietHours.End,
+		FeedToken:          user.FeedToken,
 	}
 }
 
@@ -76,6 +117,24 @@ func repoToServiceAdapter(user repoUser) (User, error) {
 		return User{}, err
 	}
 
+	parsedTimezone, err := ParseTimezone(user.Timezone)
+	if err != nil {
+		return User{}, err
+	}
+
+	parsedFrequency, err := ParseFrequency(user.DigestFrequency)
+	if err != nil {
+		return User{}, err
+	}
+
+	parsedChannelID := common.ID{}
+	if user.DigestChannelID != "" {
+		parsedChannelID, err = common.ParseID(user.DigestChannelID)
+		if err != nil {
+			return User{}, err
+		}
+	}
+
 	u := User{
 		ID:                 parsedID,
 		Name:               user.Name,
@@ -84,14 +143,21 @@ func repoToServiceAdapter(user repoUser) (User, error) {
 		IdentityProvider:   user.IdentityProvider,
 		IdentityProviderID: user.IdentityProviderID,
 		CreatedAt:          user.CreatedAt,
-	}
-
-	if user.WebhookURL != "" {
-		parsedWebhookURL, err := common.ParseURL(user.WebhookURL)
-		if err != nil {
-			return User{}, err
-		}
-		u.WebhookURL = parsedWebhookURL
+		Timezone:           parsedTimezone,
+		QuietHours: QuietHours{
+			Enabled: user.QuietHoursEnabled,
+			Start:   user.QuietHoursStart,
+			End:     user.QuietHoursEnd,
+		},
+		Digest: Digest{
+			Frequency: parsedFrequency,
+			Weekday:   time.Weekday(user.DigestWeekday),
+			Hour:      user.DigestHour,
+			Days:      user.DigestDays,
+			ChannelID: parsedChannelID,
+			LastSent:  user.DigestSentAt,
+		},
+		FeedToken: user.FeedToken,
 	}
 
 	return u, nil
@@ -0,0 +1,269 @@
+// Package webpush sends Web Push messages: payloads are encrypted for the browser (RFC 8291)
+// and requests are authenticated with VAPID (RFC 8292).
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germandv/domainator/internal/keys"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// recordSize is the size of the only record of the encrypted payload.
+	recordSize = 4096
+	// MaxPayload is the largest payload push services accept, they take bodies of up to 4096 bytes:
+	// an 86 bytes header, then the payload with its delimiter and the 16 bytes tag.
+	MaxPayload = 4096 - 86 - 1 - 16
+	tokenTTL   = 12 * time.Hour
+)
+
+var (
+	// ErrGone means the subscription expired or the user revoked it, it should be deleted.
+	ErrGone                = errors.New("push subscription is gone")
+	ErrInvalidSubscription = errors.New("invalid push subscription")
+	ErrPayloadTooLarge     = fmt.Errorf("push payload cannot be larger than %d bytes", MaxPayload)
+)
+
+// Subscription is what PushManager.subscribe() returns in the browser.
+type Subscription struct {
+	Endpoint string
+	// P256dh is the public key of the browser, base64url encoded.
+	P256dh string
+	// Auth is the authentication secret of the browser, base64url encoded.
+	Auth string
+}
+
+// Validate checks that the endpoint is an HTTPS URL and that the keys can be decoded.
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return ErrInvalidSubscription
+	}
+	_, _, err = s.decodeKeys()
+	return err
+}
+
+func (s Subscription) decodeKeys() (*ecdh.PublicKey, []byte, error) {
+	p256dh, err := decodeBase64(s.P256dh)
+	if err != nil {
+		return nil, nil, ErrInvalidSubscription
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, nil, ErrInvalidSubscription
+	}
+
+	auth, err := decodeBase64(s.Auth)
+	if err != nil || len(auth) != 16 {
+		return nil, nil, ErrInvalidSubscription
+	}
+
+	return uaPublic, auth, nil
+}
+
+// browsers encode keys with base64url, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// VAPID identifies the application server to push services.
+type VAPID struct {
+	privateKey *ecdsa.PrivateKey
+	// PublicKey is handed to the browser as the applicationServerKey.
+	PublicKey string
+	// Subject is a mailto: or https: URL push services can use to contact the operator.
+	Subject string
+}
+
+// NewVAPID decodes the private key generated with keys.NewVAPID, it returns nil if the key is empty.
+func NewVAPID(privateKey string, subject string) (*VAPID, error) {
+	privateKey = strings.TrimSpace(privateKey)
+	if privateKey == "" {
+		return nil, nil
+	}
+
+	key, err := keys.DecodeVAPID(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VAPID{privateKey: key, PublicKey: keys.VAPIDPublic(key), Subject: subject}, nil
+}
+
+// Enabled reports whether Web Push is configured.
+func (v *VAPID) Enabled() bool {
+	return v != nil
+}
+
+// header returns the Authorization header for the push service of the endpoint.
+func (v *VAPID) header(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", ErrInvalidSubscription
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(tokenTTL).Unix(),
+		"sub": v.Subject,
+	})
+	signed, err := token.SignedString(v.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("vapid t=%s, k=%s", signed, v.PublicKey), nil
+}
+
+// Message is a push message, Urgency is one of very-low, low, normal or high.
+type Message struct {
+	Payload []byte
+	TTL     time.Duration
+	Urgency string
+	// Topic replaces a pending message with the same topic, up to 32 base64url characters.
+	Topic string
+}
+
+// Send encrypts the message for the subscription and delivers it to its push service,
+// ErrGone is returned when the push service reports the subscription doesn't exist anymore.
+func (v *VAPID) Send(ctx context.Context, client *http.Client, sub Subscription, msg Message) (int, error) {
+	body, err := Encrypt(sub, msg.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	auth, err := v.header(sub.Endpoint, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(msg.TTL.Seconds())))
+	if msg.Urgency != "" {
+		req.Header.Set("Urgency", msg.Urgency)
+	}
+	if msg.Topic != "" {
+		req.Header.Set("Topic", msg.Topic)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1000))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return resp.StatusCode, ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return resp.StatusCode, fmt.Errorf("error (%d) sending push message: %s", resp.StatusCode, respBody)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Encrypt encrypts the payload for the subscription with the aes128gcm content encoding (RFC 8188 and RFC 8291).
+func Encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+
+	uaPublic, authSecret, err := sub.decodeKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	salt := make([]byte, 16)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, nonce, err := deriveKeys(asPrivate, uaPublic, asPublic, uaPublic.Bytes(), authSecret, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// A single record, the 0x02 delimiter marks it as the last one.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// deriveKeys derives the content encryption key and the nonce from the shared secret of the
+// private key with the peer key, uaPublic and asPublic are the keys of the browser and the server.
+func deriveKeys(private *ecdh.PrivateKey, peer *ecdh.PublicKey, asPublic []byte, uaPublic []byte, authSecret []byte, salt []byte) ([]byte, []byte, error) {
+	ecdhSecret, err := private.ECDH(peer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := make([]byte, 32)
+	_, err = io.ReadFull(hkdf.New(sha256.New, ecdhSecret, authSecret, keyInfo), ikm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek := make([]byte, 16)
+	_, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, 12)
+	_, err = io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, nonce, nil
+}
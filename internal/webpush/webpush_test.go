@@ -0,0 +1,138 @@
+package webpush
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germandv/domainator/internal/keys"
+)
+
+// browser generates the keys of a subscription, as a browser does.
+func browser(t *testing.T, endpoint string) (Subscription, *ecdh.PrivateKey, []byte) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+
+	sub := Subscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.URLEncoding.EncodeToString(auth),
+	}
+	return sub, uaPrivate, auth
+}
+
+// decrypt does what the browser does with the body of a push message.
+func decrypt(t *testing.T, body []byte, uaPrivate *ecdh.PrivateKey, auth []byte) []byte {
+	salt := body[:16]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != recordSize {
+		t.Fatalf("Expected record size %d, got %d", recordSize, rs)
+	}
+	idLen := int(body[20])
+	asPublic := body[21 : 21+idLen]
+
+	peer, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, nonce, err := deriveKeys(uaPrivate, peer, asPublic, uaPrivate.PublicKey().Bytes(), auth, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("Could not decrypt: %s", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatalf("Expected the last record delimiter, got %x", plaintext[len(plaintext)-1])
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	sub, uaPrivate, auth := browser(t, "https://push.example.com/send/1")
+	payload := []byte(`{"title":"example.com: expired"}`)
+
+	body, err := Encrypt(sub, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := decrypt(t, body, uaPrivate, auth)
+	if string(got) != string(payload) {
+		t.Errorf("Expected %q, got %q", payload, got)
+	}
+
+	_, err = Encrypt(sub, make([]byte, MaxPayload+1))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+
+	_, err = Encrypt(Subscription{Endpoint: sub.Endpoint, P256dh: "nope", Auth: sub.Auth}, payload)
+	if !errors.Is(err, ErrInvalidSubscription) {
+		t.Errorf("Expected ErrInvalidSubscription, got %v", err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+
+	priv, publ, err := keys.NewVAPID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vapid, err := NewVAPID(priv, "mailto:ops@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gone := false
+	var auth string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") != "3600" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		if gone {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	sub, _, _ := browser(t, ts.URL+"/send/1")
+	msg := Message{Payload: []byte("{}"), TTL: time.Hour, Urgency: "high"}
+
+	status, err := vapid.Send(context.Background(), ts.Client(), sub, msg)
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d and %v", status, err)
+	}
+	if !strings.HasPrefix(auth, "vapid t=") || !strings.HasSuffix(auth, ", k="+publ) {
+		t.Errorf("Unexpected Authorization header %q", auth)
+	}
+
+	gone = true
+	_, err = vapid.Send(context.Background(), ts.Client(), sub, msg)
+	if !errors.Is(err, ErrGone) {
+		t.Errorf("Expected ErrGone, got %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+alter table if exists users add column if not exists email_notifications boolean not null default false;
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists email_notifications;
@@ -0,0 +1,7 @@
+alter table if exists users add column if not exists json_webhook_url text;
+alter table if exists users add column if not exists json_webhook_secret text;
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists json_webhook_url;
+alter table if exists users drop column if exists json_webhook_secret;
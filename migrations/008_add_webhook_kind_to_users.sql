@@ -0,0 +1,5 @@
+alter table if exists users add column if not exists webhook_kind text not null default '';
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists webhook_kind;
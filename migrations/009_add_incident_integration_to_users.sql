@@ -0,0 +1,7 @@
+alter table if exists users add column if not exists incident_provider text not null default '';
+alter table if exists users add column if not exists incident_key text;
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists incident_key;
+alter table if exists users drop column if exists incident_provider;
@@ -0,0 +1,7 @@
+alter table if exists certificates add column if not exists tags text[] not null default '{}';
+alter table if exists certificates_deleted add column if not exists tags text[] not null default '{}';
+
+---- create above / drop below ----
+
+alter table if exists certificates_deleted drop column if exists tags;
+alter table if exists certificates drop column if exists tags;
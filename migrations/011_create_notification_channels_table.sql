@@ -0,0 +1,77 @@
+create table if not exists notification_channels (
+  id uuid not null primary key,
+  user_id uuid not null,
+  kind text not null,
+  name text not null,
+  target text not null,
+  secret text not null default '',
+  enabled boolean not null default true,
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists notification_channels_user_id_idx on notification_channels (user_id);
+
+create table if not exists notification_rules (
+  id uuid not null primary key,
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  domain_pattern text not null default '',
+  tag text not null default '',
+  min_severity text not null default 'info',
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists notification_rules_channel_id_idx on notification_rules (channel_id);
+
+-- Move the single destinations stored in users into channels.
+insert into notification_channels (id, user_id, kind, name, target)
+  select gen_random_uuid(), id, case webhook_kind when '' then 'chat' else webhook_kind end, 'Chat', webhook_url
+  from users where coalesce(webhook_url, '') <> '';
+
+insert into notification_channels (id, user_id, kind, name, target)
+  select gen_random_uuid(), id, 'email', 'Email', email
+  from users where email_notifications;
+
+insert into notification_channels (id, user_id, kind, name, target, secret)
+  select gen_random_uuid(), id, 'webhook', 'JSON Webhook', json_webhook_url, coalesce(json_webhook_secret, '')
+  from users where coalesce(json_webhook_url, '') <> '';
+
+insert into notification_channels (id, user_id, kind, name, target)
+  select gen_random_uuid(), id, incident_provider, case incident_provider when 'pagerduty' then 'PagerDuty' else 'Opsgenie' end, incident_key
+  from users where incident_provider <> '' and coalesce(incident_key, '') <> '';
+
+alter table if exists users drop column if exists webhook_url;
+alter table if exists users drop column if exists webhook_kind;
+alter table if exists users drop column if exists email_notifications;
+alter table if exists users drop column if exists json_webhook_url;
+alter table if exists users drop column if exists json_webhook_secret;
+alter table if exists users drop column if exists incident_provider;
+alter table if exists users drop column if exists incident_key;
+
+---- create above / drop below ----
+
+alter table if exists users add column if not exists webhook_url text;
+alter table if exists users add column if not exists webhook_kind text not null default '';
+alter table if exists users add column if not exists email_notifications boolean not null default false;
+alter table if exists users add column if not exists json_webhook_url text;
+alter table if exists users add column if not exists json_webhook_secret text;
+alter table if exists users add column if not exists incident_provider text not null default '';
+alter table if exists users add column if not exists incident_key text;
+
+update users u set webhook_url = c.target, webhook_kind = case c.kind when 'chat' then '' else c.kind end
+  from notification_channels c
+  where c.user_id = u.id and c.kind in ('chat', 'slack', 'discord', 'teams', 'mattermost');
+
+update users u set email_notifications = true
+  from notification_channels c
+  where c.user_id = u.id and c.kind = 'email' and c.enabled;
+
+update users u set json_webhook_url = c.target, json_webhook_secret = c.secret
+  from notification_channels c
+  where c.user_id = u.id and c.kind = 'webhook';
+
+update users u set incident_provider = c.kind, incident_key = c.target
+  from notification_channels c
+  where c.user_id = u.id and c.kind in ('pagerduty', 'opsgenie');
+
+drop table if exists notification_rules;
+drop table if exists notification_channels;
@@ -0,0 +1,11 @@
+create table if not exists notification_templates (
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  event text not null,
+  body text not null,
+  updated_at timestamp not null default (now() at time zone 'utc'),
+  primary key (channel_id, event)
+);
+
+---- create above / drop below ----
+
+drop table if exists notification_templates;
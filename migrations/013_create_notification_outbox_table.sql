@@ -0,0 +1,35 @@
+create table if not exists notification_outbox (
+  id uuid not null primary key,
+  user_id uuid not null,
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  payload jsonb not null,
+  state text not null default 'pending',
+  attempts integer not null default 0,
+  next_attempt_at timestamp not null default (now() at time zone 'utc'),
+  created_at timestamp not null default (now() at time zone 'utc'),
+  updated_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists notification_outbox_pending_idx on notification_outbox (next_attempt_at) where state = 'pending';
+
+create table if not exists notification_deliveries (
+  id uuid not null primary key,
+  message_id uuid not null references notification_outbox (id) on delete cascade,
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  attempt integer not null,
+  ok boolean not null,
+  status_code integer not null default 0,
+  response text not null default '',
+  error text not null default '',
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists notification_deliveries_message_id_idx on notification_deliveries (message_id);
+
+alter table notification_channels add column if not exists consecutive_failures integer not null default 0;
+
+---- create above / drop below ----
+
+alter table notification_channels drop column if exists consecutive_failures;
+drop table if exists notification_deliveries;
+drop table if exists notification_outbox;
@@ -0,0 +1,15 @@
+create table if not exists certificate_events (
+  id uuid not null primary key,
+  user_id uuid not null,
+  certificate_id uuid not null,
+  domain text not null,
+  kind text not null,
+  detail text not null default '',
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists certificate_events_user_id_created_at_idx on certificate_events (user_id, created_at);
+
+---- create above / drop below ----
+
+drop table if exists certificate_events;
@@ -0,0 +1,17 @@
+alter table if exists users add column if not exists timezone text not null default 'UTC';
+alter table if exists users add column if not exists digest_frequency text not null default 'off';
+alter table if exists users add column if not exists digest_weekday integer not null default 1;
+alter table if exists users add column if not exists digest_hour integer not null default 8;
+alter table if exists users add column if not exists digest_days integer not null default 14;
+alter table if exists users add column if not exists digest_channel_id uuid references notification_channels (id) on delete set null;
+alter table if exists users add column if not exists digest_sent_at timestamp;
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists digest_sent_at;
+alter table if exists users drop column if exists digest_channel_id;
+alter table if exists users drop column if exists digest_days;
+alter table if exists users drop column if exists digest_hour;
+alter table if exists users drop column if exists digest_weekday;
+alter table if exists users drop column if exists digest_frequency;
+alter table if exists users drop column if exists timezone;
@@ -0,0 +1,9 @@
+alter table if exists users add column if not exists quiet_hours_enabled boolean not null default false;
+alter table if exists users add column if not exists quiet_hours_start integer not null default 1320;
+alter table if exists users add column if not exists quiet_hours_end integer not null default 420;
+
+---- create above / drop below ----
+
+alter table if exists users drop column if exists quiet_hours_end;
+alter table if exists users drop column if exists quiet_hours_start;
+alter table if exists users drop column if exists quiet_hours_enabled;
@@ -0,0 +1,48 @@
+create table if not exists escalation_policies (
+  id uuid not null primary key,
+  user_id uuid not null,
+  name text not null,
+  min_severity text not null default 'warning',
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists escalation_policies_user_id_idx on escalation_policies (user_id);
+
+create table if not exists escalation_steps (
+  id uuid not null primary key,
+  policy_id uuid not null references escalation_policies (id) on delete cascade,
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  delay_hours integer not null default 0,
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists escalation_steps_policy_id_idx on escalation_steps (policy_id);
+
+create table if not exists incidents (
+  id uuid not null primary key,
+  user_id uuid not null,
+  policy_id uuid not null references escalation_policies (id) on delete cascade,
+  certificate_id uuid not null,
+  domain text not null,
+  status text not null,
+  payload jsonb not null,
+  state text not null default 'open',
+  step integer not null default 0,
+  next_escalation_at timestamp,
+  acknowledged_by text not null default '',
+  acknowledged_via text not null default '',
+  acknowledged_at timestamp,
+  opened_at timestamp not null default (now() at time zone 'utc'),
+  resolved_at timestamp,
+  updated_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists incidents_user_id_idx on incidents (user_id, opened_at desc);
+create index if not exists incidents_due_idx on incidents (next_escalation_at) where state in ('open', 'snoozed');
+create unique index if not exists incidents_unresolved_idx on incidents (policy_id, certificate_id) where state <> 'resolved';
+
+---- create above / drop below ----
+
+drop table if exists incidents;
+drop table if exists escalation_steps;
+drop table if exists escalation_policies;
@@ -0,0 +1,29 @@
+create table if not exists slack_installations (
+  channel_id uuid not null primary key references notification_channels (id) on delete cascade,
+  user_id uuid not null,
+  team_id text not null,
+  team_name text not null,
+  slack_user_id text not null,
+  bot_user_id text not null,
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists slack_installations_team_id_idx on slack_installations (team_id, slack_user_id);
+
+create table if not exists slack_threads (
+  channel_id uuid not null references notification_channels (id) on delete cascade,
+  thread_key text not null,
+  ts text not null,
+  updated_at timestamp not null default (now() at time zone 'utc'),
+  primary key (channel_id, thread_key)
+);
+
+alter table notification_rules add column if not exists destination text not null default '';
+alter table notification_rules add column if not exists destination_name text not null default '';
+
+---- create above / drop below ----
+
+alter table notification_rules drop column if exists destination_name;
+alter table notification_rules drop column if exists destination;
+drop table if exists slack_threads;
+drop table if exists slack_installations;
@@ -0,0 +1,21 @@
+alter table if exists certificates add column if not exists snoozed_until timestamp;
+
+create table if not exists slack_users (
+  team_id text not null,
+  slack_user_id text not null,
+  user_id uuid not null,
+  created_at timestamp not null default (now() at time zone 'utc'),
+  primary key (team_id, slack_user_id)
+);
+
+insert into slack_users (team_id, slack_user_id, user_id)
+  select distinct on (team_id, slack_user_id) team_id, slack_user_id, user_id
+  from slack_installations
+  where slack_user_id <> ''
+  order by team_id, slack_user_id, created_at desc
+  on conflict do nothing;
+
+---- create above / drop below ----
+
+drop table if exists slack_users;
+alter table if exists certificates drop column if exists snoozed_until;
@@ -0,0 +1,15 @@
+create table if not exists push_subscriptions (
+  id uuid not null primary key,
+  user_id uuid not null,
+  endpoint text not null unique,
+  p256dh text not null,
+  auth text not null,
+  user_agent text not null default '',
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists push_subscriptions_user_id_idx on push_subscriptions (user_id);
+
+---- create above / drop below ----
+
+drop table if exists push_subscriptions;
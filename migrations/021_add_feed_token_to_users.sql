@@ -0,0 +1,8 @@
+alter table if exists users add column if not exists feed_token text;
+
+create unique index if not exists users_feed_token_idx on users (feed_token);
+
+---- create above / drop below ----
+
+drop index if exists users_feed_token_idx;
+alter table if exists users drop column if exists feed_token;
@@ -0,0 +1,16 @@
+create table if not exists api_tokens (
+  id uuid not null primary key,
+  user_id uuid not null,
+  name text not null,
+  prefix text not null,
+  token_hash text not null unique,
+  scopes text[] not null,
+  last_used_at timestamp,
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists api_tokens_user_id_idx on api_tokens (user_id);
+
+---- create above / drop below ----
+
+drop table if exists api_tokens;
@@ -0,0 +1,24 @@
+alter table if exists certificates alter column expires_at drop not null;
+alter table if exists certificates_deleted alter column expires_at drop not null;
+
+create table if not exists probe_jobs (
+  id uuid not null primary key,
+  certificate_id uuid not null references certificates (id) on delete cascade,
+  user_id uuid not null,
+  kind text not null,
+  attempts integer not null default 0,
+  run_at timestamp not null default (now() at time zone 'utc'),
+  created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create unique index if not exists probe_jobs_certificate_id_idx on probe_jobs (certificate_id);
+
+create index if not exists probe_jobs_run_at_idx on probe_jobs (run_at);
+
+---- create above / drop below ----
+
+drop table if exists probe_jobs;
+delete from certificates where expires_at is null;
+delete from certificates_deleted where expires_at is null;
+alter table if exists certificates_deleted alter column expires_at set not null;
+alter table if exists certificates alter column expires_at set not null;
@@ -0,0 +1,9 @@
+alter table if exists notification_channels add column if not exists confirmed boolean not null default true;
+
+-- Email channels have to be confirmed from the address, except those sending to the verified email of their owner.
+update notification_channels c set confirmed = false, enabled = false
+where c.kind = 'email' and not exists (select 1 from users u where u.id = c.user_id and lower(u.email) = lower(c.target));
+
+---- create above / drop below ----
+
+alter table if exists notification_channels drop column if exists confirmed;
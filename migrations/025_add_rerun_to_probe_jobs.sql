@@ -0,0 +1,6 @@
+-- Set when the cert is refreshed while its job runs, so it's checked again once the job is done.
+alter table if exists probe_jobs add column if not exists rerun boolean not null default false;
+
+---- create above / drop below ----
+
+alter table if exists probe_jobs drop column if exists rerun;
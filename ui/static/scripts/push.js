@@ -0,0 +1,67 @@
+// Subscribes this browser to push notifications, the service worker shows them.
+;(async () => {
+  const subscribeBtn = document.querySelector("#push_subscribe")
+  const unsubscribeBtn = document.querySelector("#push_unsubscribe")
+  if (!subscribeBtn || !("serviceWorker" in navigator) || !("PushManager" in window)) {
+    return
+  }
+
+  const registration = await navigator.serviceWorker.register("/static/scripts/sw.js")
+  let subscription = await registration.pushManager.getSubscription()
+  toggle(subscription)
+
+  subscribeBtn.addEventListener("click", async () => {
+    const permission = await Notification.requestPermission()
+    if (permission !== "granted") {
+      notify("Notifications are blocked in this browser.")
+      return
+    }
+
+    try {
+      subscription = await registration.pushManager.subscribe({
+        userVisibleOnly: true,
+        applicationServerKey: decodeKey(subscribeBtn.dataset.vapidKey),
+      })
+      await send("POST", subscription.toJSON())
+      // Reload to show the channel of browser notifications.
+      window.location.reload()
+    } catch (err) {
+      notify(err.message)
+    }
+  })
+
+  unsubscribeBtn.addEventListener("click", async () => {
+    if (!subscription) return
+    try {
+      await send("DELETE", { endpoint: subscription.endpoint })
+      await subscription.unsubscribe()
+      subscription = null
+      toggle(subscription)
+      notify("Browser notifications disabled.")
+    } catch (err) {
+      notify(err.message)
+    }
+  })
+
+  function toggle(sub) {
+    subscribeBtn.hidden = !!sub
+    unsubscribeBtn.hidden = !sub
+  }
+
+  async function send(method, body) {
+    const res = await fetch("/push/subscription", {
+      method,
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(body),
+    })
+    if (!res.ok) {
+      throw new Error(await res.text())
+    }
+  }
+
+  // decodeKey turns the base64url VAPID public key into the bytes PushManager expects.
+  function decodeKey(key) {
+    const padded = (key + "=".repeat((4 - (key.length % 4)) % 4)).replace(/-/g, "+").replace(/_/g, "/")
+    return Uint8Array.from(atob(padded), (c) => c.charCodeAt(0))
+  }
+})()
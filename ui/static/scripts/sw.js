@@ -0,0 +1,32 @@
+// Service worker showing the push notifications sent by Domainator.
+self.addEventListener("push", (ev) => {
+  const data = ev.data ? ev.data.json() : {}
+  const title = data.title || "Domainator"
+
+  ev.waitUntil(
+    self.registration.showNotification(title, {
+      body: data.body || "",
+      // A new notification about a domain replaces the previous one.
+      tag: data.tag || "domainator",
+      renotify: true,
+      icon: "/static/images/favicon.png",
+      data: { url: data.url || "/dashboard" },
+    }),
+  )
+})
+
+self.addEventListener("notificationclick", (ev) => {
+  ev.notification.close()
+  const url = ev.notification.data?.url || "/dashboard"
+
+  ev.waitUntil(
+    self.clients.matchAll({ type: "window", includeUncontrolled: true }).then((clients) => {
+      for (const client of clients) {
+        if (client.url === url && "focus" in client) {
+          return client.focus()
+        }
+      }
+      return self.clients.openWindow(url)
+    }),
+  )
+})